@@ -2,27 +2,43 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	// Profile time zones are checked against the embedded database, so
+	// the server does not depend on the host's.
+	_ "time/tzdata"
 
 	firebase "firebase.google.com/go"
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
 
+	"chat-app/backend/internal/infrastructure/breached"
 	infraFirestore "chat-app/backend/internal/infrastructure/firestore"
+	"chat-app/backend/internal/infrastructure/notify"
+	"chat-app/backend/internal/infrastructure/oidc"
+	"chat-app/backend/internal/interfaces/clientip"
 	"chat-app/backend/internal/interfaces/grpc/handlers"
 	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/interfaces/httpapi"
 	"chat-app/backend/internal/usecases"
 )
 
 func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, x-grpc-web, x-user-agent")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Max-Age", "86400")
@@ -57,11 +73,210 @@ func main() {
 
 	log.Println("Firestore client initialized successfully")
 
+	if migrated, err := infraFirestore.MigrateUsernameKeys(ctx, client); err != nil {
+		log.Fatalf("error migrating username keys: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Added username keys to %d users", migrated)
+	}
+
 	messageRepo := infraFirestore.NewMessageRepository(client)
 	userRepo := infraFirestore.NewUserRepository(client)
-	authUseCase := usecases.NewAuthUseCase(userRepo)
-	messageUseCase := usecases.NewMessageUseCase(messageRepo, authUseCase)
-	chatHandler := handlers.NewChatHandler(messageUseCase, authUseCase)
+	blobRepo := infraFirestore.NewBlobRepository(client)
+	roomRepo := infraFirestore.NewRoomRepository(client)
+	scheduledRepo := infraFirestore.NewScheduledMessageRepository(client)
+	webhookRepo := infraFirestore.NewWebhookRepository(client)
+	webhookDeliveryRepo := infraFirestore.NewWebhookDeliveryRepository(client)
+	apiKeyRepo := infraFirestore.NewAPIKeyRepository(client)
+	incomingWebhookRepo := infraFirestore.NewIncomingWebhookRepository(client)
+	refreshTokenRepo := infraFirestore.NewRefreshTokenRepository(client)
+
+	authConfig := usecases.DefaultAuthConfig()
+	if ttl, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_TTL")); err == nil && ttl > 0 {
+		authConfig.AccessTokenTTL = ttl
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL")); err == nil && ttl > 0 {
+		authConfig.RefreshTokenTTL = ttl
+	}
+	if issuer := os.Getenv("TWO_FACTOR_ISSUER"); issuer != "" {
+		authConfig.TwoFactorIssuer = issuer
+	}
+	if length, err := strconv.Atoi(os.Getenv("USERNAME_MIN_LENGTH")); err == nil && length > 0 {
+		authConfig.UsernamePolicy.MinLength = length
+	}
+	if length, err := strconv.Atoi(os.Getenv("USERNAME_MAX_LENGTH")); err == nil && length > 0 {
+		authConfig.UsernamePolicy.MaxLength = length
+	}
+	if allow, err := strconv.ParseBool(os.Getenv("USERNAME_ALLOW_UNICODE")); err == nil {
+		authConfig.UsernamePolicy.AllowUnicode = allow
+	}
+	if reserved := os.Getenv("RESERVED_USERNAMES"); reserved != "" {
+		authConfig.UsernamePolicy.Reserved = strings.Split(reserved, ",")
+	}
+	if length, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil && length > 0 {
+		authConfig.PasswordPolicy.MinLength = length
+	}
+	if bits, err := strconv.ParseFloat(os.Getenv("PASSWORD_MIN_ENTROPY_BITS"), 64); err == nil && bits >= 0 {
+		authConfig.PasswordPolicy.MinEntropyBits = bits
+	}
+	if cost, err := strconv.Atoi(os.Getenv("BCRYPT_COST")); err == nil {
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			log.Fatalf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		authConfig.PasswordPolicy.BcryptCost = cost
+	}
+	authOptions := []usecases.AuthUseCaseOption{
+		usecases.WithAPIKeyRepository(apiKeyRepo),
+		usecases.WithRefreshTokenRepository(refreshTokenRepo),
+		usecases.WithTwoFactor(infraFirestore.NewLoginChallengeRepository(client)),
+		usecases.WithAuthConfig(authConfig),
+	}
+	if dir := os.Getenv("BREACHED_PASSWORDS_DIR"); dir != "" {
+		list, err := breached.Open(dir)
+		if err != nil {
+			log.Fatalf("invalid BREACHED_PASSWORDS_DIR: %v", err)
+		}
+		authOptions = append(authOptions, usecases.WithBreachedPasswords(list))
+	}
+	if value := os.Getenv("TOKEN_SIGNING_KEYS"); value != "" {
+		keys, err := usecases.ParseSigningKeys(value)
+		if err != nil || len(keys) == 0 {
+			log.Fatalf("invalid TOKEN_SIGNING_KEYS: %v", err)
+		}
+		signer, err := usecases.NewTokenSigner(keys[0], keys[1:]...)
+		if err != nil {
+			log.Fatalf("invalid TOKEN_SIGNING_KEYS: %v", err)
+		}
+
+		revocationConfig := usecases.DefaultRevocationListConfig()
+		revocationConfig.Lookback = authConfig.AccessTokenTTL
+		revocations := usecases.NewRevocationList(infraFirestore.NewRevokedSessionRepository(client), revocationConfig)
+		revocations.Start(ctx)
+
+		authOptions = append(authOptions, usecases.WithTokenSigner(signer, revocations))
+		log.Printf("Issuing signed access tokens with key %s", keys[0].ID)
+	}
+	var adminUsernames []string
+	for _, username := range strings.Split(os.Getenv("ADMIN_USERNAMES"), ",") {
+		if username = strings.TrimSpace(username); username != "" {
+			adminUsernames = append(adminUsernames, username)
+		}
+	}
+
+	var notifier usecases.Notifier
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		notifier = notify.NewSMTPNotifier(notify.SMTPConfig{
+			Addr:     addr,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+			ResetURL: resetURL,
+		})
+	} else if path := os.Getenv("PASSWORD_RESET_LOG"); path != "" {
+		out, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			log.Fatalf("failed to open PASSWORD_RESET_LOG: %v", err)
+		}
+		defer out.Close()
+		notifier = notify.NewLogNotifier(out, resetURL)
+		log.Printf("Writing password reset tokens to %s", path)
+	}
+	if notifier != nil {
+		authOptions = append(authOptions, usecases.WithPasswordReset(infraFirestore.NewPasswordResetRepository(client), notifier))
+	}
+
+	guardConfig := usecases.DefaultLoginGuardConfig()
+	guardConfig.AdminUsernames = adminUsernames
+	if threshold, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_THRESHOLD")); err == nil && threshold > 0 {
+		guardConfig.LockoutThreshold = threshold
+	}
+	if duration, err := time.ParseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION")); err == nil && duration > 0 {
+		guardConfig.LockoutDuration = duration
+	}
+	if limit, err := strconv.Atoi(os.Getenv("REGISTRATIONS_PER_ADDRESS")); err == nil && limit > 0 {
+		guardConfig.RegistrationsPerAddress = limit
+	}
+	loginGuard := usecases.NewLoginGuard(infraFirestore.NewLoginAttemptRepository(client), userRepo, notifier, guardConfig)
+	authOptions = append(authOptions, usecases.WithLoginGuard(loginGuard))
+	authUseCase := usecases.NewAuthUseCase(userRepo, authOptions...)
+
+	botConfig := usecases.DefaultBotConfig()
+	botConfig.AdminUsernames = adminUsernames
+	botConfig.UsernamePolicy = authConfig.UsernamePolicy
+	botUseCase := usecases.NewBotUseCase(userRepo, apiKeyRepo, botConfig)
+
+	roomConfig := usecases.DefaultRoomConfig()
+	roomConfig.AdminUsernames = adminUsernames
+	if limit, err := strconv.Atoi(os.Getenv("ROOM_PIN_LIMIT")); err == nil && limit > 0 {
+		roomConfig.DefaultPinLimit = limit
+	}
+	roomUseCase := usecases.NewRoomUseCase(roomRepo, messageRepo, roomConfig)
+
+	linkUnfurler := usecases.NewLinkUnfurler(messageRepo, usecases.DefaultLinkUnfurlerConfig())
+	commands := usecases.NewCommandRegistry(roomUseCase)
+	messageUseCase := usecases.NewMessageUseCase(messageRepo, authUseCase,
+		usecases.WithLinkUnfurler(linkUnfurler),
+		usecases.WithRoomUseCase(roomUseCase),
+		usecases.WithCommandRegistry(commands),
+	)
+
+	attachmentProcessor := usecases.NewAttachmentProcessor(messageRepo, blobRepo, usecases.DefaultAttachmentProcessorConfig())
+	attachmentProcessor.Start(ctx)
+
+	messageReaper := usecases.NewMessageReaper(messageRepo, blobRepo, usecases.DefaultMessageReaperConfig())
+	messageReaper.Start(ctx)
+
+	scheduledUseCase := usecases.NewScheduledMessageUseCase(scheduledRepo, messageUseCase, usecases.DefaultSchedulerConfig())
+	scheduledUseCase.Start(ctx)
+
+	webhookUseCase := usecases.NewWebhookUseCase(webhookRepo, webhookDeliveryRepo, roomUseCase)
+	webhookDispatcher := usecases.NewWebhookDispatcher(webhookRepo, webhookDeliveryRepo, messageRepo, usecases.DefaultWebhookDispatcherConfig())
+	webhookDispatcher.Start(ctx)
+
+	incomingConfig := usecases.DefaultIncomingWebhookConfig()
+	if name := os.Getenv("INCOMING_WEBHOOK_DISPLAY_NAME"); name != "" {
+		incomingConfig.DefaultDisplayName = name
+	}
+	incomingWebhookUseCase := usecases.NewIncomingWebhookUseCase(incomingWebhookRepo, messageUseCase, roomUseCase, incomingConfig)
+
+	commands.Register(usecases.NewMeCommand(messageUseCase))
+	commands.Register(usecases.NewTopicCommand(roomUseCase, messageUseCase))
+	commands.Register(usecases.NewInviteCommand(userRepo, messageUseCase))
+	commands.Register(usecases.NewMuteCommand(roomUseCase, userRepo, time.Hour))
+	commands.Register(usecases.NewUnmuteCommand(roomUseCase, userRepo))
+	commands.Register(usecases.NewRemindCommand(scheduledUseCase))
+
+	pollConfig := usecases.DefaultPollConfig()
+	if value := os.Getenv("POLL_VOTER_KEY_SECRET"); value != "" {
+		secret, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(secret) < 32 {
+			log.Fatal("invalid POLL_VOTER_KEY_SECRET: want at least 32 base64 encoded bytes")
+		}
+		pollConfig.VoterKeySecret = secret
+	}
+	pollUseCase := usecases.NewPollUseCase(messageRepo, messageUseCase, pollConfig)
+	pollUseCase.Start(ctx)
+
+	typingUseCase := usecases.NewTypingUseCase(usecases.DefaultTypingConfig())
+
+	profileUseCase := usecases.NewProfileUseCase(infraFirestore.NewProfileRepository(client), userRepo, usecases.DefaultProfileConfig())
+
+	trustedProxies, err := clientip.ParseTrustedProxies(strings.Split(os.Getenv("TRUSTED_PROXIES"), ","))
+	if err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	chatHandler := handlers.NewChatHandler(messageUseCase, authUseCase,
+		handlers.WithRoomUseCase(roomUseCase),
+		handlers.WithScheduledMessageUseCase(scheduledUseCase),
+		handlers.WithPollUseCase(pollUseCase),
+		handlers.WithBotUseCase(botUseCase),
+		handlers.WithWebhookUseCase(webhookUseCase),
+		handlers.WithIncomingWebhookUseCase(incomingWebhookUseCase),
+		handlers.WithTypingUseCase(typingUseCase),
+		handlers.WithProfileUseCase(profileUseCase),
+		handlers.WithTrustedProxies(trustedProxies),
+	)
 
 	grpcServer := grpc.NewServer()
 	pb.RegisterChatServiceServer(grpcServer, chatHandler)
@@ -73,26 +288,74 @@ func main() {
 		grpcweb.WithAllowedRequestHeaders([]string{"*"}),
 	)
 
+	mux := http.NewServeMux()
+	httpapi.NewIncomingWebhookHandler(incomingWebhookUseCase).Register(mux)
+	httpapi.NewGateway(chatHandler, trustedProxies).Register(mux)
+	httpapi.NewWebSocketHandler(chatHandler, httpapi.DefaultWebSocketConfig()).Register(mux)
+	httpapi.NewSSEHandler(chatHandler, httpapi.DefaultSSEConfig()).Register(mux)
+	if issuerURL := os.Getenv("OIDC_ISSUER_URL"); issuerURL != "" {
+		provider, err := oidc.Discover(ctx, oidc.Config{
+			IssuerURL:    issuerURL,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		}, &http.Client{Timeout: 10 * time.Second})
+		if err != nil {
+			log.Fatalf("error discovering OIDC provider: %v", err)
+		}
+
+		ssoConfig := usecases.DefaultSSOConfig()
+		if claims := os.Getenv("OIDC_USERNAME_CLAIMS"); claims != "" {
+			ssoConfig.UsernameClaims = strings.Split(claims, ",")
+		}
+		oidcConfig := httpapi.DefaultOIDCConfig()
+		oidcConfig.FrontendURL = os.Getenv("OIDC_FRONTEND_URL")
+		oidcConfig.TrustedProxies = trustedProxies
+		ssoUseCase := usecases.NewSSOUseCase(provider, authUseCase, ssoConfig)
+		httpapi.NewOIDCHandler(ssoUseCase, oidcConfig).Register(mux)
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Chat App gRPC Server is running"))
+	})
+
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if wrappedGrpc.IsGrpcWebRequest(r) || wrappedGrpc.IsAcceptableGrpcCorsRequest(r) {
 			wrappedGrpc.ServeHTTP(w, r)
 			return
 		}
 
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("Chat App gRPC Server is running"))
+		mux.ServeHTTP(w, r)
 	})
 
 	corsHandler := enableCORS(handler)
 
+	// Cancelling serverCtx ends open streams, so Shutdown does not wait for
+	// them until its timeout.
+	serverCtx, stopStreams := context.WithCancel(ctx)
 	httpServer := &http.Server{
-		Addr:    "0.0.0.0:" + port,
-		Handler: h2c.NewHandler(corsHandler, &http2.Server{}),
+		Addr:        "0.0.0.0:" + port,
+		Handler:     h2c.NewHandler(corsHandler, &http2.Server{}),
+		BaseContext: func(net.Listener) context.Context { return serverCtx },
 	}
 
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+
+		log.Println("Shutting down server")
+		stopStreams()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v", err)
+		}
+	}()
+
 	log.Printf("Server listening on 0.0.0.0:%s", port)
-	if err := httpServer.ListenAndServe(); err != nil {
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
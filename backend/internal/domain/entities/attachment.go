@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+type AttachmentStatus string
+
+const (
+	AttachmentPending   AttachmentStatus = "pending"
+	AttachmentProcessed AttachmentStatus = "processed"
+	AttachmentFailed    AttachmentStatus = "failed"
+)
+
+type Attachment struct {
+	ID              string           `firestore:"id" json:"id"`
+	FileName        string           `firestore:"file_name" json:"file_name"`
+	ContentType     string           `firestore:"content_type" json:"content_type"`
+	Size            int64            `firestore:"size" json:"size"`
+	Width           int              `firestore:"width" json:"width"`
+	Height          int              `firestore:"height" json:"height"`
+	ThumbnailID     string           `firestore:"thumbnail_id" json:"thumbnail_id"`
+	ThumbnailWidth  int              `firestore:"thumbnail_width" json:"thumbnail_width"`
+	ThumbnailHeight int              `firestore:"thumbnail_height" json:"thumbnail_height"`
+	Status          AttachmentStatus `firestore:"status" json:"status"`
+	Attempts        int              `firestore:"attempts" json:"attempts"`
+	Error           string           `firestore:"error" json:"error,omitempty"`
+	CreatedAt       time.Time        `firestore:"created_at" json:"created_at"`
+}
+
+func (a *Attachment) IsImage() bool {
+	switch a.ContentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+type Blob struct {
+	ID          string    `firestore:"id"`
+	ContentType string    `firestore:"content_type"`
+	Data        []byte    `firestore:"data"`
+	CreatedAt   time.Time `firestore:"created_at"`
+}
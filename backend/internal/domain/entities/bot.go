@@ -0,0 +1,44 @@
+package entities
+
+import "time"
+
+// BotScopes limits what a bot account can do. Empty lists allow everything.
+type BotScopes struct {
+	RoomIDs []string `firestore:"room_ids" json:"room_ids,omitempty"`
+	RPCs    []string `firestore:"rpcs" json:"rpcs,omitempty"`
+}
+
+func (s *BotScopes) Allows(rpc, roomID string) bool {
+	if s == nil {
+		return true
+	}
+	return containsOrEmpty(s.RPCs, rpc) && (roomID == "" || containsOrEmpty(s.RoomIDs, roomID))
+}
+
+func containsOrEmpty(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKey struct {
+	ID         string    `firestore:"id"`
+	UserID     string    `firestore:"user_id"`
+	KeyHash    string    `firestore:"key_hash"`
+	CreatedBy  string    `firestore:"created_by"`
+	CreatedAt  time.Time `firestore:"created_at"`
+	LastUsedAt time.Time `firestore:"last_used_at"`
+	Revoked    bool      `firestore:"revoked"`
+}
+
+// IssuedAPIKey carries the plaintext secret of a newly created key.
+type IssuedAPIKey struct {
+	Key    *APIKey
+	Secret string
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+// IncomingWebhook lets an external tool post into a room without a user
+// session. Only the hash of its token is stored.
+type IncomingWebhook struct {
+	ID          string    `firestore:"id"`
+	RoomID      string    `firestore:"room_id"`
+	TokenHash   string    `firestore:"token_hash"`
+	DisplayName string    `firestore:"display_name"`
+	CreatedBy   string    `firestore:"created_by"`
+	CreatedAt   time.Time `firestore:"created_at"`
+}
+
+// IncomingWebhookPayload accepts a plain {"text": "..."} body as well as the
+// text/attachments subset of Slack's incoming webhook format.
+type IncomingWebhookPayload struct {
+	Text        string                      `json:"text"`
+	Username    string                      `json:"username"`
+	Attachments []IncomingWebhookAttachment `json:"attachments"`
+}
+
+type IncomingWebhookAttachment struct {
+	Fallback  string                 `json:"fallback"`
+	Pretext   string                 `json:"pretext"`
+	Title     string                 `json:"title"`
+	TitleLink string                 `json:"title_link"`
+	Text      string                 `json:"text"`
+	Fields    []IncomingWebhookField `json:"fields"`
+}
+
+type IncomingWebhookField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
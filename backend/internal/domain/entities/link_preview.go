@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+type LinkPreview struct {
+	URL         string    `firestore:"url" json:"url"`
+	Title       string    `firestore:"title" json:"title"`
+	Description string    `firestore:"description" json:"description"`
+	ImageURL    string    `firestore:"image_url" json:"image_url"`
+	SiteName    string    `firestore:"site_name" json:"site_name"`
+	FetchedAt   time.Time `firestore:"fetched_at" json:"fetched_at"`
+}
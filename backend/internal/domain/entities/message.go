@@ -2,11 +2,61 @@ package entities
 
 import "time"
 
+type MessageEvent string
+
+const (
+	MessageCreated  MessageEvent = "created"
+	MessageUpdated  MessageEvent = "updated"
+	MessagePinned   MessageEvent = "pinned"
+	MessageUnpinned MessageEvent = "unpinned"
+	MessageDeleted  MessageEvent = "deleted"
+	MessageExpired  MessageEvent = "expired"
+	PollUpdated     MessageEvent = "poll_updated"
+	PollClosed      MessageEvent = "poll_closed"
+)
+
 type Message struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Username  string    `json:"username"`
-	Content   string    `json:"content"`
-	RoomID    string    `json:"room_id"`
-	Timestamp time.Time `json:"timestamp"`
+	ID           string         `json:"id"`
+	UserID       string         `json:"user_id"`
+	Username     string         `json:"username"`
+	Content      string         `json:"content"`
+	RoomID       string         `json:"room_id"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Attachments  []*Attachment  `json:"attachments,omitempty"`
+	LinkPreviews []*LinkPreview `json:"link_previews,omitempty"`
+	Pinned       bool           `json:"pinned,omitempty"`
+	PinnedBy     string         `json:"pinned_by,omitempty"`
+	PinnedAt     time.Time      `json:"pinned_at,omitempty"`
+	ExpiresAt    time.Time      `json:"expires_at,omitempty"`
+	Poll         *Poll          `json:"poll,omitempty"`
+	Ephemeral    bool           `json:"ephemeral,omitempty"`
+	IsBot        bool           `json:"is_bot,omitempty"`
+	Event        MessageEvent   `json:"event,omitempty"`
+}
+
+type MessageCreateParams struct {
+	UserID   string
+	Username string
+	Content  string
+	RoomID   string
+	TTL      time.Duration
+	Poll     *Poll
+	IsBot    bool
+	// Unverified marks a UserID the client named without a session token.
+	// Such senders cannot run commands or post in rooms with moderators
+	// or mutes.
+	Unverified bool
+}
+
+func (m *Message) IsExpired(now time.Time) bool {
+	return !m.ExpiresAt.IsZero() && !now.Before(m.ExpiresAt)
+}
+
+func (m *Message) HasPendingAttachments() bool {
+	for _, attachment := range m.Attachments {
+		if attachment.Status == AttachmentPending {
+			return true
+		}
+	}
+	return false
 }
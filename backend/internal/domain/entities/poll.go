@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+type PollOption struct {
+	ID       string   `firestore:"id" json:"id"`
+	Text     string   `firestore:"text" json:"text"`
+	Votes    int      `firestore:"votes" json:"votes"`
+	VoterIDs []string `firestore:"voter_ids" json:"voter_ids,omitempty"`
+}
+
+type Poll struct {
+	Question       string        `firestore:"question" json:"question"`
+	Options        []*PollOption `firestore:"options" json:"options"`
+	MultipleChoice bool          `firestore:"multiple_choice" json:"multiple_choice"`
+	Anonymous      bool          `firestore:"anonymous" json:"anonymous"`
+	ClosesAt       time.Time     `firestore:"closes_at,omitempty" json:"closes_at"`
+	Closed         bool          `firestore:"closed" json:"closed"`
+	TotalVoters    int           `firestore:"total_voters" json:"total_voters"`
+}
+
+func (p *Poll) IsOpen(now time.Time) bool {
+	return !p.Closed && (p.ClosesAt.IsZero() || now.Before(p.ClosesAt))
+}
+
+func (p *Poll) Option(id string) *PollOption {
+	for _, option := range p.Options {
+		if option.ID == id {
+			return option
+		}
+	}
+	return nil
+}
+
+type PollParams struct {
+	Question       string
+	Options        []string
+	MultipleChoice bool
+	Anonymous      bool
+	ClosesAt       time.Time
+}
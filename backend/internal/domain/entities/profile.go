@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Profile is what a user tells others about themselves. Users who never
+// set one have none.
+type Profile struct {
+	UserID      string `firestore:"user_id"`
+	Username    string `firestore:"username"`
+	DisplayName string `firestore:"display_name"`
+	AvatarURL   string `firestore:"avatar_url"`
+	Bio         string `firestore:"bio"`
+	StatusText  string `firestore:"status_text"`
+	// StatusExpiresAt is when StatusText is cleared. Zero keeps it until
+	// the user changes it.
+	StatusExpiresAt time.Time `firestore:"status_expires_at"`
+	// Timezone is an IANA time zone name such as "Europe/Paris".
+	Timezone  string    `firestore:"timezone"`
+	UpdatedAt time.Time `firestore:"updated_at"`
+}
+
+// ClearExpiredStatus drops the status once it has expired.
+func (p *Profile) ClearExpiredStatus(now time.Time) {
+	if !p.StatusExpiresAt.IsZero() && !now.Before(p.StatusExpiresAt) {
+		p.StatusText = ""
+		p.StatusExpiresAt = time.Time{}
+	}
+}
+
+// ProfileUpdate changes the fields that are not nil. StatusTTL is how long
+// a new StatusText lasts; zero keeps it until it is changed.
+type ProfileUpdate struct {
+	DisplayName *string
+	AvatarURL   *string
+	Bio         *string
+	StatusText  *string
+	StatusTTL   time.Duration
+	Timezone    *string
+}
@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+type Room struct {
+	ID                string               `firestore:"id"`
+	Topic             string               `firestore:"topic"`
+	ModeratorIDs      []string             `firestore:"moderator_ids"`
+	PinLimit          int                  `firestore:"pin_limit"`
+	DefaultTTLSeconds int                  `firestore:"default_ttl_seconds"`
+	MutedUntil        map[string]time.Time `firestore:"muted_until"`
+	CreatedAt         time.Time            `firestore:"created_at"`
+	UpdatedAt         time.Time            `firestore:"updated_at"`
+}
+
+func (r *Room) IsModerator(userID string) bool {
+	for _, id := range r.ModeratorIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) IsMuted(userID string, now time.Time) bool {
+	until, ok := r.MutedUntil[userID]
+	return ok && now.Before(until)
+}
+
+func (r *Room) DefaultMessageTTL() time.Duration {
+	return time.Duration(r.DefaultTTLSeconds) * time.Second
+}
+
+type RoomSettings struct {
+	RoomID       string
+	PinLimit     int
+	ModeratorIDs []string
+	DefaultTTL   *time.Duration
+	Topic        *string
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+type ScheduledMessageStatus string
+
+const (
+	ScheduledPending   ScheduledMessageStatus = "pending"
+	ScheduledSending   ScheduledMessageStatus = "sending"
+	ScheduledSent      ScheduledMessageStatus = "sent"
+	ScheduledCancelled ScheduledMessageStatus = "cancelled"
+	ScheduledFailed    ScheduledMessageStatus = "failed"
+)
+
+type ScheduledMessage struct {
+	ID         string                 `firestore:"id"`
+	UserID     string                 `firestore:"user_id"`
+	Username   string                 `firestore:"username"`
+	RoomID     string                 `firestore:"room_id"`
+	Content    string                 `firestore:"content"`
+	TTLSeconds int                    `firestore:"ttl_seconds"`
+	SendAt     time.Time              `firestore:"send_at"`
+	Status     ScheduledMessageStatus `firestore:"status"`
+	LeaseUntil time.Time              `firestore:"lease_until"`
+	Attempts   int                    `firestore:"attempts"`
+	MessageID  string                 `firestore:"message_id"`
+	Error      string                 `firestore:"error"`
+	CreatedAt  time.Time              `firestore:"created_at"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// TypingEvent is a short-lived indicator that is never stored. Clients hide
+// it at ExpiresAt unless it is refreshed.
+type TypingEvent struct {
+	RoomID    string
+	UserID    string
+	Username  string
+	Typing    bool
+	ExpiresAt time.Time
+}
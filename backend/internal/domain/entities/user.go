@@ -5,10 +5,66 @@ import (
 )
 
 type User struct {
-	ID           string    `firestore:"id"`
-	Username     string    `firestore:"username"`
-	PasswordHash string    `firestore:"password_hash"`
-	CreatedAt    time.Time `firestore:"created_at"`
+	ID           string     `firestore:"id"`
+	Username     string     `firestore:"username"`
+	UsernameKey  string     `firestore:"username_key"`
+	PasswordHash string     `firestore:"password_hash"`
+	CreatedAt    time.Time  `firestore:"created_at"`
+	IsBot        bool       `firestore:"is_bot"`
+	BotScopes    *BotScopes `firestore:"bot_scopes"`
+	CreatedBy    string     `firestore:"created_by"`
+	// Email receives password reset links. It is optional.
+	Email     string     `firestore:"email"`
+	TwoFactor *TwoFactor `firestore:"two_factor"`
+	// SSOIssuer and SSOSubject link the user to an account at an OpenID
+	// Connect provider. Such users have no password.
+	SSOIssuer  string `firestore:"sso_issuer"`
+	SSOSubject string `firestore:"sso_subject"`
+}
+
+// TwoFactor holds a user's TOTP settings. It is created by enrollment and
+// takes effect once a first code confirms it.
+type TwoFactor struct {
+	Secret  string `firestore:"secret"`
+	Enabled bool   `firestore:"enabled"`
+	// LastCounter is the time step of the last accepted code, so that a
+	// code cannot be used twice.
+	LastCounter int64 `firestore:"last_counter"`
+	// RecoveryCodes are hashed; each can replace a code once.
+	RecoveryCodes []string  `firestore:"recovery_codes"`
+	EnabledAt     time.Time `firestore:"enabled_at"`
+}
+
+// TwoFactorEnrollment is shown to the user once: the secret for their
+// authenticator app and the recovery codes for when it is lost.
+type TwoFactorEnrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+func (u *User) TwoFactorEnabled() bool {
+	return u.TwoFactor != nil && u.TwoFactor.Enabled
+}
+
+// ExternalIdentity is a user as an identity provider vouches for them.
+// Username is the name to give them if they sign in for the first time.
+type ExternalIdentity struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Email    string
+}
+
+// SSORequest is a sign-in that was sent to the identity provider. The
+// client keeps it until the provider redirects back, so that the callback
+// can be matched to it.
+type SSORequest struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+	DeviceLabel  string
+	AuthURL      string
 }
 
 type UserCreateParams struct {
@@ -16,8 +72,97 @@ type UserCreateParams struct {
 	Password string
 }
 
+// AuthToken is an access token. ID identifies its session to the owner
+// without revealing the token; every access token issued by refreshing the
+// session shares it.
 type AuthToken struct {
 	Token     string    `firestore:"token"`
+	ID        string    `firestore:"id"`
+	UserID    string    `firestore:"user_id"`
+	ExpiresAt time.Time `firestore:"expires_at"`
+	// SessionExpiresAt is when the session's refresh token runs out. It is
+	// zero for sessions that cannot be refreshed.
+	SessionExpiresAt time.Time `firestore:"session_expires_at"`
+	CreatedAt        time.Time `firestore:"created_at"`
+	LastUsedAt       time.Time `firestore:"last_used_at"`
+	DeviceLabel      string    `firestore:"device_label"`
+	IPAddress        string    `firestore:"ip_address"`
+	UserAgent        string    `firestore:"user_agent"`
+
+	// RefreshToken is only set when the token is issued.
+	RefreshToken string `firestore:"-"`
+	// Challenge is returned by Login instead of a token for users with
+	// two-factor authentication. The login is completed by exchanging it
+	// with a code before ExpiresAt.
+	Challenge string `firestore:"-"`
+}
+
+// SessionExpiry is when the session ends unless it is refreshed.
+func (t *AuthToken) SessionExpiry() time.Time {
+	if t.SessionExpiresAt.After(t.ExpiresAt) {
+		return t.SessionExpiresAt
+	}
+	return t.ExpiresAt
+}
+
+// RefreshToken is stored by hash. Each use replaces it with a new token of
+// the same session, so a token presented twice has leaked and the whole
+// session is revoked.
+type RefreshToken struct {
+	TokenHash        string    `firestore:"token_hash"`
+	SessionID        string    `firestore:"session_id"`
+	UserID           string    `firestore:"user_id"`
+	DeviceLabel      string    `firestore:"device_label"`
+	SessionCreatedAt time.Time `firestore:"session_created_at"`
+	CreatedAt        time.Time `firestore:"created_at"`
+	ExpiresAt        time.Time `firestore:"expires_at"`
+	UsedAt           time.Time `firestore:"used_at"`
+}
+
+// SessionMetadata describes the client a session is created for.
+type SessionMetadata struct {
+	DeviceLabel string
+	IPAddress   string
+	UserAgent   string
+}
+
+// RevokedSession lists a session whose signed access tokens must no longer
+// be accepted. It is kept until the last of them has expired.
+type RevokedSession struct {
+	SessionID string    `firestore:"session_id"`
 	UserID    string    `firestore:"user_id"`
+	RevokedAt time.Time `firestore:"revoked_at"`
 	ExpiresAt time.Time `firestore:"expires_at"`
 }
+
+// PasswordResetToken lets the holder set a new password once. Like refresh
+// tokens it is stored by hash.
+type PasswordResetToken struct {
+	TokenHash string    `firestore:"token_hash"`
+	UserID    string    `firestore:"user_id"`
+	CreatedAt time.Time `firestore:"created_at"`
+	ExpiresAt time.Time `firestore:"expires_at"`
+	UsedAt    time.Time `firestore:"used_at"`
+}
+
+// LoginChallenge is a login whose password was right and that still needs a
+// two-factor code. It is stored by hash.
+type LoginChallenge struct {
+	TokenHash   string    `firestore:"token_hash"`
+	UserID      string    `firestore:"user_id"`
+	DeviceLabel string    `firestore:"device_label"`
+	IPAddress   string    `firestore:"ip_address"`
+	UserAgent   string    `firestore:"user_agent"`
+	CreatedAt   time.Time `firestore:"created_at"`
+	ExpiresAt   time.Time `firestore:"expires_at"`
+	Attempts    int       `firestore:"attempts"`
+}
+
+// LoginAttempts counts the failed logins of a username or a client address
+// since FirstFailureAt. Key is a hash of what is counted.
+type LoginAttempts struct {
+	Key            string    `firestore:"key"`
+	Failures       int       `firestore:"failures"`
+	FirstFailureAt time.Time `firestore:"first_failure_at"`
+	LastFailureAt  time.Time `firestore:"last_failure_at"`
+}
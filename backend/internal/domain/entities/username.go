@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps characters that look like a Latin letter or digit once
+// case is folded onto it, after the skeletons of Unicode TR 39.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j',
+	'ӏ': 'l', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'ԝ': 'w', 'х': 'x',
+	'у': 'y', 'ү': 'y',
+	// Greek
+	'α': 'a', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'υ': 'u',
+	'χ': 'x',
+	// Latin
+	'ı': 'i', 'ɡ': 'g', 'ɑ': 'a',
+	// Digits
+	'0': 'o', '1': 'l',
+}
+
+var confusableSequences = strings.NewReplacer("rn", "m", "vv", "w")
+
+// UsernameKey identifies a username regardless of case, Unicode form and
+// characters that look alike, so that "Alice", "ALICE" and "аlice" with a
+// Cyrillic "а" are the same username.
+func UsernameKey(username string) string {
+	folded := norm.NFKC.String(cases.Fold().String(norm.NFKC.String(username)))
+
+	var key strings.Builder
+	for _, r := range folded {
+		if prototype, ok := confusables[r]; ok {
+			r = prototype
+		}
+		key.WriteRune(r)
+	}
+	return confusableSequences.Replace(key.String())
+}
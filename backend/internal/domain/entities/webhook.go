@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+type Webhook struct {
+	ID        string    `firestore:"id"`
+	RoomID    string    `firestore:"room_id"`
+	URL       string    `firestore:"url"`
+	Secret    string    `firestore:"secret"`
+	Events    []string  `firestore:"events"`
+	CreatedBy string    `firestore:"created_by"`
+	CreatedAt time.Time `firestore:"created_at"`
+}
+
+// Subscribes reports whether the webhook wants the event. No events means
+// every event.
+func (w *Webhook) Subscribes(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliverySending   WebhookDeliveryStatus = "sending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// DeliveryDead marks the dead-letter record of a delivery that ran out
+	// of attempts. Its payload is kept for inspection or manual replay.
+	DeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID            string                `firestore:"id"`
+	WebhookID     string                `firestore:"webhook_id"`
+	RoomID        string                `firestore:"room_id"`
+	MessageID     string                `firestore:"message_id"`
+	Event         string                `firestore:"event"`
+	Payload       string                `firestore:"payload"`
+	Status        WebhookDeliveryStatus `firestore:"status"`
+	Attempts      int                   `firestore:"attempts"`
+	NextAttemptAt time.Time             `firestore:"next_attempt_at"`
+	LeaseUntil    time.Time             `firestore:"lease_until"`
+	ResponseCode  int                   `firestore:"response_code"`
+	Error         string                `firestore:"error"`
+	CreatedAt     time.Time             `firestore:"created_at"`
+	DeliveredAt   time.Time             `firestore:"delivered_at"`
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	GetByID(ctx context.Context, keyID string) (*entities.APIKey, error)
+	Revoke(ctx context.Context, keyID string) error
+	TouchLastUsed(ctx context.Context, keyHash string, at time.Time) error
+}
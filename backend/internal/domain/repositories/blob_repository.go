@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+)
+
+type BlobRepository interface {
+	Put(ctx context.Context, blob *entities.Blob) error
+	Get(ctx context.Context, blobID string) (*entities.Blob, error)
+	// Delete deletes the given blobs; ids without a blob are skipped.
+	Delete(ctx context.Context, blobIDs []string) error
+}
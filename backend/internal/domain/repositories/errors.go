@@ -0,0 +1,14 @@
+package repositories
+
+import "errors"
+
+// Implementations wrap these so that usecases can tell a missing or
+// conflicting record apart from a storage failure, e.g.
+// fmt.Errorf("user %w", ErrNotFound).
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict means the write was rejected because of the record's
+	// current state, such as voting in a closed poll.
+	ErrConflict = errors.New("conflict")
+)
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+)
+
+type IncomingWebhookRepository interface {
+	Create(ctx context.Context, webhook *entities.IncomingWebhook) error
+	GetByID(ctx context.Context, id string) (*entities.IncomingWebhook, error)
+	ListByRoom(ctx context.Context, roomID string) ([]*entities.IncomingWebhook, error)
+	Delete(ctx context.Context, id string) error
+}
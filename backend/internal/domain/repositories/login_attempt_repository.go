@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (*entities.LoginAttempts, error)
+	// RecordFailure counts a failure at the given time and returns the
+	// updated count. The count starts over once its first failure is older
+	// than window.
+	RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*entities.LoginAttempts, error)
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+)
+
+type LoginChallengeRepository interface {
+	Create(ctx context.Context, challenge *entities.LoginChallenge) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.LoginChallenge, error)
+	// RecordAttempt counts a wrong code and returns the number so far.
+	RecordAttempt(ctx context.Context, tokenHash string) (int, error)
+	Delete(ctx context.Context, tokenHash string) error
+}
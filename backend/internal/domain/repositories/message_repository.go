@@ -3,10 +3,25 @@ package repositories
 import (
 	"chat-app/backend/internal/domain/entities"
 	"context"
+	"time"
 )
 
 type MessageRepository interface {
 	Create(ctx context.Context, message *entities.Message) (*entities.Message, error)
+	GetByID(ctx context.Context, messageID string) (*entities.Message, error)
 	GetByRoomID(ctx context.Context, roomID string, limit int) ([]*entities.Message, error)
 	StreamByRoomID(ctx context.Context, roomID string) (<-chan *entities.Message, error)
+	UpdateAttachment(ctx context.Context, messageID string, attachment *entities.Attachment) error
+	ListWithPendingAttachments(ctx context.Context, limit int) ([]*entities.Message, error)
+	UpdateLinkPreviews(ctx context.Context, messageID string, previews []*entities.LinkPreview) error
+	Pin(ctx context.Context, messageID, pinnedBy string, limit int) (*entities.Message, error)
+	Unpin(ctx context.Context, messageID string) (*entities.Message, error)
+	GetPinnedByRoomID(ctx context.Context, roomID string) ([]*entities.Message, error)
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.Message, error)
+	// DeleteVotes deletes the votes recorded on a poll message.
+	DeleteVotes(ctx context.Context, messageID string) error
+	DeleteMessages(ctx context.Context, messageIDs []string) error
+	RecordVote(ctx context.Context, messageID, voterKey, userID string, optionIDs []string, now time.Time) (*entities.Message, error)
+	ClosePoll(ctx context.Context, messageID string) (*entities.Message, error)
+	ListDuePolls(ctx context.Context, now time.Time, limit int) ([]*entities.Message, error)
 }
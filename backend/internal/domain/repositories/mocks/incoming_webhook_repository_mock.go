@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/repositories/incoming_webhook_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIncomingWebhookRepository is a mock of IncomingWebhookRepository interface.
+type MockIncomingWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncomingWebhookRepositoryMockRecorder
+}
+
+// MockIncomingWebhookRepositoryMockRecorder is the mock recorder for MockIncomingWebhookRepository.
+type MockIncomingWebhookRepositoryMockRecorder struct {
+	mock *MockIncomingWebhookRepository
+}
+
+// NewMockIncomingWebhookRepository creates a new mock instance.
+func NewMockIncomingWebhookRepository(ctrl *gomock.Controller) *MockIncomingWebhookRepository {
+	mock := &MockIncomingWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockIncomingWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncomingWebhookRepository) EXPECT() *MockIncomingWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIncomingWebhookRepository) Create(ctx context.Context, webhook *entities.IncomingWebhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) Create(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).Create), ctx, webhook)
+}
+
+// Delete mocks base method.
+func (m *MockIncomingWebhookRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockIncomingWebhookRepository) GetByID(ctx context.Context, id string) (*entities.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entities.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).GetByID), ctx, id)
+}
+
+// ListByRoom mocks base method.
+func (m *MockIncomingWebhookRepository) ListByRoom(ctx context.Context, roomID string) ([]*entities.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByRoom", ctx, roomID)
+	ret0, _ := ret[0].([]*entities.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByRoom indicates an expected call of ListByRoom.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) ListByRoom(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRoom", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).ListByRoom), ctx, roomID)
+}
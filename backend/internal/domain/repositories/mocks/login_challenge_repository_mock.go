@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/repositories/login_challenge_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoginChallengeRepository is a mock of LoginChallengeRepository interface.
+type MockLoginChallengeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginChallengeRepositoryMockRecorder
+}
+
+// MockLoginChallengeRepositoryMockRecorder is the mock recorder for MockLoginChallengeRepository.
+type MockLoginChallengeRepositoryMockRecorder struct {
+	mock *MockLoginChallengeRepository
+}
+
+// NewMockLoginChallengeRepository creates a new mock instance.
+func NewMockLoginChallengeRepository(ctrl *gomock.Controller) *MockLoginChallengeRepository {
+	mock := &MockLoginChallengeRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginChallengeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginChallengeRepository) EXPECT() *MockLoginChallengeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockLoginChallengeRepository) Create(ctx context.Context, challenge *entities.LoginChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, challenge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLoginChallengeRepositoryMockRecorder) Create(ctx, challenge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLoginChallengeRepository)(nil).Create), ctx, challenge)
+}
+
+// Delete mocks base method.
+func (m *MockLoginChallengeRepository) Delete(ctx context.Context, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLoginChallengeRepositoryMockRecorder) Delete(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLoginChallengeRepository)(nil).Delete), ctx, tokenHash)
+}
+
+// GetByHash mocks base method.
+func (m *MockLoginChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.LoginChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*entities.LoginChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockLoginChallengeRepositoryMockRecorder) GetByHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockLoginChallengeRepository)(nil).GetByHash), ctx, tokenHash)
+}
+
+// RecordAttempt mocks base method.
+func (m *MockLoginChallengeRepository) RecordAttempt(ctx context.Context, tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttempt", ctx, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordAttempt indicates an expected call of RecordAttempt.
+func (mr *MockLoginChallengeRepositoryMockRecorder) RecordAttempt(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttempt", reflect.TypeOf((*MockLoginChallengeRepository)(nil).RecordAttempt), ctx, tokenHash)
+}
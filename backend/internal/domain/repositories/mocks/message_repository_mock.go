@@ -8,6 +8,7 @@ import (
 	entities "chat-app/backend/internal/domain/entities"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,21 @@ func (m *MockMessageRepository) EXPECT() *MockMessageRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ClosePoll mocks base method.
+func (m *MockMessageRepository) ClosePoll(ctx context.Context, messageID string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePoll", ctx, messageID)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePoll indicates an expected call of ClosePoll.
+func (mr *MockMessageRepositoryMockRecorder) ClosePoll(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePoll", reflect.TypeOf((*MockMessageRepository)(nil).ClosePoll), ctx, messageID)
+}
+
 // Create mocks base method.
 func (m *MockMessageRepository) Create(ctx context.Context, message *entities.Message) (*entities.Message, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +66,49 @@ func (mr *MockMessageRepositoryMockRecorder) Create(ctx, message interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMessageRepository)(nil).Create), ctx, message)
 }
 
+// DeleteMessages mocks base method.
+func (m *MockMessageRepository) DeleteMessages(ctx context.Context, messageIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessages", ctx, messageIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessages indicates an expected call of DeleteMessages.
+func (mr *MockMessageRepositoryMockRecorder) DeleteMessages(ctx, messageIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessages", reflect.TypeOf((*MockMessageRepository)(nil).DeleteMessages), ctx, messageIDs)
+}
+
+// DeleteVotes mocks base method.
+func (m *MockMessageRepository) DeleteVotes(ctx context.Context, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVotes", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVotes indicates an expected call of DeleteVotes.
+func (mr *MockMessageRepositoryMockRecorder) DeleteVotes(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVotes", reflect.TypeOf((*MockMessageRepository)(nil).DeleteVotes), ctx, messageID)
+}
+
+// GetByID mocks base method.
+func (m *MockMessageRepository) GetByID(ctx context.Context, messageID string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, messageID)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockMessageRepositoryMockRecorder) GetByID(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMessageRepository)(nil).GetByID), ctx, messageID)
+}
+
 // GetByRoomID mocks base method.
 func (m *MockMessageRepository) GetByRoomID(ctx context.Context, roomID string, limit int) ([]*entities.Message, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +124,96 @@ func (mr *MockMessageRepositoryMockRecorder) GetByRoomID(ctx, roomID, limit inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByRoomID", reflect.TypeOf((*MockMessageRepository)(nil).GetByRoomID), ctx, roomID, limit)
 }
 
+// GetPinnedByRoomID mocks base method.
+func (m *MockMessageRepository) GetPinnedByRoomID(ctx context.Context, roomID string) ([]*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPinnedByRoomID", ctx, roomID)
+	ret0, _ := ret[0].([]*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPinnedByRoomID indicates an expected call of GetPinnedByRoomID.
+func (mr *MockMessageRepositoryMockRecorder) GetPinnedByRoomID(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPinnedByRoomID", reflect.TypeOf((*MockMessageRepository)(nil).GetPinnedByRoomID), ctx, roomID)
+}
+
+// ListDuePolls mocks base method.
+func (m *MockMessageRepository) ListDuePolls(ctx context.Context, now time.Time, limit int) ([]*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDuePolls", ctx, now, limit)
+	ret0, _ := ret[0].([]*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDuePolls indicates an expected call of ListDuePolls.
+func (mr *MockMessageRepositoryMockRecorder) ListDuePolls(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDuePolls", reflect.TypeOf((*MockMessageRepository)(nil).ListDuePolls), ctx, now, limit)
+}
+
+// ListExpired mocks base method.
+func (m *MockMessageRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpired", ctx, before, limit)
+	ret0, _ := ret[0].([]*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpired indicates an expected call of ListExpired.
+func (mr *MockMessageRepositoryMockRecorder) ListExpired(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpired", reflect.TypeOf((*MockMessageRepository)(nil).ListExpired), ctx, before, limit)
+}
+
+// ListWithPendingAttachments mocks base method.
+func (m *MockMessageRepository) ListWithPendingAttachments(ctx context.Context, limit int) ([]*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithPendingAttachments", ctx, limit)
+	ret0, _ := ret[0].([]*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithPendingAttachments indicates an expected call of ListWithPendingAttachments.
+func (mr *MockMessageRepositoryMockRecorder) ListWithPendingAttachments(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithPendingAttachments", reflect.TypeOf((*MockMessageRepository)(nil).ListWithPendingAttachments), ctx, limit)
+}
+
+// Pin mocks base method.
+func (m *MockMessageRepository) Pin(ctx context.Context, messageID, pinnedBy string, limit int) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pin", ctx, messageID, pinnedBy, limit)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pin indicates an expected call of Pin.
+func (mr *MockMessageRepositoryMockRecorder) Pin(ctx, messageID, pinnedBy, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pin", reflect.TypeOf((*MockMessageRepository)(nil).Pin), ctx, messageID, pinnedBy, limit)
+}
+
+// RecordVote mocks base method.
+func (m *MockMessageRepository) RecordVote(ctx context.Context, messageID, voterKey, userID string, optionIDs []string, now time.Time) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordVote", ctx, messageID, voterKey, userID, optionIDs, now)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordVote indicates an expected call of RecordVote.
+func (mr *MockMessageRepositoryMockRecorder) RecordVote(ctx, messageID, voterKey, userID, optionIDs, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordVote", reflect.TypeOf((*MockMessageRepository)(nil).RecordVote), ctx, messageID, voterKey, userID, optionIDs, now)
+}
+
 // StreamByRoomID mocks base method.
 func (m *MockMessageRepository) StreamByRoomID(ctx context.Context, roomID string) (<-chan *entities.Message, error) {
 	m.ctrl.T.Helper()
@@ -79,3 +228,46 @@ func (mr *MockMessageRepositoryMockRecorder) StreamByRoomID(ctx, roomID interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamByRoomID", reflect.TypeOf((*MockMessageRepository)(nil).StreamByRoomID), ctx, roomID)
 }
+
+// Unpin mocks base method.
+func (m *MockMessageRepository) Unpin(ctx context.Context, messageID string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unpin", ctx, messageID)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unpin indicates an expected call of Unpin.
+func (mr *MockMessageRepositoryMockRecorder) Unpin(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unpin", reflect.TypeOf((*MockMessageRepository)(nil).Unpin), ctx, messageID)
+}
+
+// UpdateAttachment mocks base method.
+func (m *MockMessageRepository) UpdateAttachment(ctx context.Context, messageID string, attachment *entities.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAttachment", ctx, messageID, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAttachment indicates an expected call of UpdateAttachment.
+func (mr *MockMessageRepositoryMockRecorder) UpdateAttachment(ctx, messageID, attachment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAttachment", reflect.TypeOf((*MockMessageRepository)(nil).UpdateAttachment), ctx, messageID, attachment)
+}
+
+// UpdateLinkPreviews mocks base method.
+func (m *MockMessageRepository) UpdateLinkPreviews(ctx context.Context, messageID string, previews []*entities.LinkPreview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkPreviews", ctx, messageID, previews)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkPreviews indicates an expected call of UpdateLinkPreviews.
+func (mr *MockMessageRepositoryMockRecorder) UpdateLinkPreviews(ctx, messageID, previews interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkPreviews", reflect.TypeOf((*MockMessageRepository)(nil).UpdateLinkPreviews), ctx, messageID, previews)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/repositories/revoked_session_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevokedSessionRepository is a mock of RevokedSessionRepository interface.
+type MockRevokedSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevokedSessionRepositoryMockRecorder
+}
+
+// MockRevokedSessionRepositoryMockRecorder is the mock recorder for MockRevokedSessionRepository.
+type MockRevokedSessionRepositoryMockRecorder struct {
+	mock *MockRevokedSessionRepository
+}
+
+// NewMockRevokedSessionRepository creates a new mock instance.
+func NewMockRevokedSessionRepository(ctrl *gomock.Controller) *MockRevokedSessionRepository {
+	mock := &MockRevokedSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockRevokedSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevokedSessionRepository) EXPECT() *MockRevokedSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRevokedSessionRepository) Create(ctx context.Context, session *entities.RevokedSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRevokedSessionRepositoryMockRecorder) Create(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRevokedSessionRepository)(nil).Create), ctx, session)
+}
+
+// ListRevokedSince mocks base method.
+func (m *MockRevokedSessionRepository) ListRevokedSince(ctx context.Context, since time.Time) ([]*entities.RevokedSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevokedSince", ctx, since)
+	ret0, _ := ret[0].([]*entities.RevokedSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevokedSince indicates an expected call of ListRevokedSince.
+func (mr *MockRevokedSessionRepositoryMockRecorder) ListRevokedSince(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevokedSince", reflect.TypeOf((*MockRevokedSessionRepository)(nil).ListRevokedSince), ctx, since)
+}
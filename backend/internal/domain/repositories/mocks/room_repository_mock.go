@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/repositories/room_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoomRepository is a mock of RoomRepository interface.
+type MockRoomRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomRepositoryMockRecorder
+}
+
+// MockRoomRepositoryMockRecorder is the mock recorder for MockRoomRepository.
+type MockRoomRepositoryMockRecorder struct {
+	mock *MockRoomRepository
+}
+
+// NewMockRoomRepository creates a new mock instance.
+func NewMockRoomRepository(ctrl *gomock.Controller) *MockRoomRepository {
+	mock := &MockRoomRepository{ctrl: ctrl}
+	mock.recorder = &MockRoomRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomRepository) EXPECT() *MockRoomRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetRoom mocks base method.
+func (m *MockRoomRepository) GetRoom(ctx context.Context, roomID string) (*entities.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoom", ctx, roomID)
+	ret0, _ := ret[0].(*entities.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoom indicates an expected call of GetRoom.
+func (mr *MockRoomRepositoryMockRecorder) GetRoom(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoom", reflect.TypeOf((*MockRoomRepository)(nil).GetRoom), ctx, roomID)
+}
+
+// UpdateRoom mocks base method.
+func (m *MockRoomRepository) UpdateRoom(ctx context.Context, roomID string, update func(*entities.Room) error) (*entities.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRoom", ctx, roomID, update)
+	ret0, _ := ret[0].(*entities.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRoom indicates an expected call of UpdateRoom.
+func (mr *MockRoomRepositoryMockRecorder) UpdateRoom(ctx, roomID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRoom", reflect.TypeOf((*MockRoomRepository)(nil).UpdateRoom), ctx, roomID, update)
+}
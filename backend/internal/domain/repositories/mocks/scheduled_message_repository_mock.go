@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/repositories/scheduled_message_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockScheduledMessageRepository is a mock of ScheduledMessageRepository interface.
+type MockScheduledMessageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledMessageRepositoryMockRecorder
+}
+
+// MockScheduledMessageRepositoryMockRecorder is the mock recorder for MockScheduledMessageRepository.
+type MockScheduledMessageRepositoryMockRecorder struct {
+	mock *MockScheduledMessageRepository
+}
+
+// NewMockScheduledMessageRepository creates a new mock instance.
+func NewMockScheduledMessageRepository(ctrl *gomock.Controller) *MockScheduledMessageRepository {
+	mock := &MockScheduledMessageRepository{ctrl: ctrl}
+	mock.recorder = &MockScheduledMessageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledMessageRepository) EXPECT() *MockScheduledMessageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockScheduledMessageRepository) Cancel(ctx context.Context, id string) (*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockScheduledMessageRepositoryMockRecorder) Cancel(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockScheduledMessageRepository)(nil).Cancel), ctx, id)
+}
+
+// Claim mocks base method.
+func (m *MockScheduledMessageRepository) Claim(ctx context.Context, id string, now, leaseUntil time.Time) (*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, id, now, leaseUntil)
+	ret0, _ := ret[0].(*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockScheduledMessageRepositoryMockRecorder) Claim(ctx, id, now, leaseUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockScheduledMessageRepository)(nil).Claim), ctx, id, now, leaseUntil)
+}
+
+// Create mocks base method.
+func (m *MockScheduledMessageRepository) Create(ctx context.Context, scheduled *entities.ScheduledMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, scheduled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScheduledMessageRepositoryMockRecorder) Create(ctx, scheduled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduledMessageRepository)(nil).Create), ctx, scheduled)
+}
+
+// GetByID mocks base method.
+func (m *MockScheduledMessageRepository) GetByID(ctx context.Context, id string) (*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockScheduledMessageRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockScheduledMessageRepository)(nil).GetByID), ctx, id)
+}
+
+// ListByUser mocks base method.
+func (m *MockScheduledMessageRepository) ListByUser(ctx context.Context, userID string) ([]*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockScheduledMessageRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockScheduledMessageRepository)(nil).ListByUser), ctx, userID)
+}
+
+// ListDue mocks base method.
+func (m *MockScheduledMessageRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, now, limit)
+	ret0, _ := ret[0].([]*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockScheduledMessageRepositoryMockRecorder) ListDue(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockScheduledMessageRepository)(nil).ListDue), ctx, now, limit)
+}
+
+// Update mocks base method.
+func (m *MockScheduledMessageRepository) Update(ctx context.Context, scheduled *entities.ScheduledMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, scheduled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScheduledMessageRepositoryMockRecorder) Update(ctx, scheduled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScheduledMessageRepository)(nil).Update), ctx, scheduled)
+}
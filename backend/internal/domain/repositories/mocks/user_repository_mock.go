@@ -8,6 +8,7 @@ import (
 	entities "chat-app/backend/internal/domain/entities"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -78,6 +79,21 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByID(ctx, userID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepository)(nil).GetUserByID), ctx, userID)
 }
 
+// GetUserBySSOIdentity mocks base method.
+func (m *MockUserRepository) GetUserBySSOIdentity(ctx context.Context, issuer, subject string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBySSOIdentity", ctx, issuer, subject)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBySSOIdentity indicates an expected call of GetUserBySSOIdentity.
+func (mr *MockUserRepositoryMockRecorder) GetUserBySSOIdentity(ctx, issuer, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBySSOIdentity", reflect.TypeOf((*MockUserRepository)(nil).GetUserBySSOIdentity), ctx, issuer, subject)
+}
+
 // GetUserByUsername mocks base method.
 func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username string) (*entities.User, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +109,21 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByUsername(ctx, username interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockUserRepository)(nil).GetUserByUsername), ctx, username)
 }
 
+// ListTokens mocks base method.
+func (m *MockUserRepository) ListTokens(ctx context.Context, userID string) ([]*entities.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTokens", ctx, userID)
+	ret0, _ := ret[0].([]*entities.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTokens indicates an expected call of ListTokens.
+func (mr *MockUserRepositoryMockRecorder) ListTokens(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTokens", reflect.TypeOf((*MockUserRepository)(nil).ListTokens), ctx, userID)
+}
+
 // StoreToken mocks base method.
 func (m *MockUserRepository) StoreToken(ctx context.Context, token *entities.AuthToken) error {
 	m.ctrl.T.Helper()
@@ -107,6 +138,104 @@ func (mr *MockUserRepositoryMockRecorder) StoreToken(ctx, token interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreToken", reflect.TypeOf((*MockUserRepository)(nil).StoreToken), ctx, token)
 }
 
+// TouchToken mocks base method.
+func (m *MockUserRepository) TouchToken(ctx context.Context, token string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchToken", ctx, token, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchToken indicates an expected call of TouchToken.
+func (mr *MockUserRepositoryMockRecorder) TouchToken(ctx, token, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchToken", reflect.TypeOf((*MockUserRepository)(nil).TouchToken), ctx, token, at)
+}
+
+// UpdateBotScopes mocks base method.
+func (m *MockUserRepository) UpdateBotScopes(ctx context.Context, userID string, scopes *entities.BotScopes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBotScopes", ctx, userID, scopes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBotScopes indicates an expected call of UpdateBotScopes.
+func (mr *MockUserRepositoryMockRecorder) UpdateBotScopes(ctx, userID, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBotScopes", reflect.TypeOf((*MockUserRepository)(nil).UpdateBotScopes), ctx, userID, scopes)
+}
+
+// UpdateEmail mocks base method.
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, userID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, userID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockUserRepositoryMockRecorder) UpdateEmail(ctx, userID, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserRepository)(nil).UpdateEmail), ctx, userID, email)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, userID, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, userID, passwordHash)
+}
+
+// UpdateTwoFactor mocks base method.
+func (m *MockUserRepository) UpdateTwoFactor(ctx context.Context, userID string, twoFactor *entities.TwoFactor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTwoFactor", ctx, userID, twoFactor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTwoFactor indicates an expected call of UpdateTwoFactor.
+func (mr *MockUserRepositoryMockRecorder) UpdateTwoFactor(ctx, userID, twoFactor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTwoFactor", reflect.TypeOf((*MockUserRepository)(nil).UpdateTwoFactor), ctx, userID, twoFactor)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockUserRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockUserRepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockUserRepository)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseTOTPCounter mocks base method.
+func (m *MockUserRepository) UseTOTPCounter(ctx context.Context, userID string, counter int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPCounter", ctx, userID, counter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTOTPCounter indicates an expected call of UseTOTPCounter.
+func (mr *MockUserRepositoryMockRecorder) UseTOTPCounter(ctx, userID, counter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPCounter", reflect.TypeOf((*MockUserRepository)(nil).UseTOTPCounter), ctx, userID, counter)
+}
+
 // ValidateToken mocks base method.
 func (m *MockUserRepository) ValidateToken(ctx context.Context, token string) (*entities.AuthToken, error) {
 	m.ctrl.T.Helper()
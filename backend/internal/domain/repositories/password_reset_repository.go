@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *entities.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error)
+	// Consume marks the token used at the given time and returns it. It
+	// fails with ErrConflict if the token was already used.
+	Consume(ctx context.Context, tokenHash string, at time.Time) (*entities.PasswordResetToken, error)
+	DeleteForUser(ctx context.Context, userID string) error
+}
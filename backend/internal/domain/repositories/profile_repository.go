@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+)
+
+type ProfileRepository interface {
+	Get(ctx context.Context, userID string) (*entities.Profile, error)
+	// GetMany reads the profiles of several users at once. Users without a
+	// profile are left out.
+	GetMany(ctx context.Context, userIDs []string) ([]*entities.Profile, error)
+	Save(ctx context.Context, profile *entities.Profile) error
+}
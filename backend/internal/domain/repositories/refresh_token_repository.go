@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+	// Rotate marks the token used at next.CreatedAt and stores next in one
+	// transaction. It fails with ErrConflict if the token was already used.
+	Rotate(ctx context.Context, tokenHash string, next *entities.RefreshToken) error
+	DeleteSession(ctx context.Context, sessionID string) error
+}
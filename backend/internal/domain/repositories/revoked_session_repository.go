@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+	"time"
+)
+
+type RevokedSessionRepository interface {
+	Create(ctx context.Context, session *entities.RevokedSession) error
+	// ListRevokedSince returns the sessions revoked at or after since.
+	ListRevokedSince(ctx context.Context, since time.Time) ([]*entities.RevokedSession, error)
+}
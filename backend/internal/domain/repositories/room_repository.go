@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+)
+
+type RoomRepository interface {
+	GetRoom(ctx context.Context, roomID string) (*entities.Room, error)
+	// UpdateRoom reads the room, applies update and writes it back in one
+	// transaction, calling update again if the room changed meanwhile.
+	// Nothing is written when update returns an error, which is returned
+	// as is.
+	UpdateRoom(ctx context.Context, roomID string, update func(room *entities.Room) error) (*entities.Room, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+	"time"
+)
+
+type ScheduledMessageRepository interface {
+	Create(ctx context.Context, scheduled *entities.ScheduledMessage) error
+	GetByID(ctx context.Context, id string) (*entities.ScheduledMessage, error)
+	ListByUser(ctx context.Context, userID string) ([]*entities.ScheduledMessage, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.ScheduledMessage, error)
+	Claim(ctx context.Context, id string, now, leaseUntil time.Time) (*entities.ScheduledMessage, error)
+	Cancel(ctx context.Context, id string) (*entities.ScheduledMessage, error)
+	Update(ctx context.Context, scheduled *entities.ScheduledMessage) error
+}
@@ -3,13 +3,29 @@ package repositories
 import (
 	"chat-app/backend/internal/domain/entities"
 	"context"
+	"time"
 )
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *entities.User) error
 	GetUserByUsername(ctx context.Context, username string) (*entities.User, error)
 	GetUserByID(ctx context.Context, userID string) (*entities.User, error)
+	GetUserBySSOIdentity(ctx context.Context, issuer, subject string) (*entities.User, error)
 	StoreToken(ctx context.Context, token *entities.AuthToken) error
 	ValidateToken(ctx context.Context, token string) (*entities.AuthToken, error)
 	DeleteToken(ctx context.Context, token string) error
+	ListTokens(ctx context.Context, userID string) ([]*entities.AuthToken, error)
+	TouchToken(ctx context.Context, token string, at time.Time) error
+	UpdateBotScopes(ctx context.Context, userID string, scopes *entities.BotScopes) error
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	UpdateEmail(ctx context.Context, userID, email string) error
+	// UpdateTwoFactor replaces the user's two-factor settings; nil turns
+	// two-factor authentication off.
+	UpdateTwoFactor(ctx context.Context, userID string, twoFactor *entities.TwoFactor) error
+	// UseTOTPCounter records that the code of counter was used. It fails
+	// with ErrConflict unless counter is later than the last one used.
+	UseTOTPCounter(ctx context.Context, userID string, counter int64) error
+	// UseRecoveryCode removes a recovery code. It fails with ErrNotFound if
+	// the user has no such code.
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
 }
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+	"time"
+)
+
+type WebhookDeliveryRepository interface {
+	// Create stores a new delivery and reports false when one with the same
+	// ID already exists.
+	Create(ctx context.Context, delivery *entities.WebhookDelivery) (bool, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error)
+	Claim(ctx context.Context, id string, now, leaseUntil time.Time) (*entities.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *entities.WebhookDelivery) error
+	ListByWebhook(ctx context.Context, webhookID string, limit int) ([]*entities.WebhookDelivery, error)
+}
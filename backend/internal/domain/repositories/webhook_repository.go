@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"chat-app/backend/internal/domain/entities"
+	"context"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entities.Webhook) error
+	GetByID(ctx context.Context, id string) (*entities.Webhook, error)
+	ListByRoom(ctx context.Context, roomID string) ([]*entities.Webhook, error)
+	ListAll(ctx context.Context) ([]*entities.Webhook, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,66 @@
+// Package breached looks passwords up in a local copy of the Pwned
+// Passwords range files, so that no password or hash of one leaves the
+// server.
+package breached
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prefixLength is how many hex characters of a SHA-1 hash name its range
+// file.
+const prefixLength = 5
+
+// List is a directory of range files. Each is named after the first five
+// hex characters of the SHA-1 hashes it holds, as in "5BAA6.txt", and has
+// a "SUFFIX:COUNT" line for every hash with that prefix. A missing range
+// file means no password with that prefix is known.
+type List struct {
+	dir string
+}
+
+func Open(dir string) (*List, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &List{dir: dir}, nil
+}
+
+func (l *List) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], []byte(hash[prefixLength:])
+
+	file, err := os.Open(filepath.Join(l.dir, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		lineSuffix, count, _ := bytes.Cut(line, []byte(":"))
+		// Padded range files list made up hashes with a count of 0.
+		if bytes.EqualFold(lineSuffix, suffix) && string(count) != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
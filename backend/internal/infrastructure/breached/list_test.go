@@ -0,0 +1,42 @@
+package breached_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"chat-app/backend/internal/infrastructure/breached"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList_IsBreached(t *testing.T) {
+	dir := t.TempDir()
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8 and
+	// of "letmein" B7A875FC1EA228B9061041B7CEC4BD3C52AB3CE3.
+	ranges := "003D68EB55068C33ACE09247EE4C639306B:3\r\n" +
+		"1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(ranges), 0o644))
+	padded := "5FC1EA228B9061041B7CEC4BD3C52AB3CE3:0\r\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "B7A87.txt"), []byte(padded), 0o644))
+
+	list, err := breached.Open(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for password, want := range map[string]bool{
+		"password":              true,
+		"Password":              false,
+		"letmein":               false,
+		"correct-horse-battery": false,
+	} {
+		got, err := list.IsBreached(ctx, password)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, password)
+	}
+
+	_, err = breached.Open(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
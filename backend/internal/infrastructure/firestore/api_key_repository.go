@@ -0,0 +1,84 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+type APIKeyRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewAPIKeyRepository(client *firestore.Client) repositories.APIKeyRepository {
+	return &APIKeyRepositoryImpl{client: client}
+}
+
+// Keys are stored under their hash so that authenticating a request is a
+// single document read.
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *entities.APIKey) error {
+	_, err := r.client.Collection("api_keys").Doc(key.KeyHash).Create(ctx, key)
+	return err
+}
+
+func (r *APIKeyRepositoryImpl) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	doc, err := r.client.Collection("api_keys").Doc(keyHash).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "api key")
+	}
+
+	var key entities.APIKey
+	if err := doc.DataTo(&key); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) GetByID(ctx context.Context, keyID string) (*entities.APIKey, error) {
+	doc, err := r.docByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var key entities.APIKey
+	if err := doc.DataTo(&key); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, keyID string) error {
+	doc, err := r.docByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	_, err = doc.Ref.Update(ctx, []firestore.Update{
+		{Path: "revoked", Value: true},
+	})
+	return err
+}
+
+func (r *APIKeyRepositoryImpl) TouchLastUsed(ctx context.Context, keyHash string, at time.Time) error {
+	_, err := r.client.Collection("api_keys").Doc(keyHash).Update(ctx, []firestore.Update{
+		{Path: "last_used_at", Value: at},
+	})
+	return err
+}
+
+func (r *APIKeyRepositoryImpl) docByID(ctx context.Context, keyID string) (*firestore.DocumentSnapshot, error) {
+	iter := r.client.Collection("api_keys").Where("id", "==", keyID).Limit(1).Documents(ctx)
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, fmt.Errorf("api key %w", repositories.ErrNotFound)
+	}
+	return doc, err
+}
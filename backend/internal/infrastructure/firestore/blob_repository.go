@@ -0,0 +1,62 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type BlobRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewBlobRepository(client *firestore.Client) repositories.BlobRepository {
+	return &BlobRepositoryImpl{client: client}
+}
+
+func (r *BlobRepositoryImpl) Put(ctx context.Context, blob *entities.Blob) error {
+	if blob.CreatedAt.IsZero() {
+		blob.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection("blobs").Doc(blob.ID).Set(ctx, map[string]interface{}{
+		"id":           blob.ID,
+		"content_type": blob.ContentType,
+		"data":         blob.Data,
+		"created_at":   blob.CreatedAt,
+	})
+	return err
+}
+
+func (r *BlobRepositoryImpl) Get(ctx context.Context, blobID string) (*entities.Blob, error) {
+	doc, err := r.client.Collection("blobs").Doc(blobID).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "blob")
+	}
+
+	var blob entities.Blob
+	if err := doc.DataTo(&blob); err != nil {
+		return nil, err
+	}
+
+	return &blob, nil
+}
+
+func (r *BlobRepositoryImpl) Delete(ctx context.Context, blobIDs []string) error {
+	for len(blobIDs) > 0 {
+		n := min(len(blobIDs), maxBatchWrites)
+		batch := r.client.Batch()
+		for _, blobID := range blobIDs[:n] {
+			batch.Delete(r.client.Collection("blobs").Doc(blobID))
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+		blobIDs = blobIDs[n:]
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package firestore
+
+import (
+	"fmt"
+
+	"chat-app/backend/internal/domain/repositories"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// notFound reports a missing document as repositories.ErrNotFound, so that
+// usecases don't have to know about Firestore status codes.
+func notFound(err error, what string) error {
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("%s %w", what, repositories.ErrNotFound)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package firestore
+
+import (
+	"context"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type IncomingWebhookRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewIncomingWebhookRepository(client *firestore.Client) repositories.IncomingWebhookRepository {
+	return &IncomingWebhookRepositoryImpl{client: client}
+}
+
+func (r *IncomingWebhookRepositoryImpl) Create(ctx context.Context, webhook *entities.IncomingWebhook) error {
+	_, err := r.client.Collection("incoming_webhooks").Doc(webhook.ID).Create(ctx, webhook)
+	return err
+}
+
+func (r *IncomingWebhookRepositoryImpl) GetByID(ctx context.Context, id string) (*entities.IncomingWebhook, error) {
+	doc, err := r.client.Collection("incoming_webhooks").Doc(id).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "webhook")
+	}
+	return r.documentToWebhook(doc)
+}
+
+func (r *IncomingWebhookRepositoryImpl) ListByRoom(ctx context.Context, roomID string) ([]*entities.IncomingWebhook, error) {
+	docs, err := r.client.Collection("incoming_webhooks").
+		Where("room_id", "==", roomID).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []*entities.IncomingWebhook
+	for _, doc := range docs {
+		webhook, err := r.documentToWebhook(doc)
+		if err != nil {
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+func (r *IncomingWebhookRepositoryImpl) Delete(ctx context.Context, id string) error {
+	_, err := r.client.Collection("incoming_webhooks").Doc(id).Delete(ctx)
+	return err
+}
+
+func (r *IncomingWebhookRepositoryImpl) documentToWebhook(doc *firestore.DocumentSnapshot) (*entities.IncomingWebhook, error) {
+	var webhook entities.IncomingWebhook
+	if err := doc.DataTo(&webhook); err != nil {
+		return nil, err
+	}
+	webhook.ID = doc.Ref.ID
+	return &webhook, nil
+}
@@ -0,0 +1,69 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LoginAttemptRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewLoginAttemptRepository(client *firestore.Client) repositories.LoginAttemptRepository {
+	return &LoginAttemptRepositoryImpl{client: client}
+}
+
+func (r *LoginAttemptRepositoryImpl) Get(ctx context.Context, key string) (*entities.LoginAttempts, error) {
+	doc, err := r.client.Collection("login_attempts").Doc(key).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "login attempts")
+	}
+
+	var attempts entities.LoginAttempts
+	if err := doc.DataTo(&attempts); err != nil {
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+func (r *LoginAttemptRepositoryImpl) RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*entities.LoginAttempts, error) {
+	docRef := r.client.Collection("login_attempts").Doc(key)
+
+	var attempts entities.LoginAttempts
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		attempts = entities.LoginAttempts{Key: key}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&attempts); err != nil {
+				return err
+			}
+		}
+
+		if attempts.Failures == 0 || at.Sub(attempts.FirstFailureAt) > window {
+			attempts.Failures = 0
+			attempts.FirstFailureAt = at
+		}
+		attempts.Failures++
+		attempts.LastFailureAt = at
+		return tx.Set(docRef, &attempts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+func (r *LoginAttemptRepositoryImpl) Reset(ctx context.Context, key string) error {
+	_, err := r.client.Collection("login_attempts").Doc(key).Delete(ctx)
+	return err
+}
@@ -0,0 +1,61 @@
+package firestore
+
+import (
+	"context"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type LoginChallengeRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewLoginChallengeRepository(client *firestore.Client) repositories.LoginChallengeRepository {
+	return &LoginChallengeRepositoryImpl{client: client}
+}
+
+func (r *LoginChallengeRepositoryImpl) Create(ctx context.Context, challenge *entities.LoginChallenge) error {
+	_, err := r.client.Collection("login_challenges").Doc(challenge.TokenHash).Create(ctx, challenge)
+	return err
+}
+
+func (r *LoginChallengeRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*entities.LoginChallenge, error) {
+	doc, err := r.client.Collection("login_challenges").Doc(tokenHash).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "login challenge")
+	}
+
+	var challenge entities.LoginChallenge
+	if err := doc.DataTo(&challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *LoginChallengeRepositoryImpl) RecordAttempt(ctx context.Context, tokenHash string) (int, error) {
+	docRef := r.client.Collection("login_challenges").Doc(tokenHash)
+
+	var attempts int
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return notFound(err, "login challenge")
+		}
+
+		var challenge entities.LoginChallenge
+		if err := doc.DataTo(&challenge); err != nil {
+			return err
+		}
+		attempts = challenge.Attempts + 1
+		return tx.Update(docRef, []firestore.Update{{Path: "attempts", Value: attempts}})
+	})
+	return attempts, err
+}
+
+func (r *LoginChallengeRepositoryImpl) Delete(ctx context.Context, tokenHash string) error {
+	_, err := r.client.Collection("login_challenges").Doc(tokenHash).Delete(ctx)
+	return err
+}
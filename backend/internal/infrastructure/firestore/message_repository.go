@@ -2,7 +2,9 @@ package firestore
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"chat-app/backend/internal/domain/entities"
@@ -29,6 +31,19 @@ func (r *MessageRepositoryImpl) Create(ctx context.Context, message *entities.Me
 		"room_id":   message.RoomID,
 		"timestamp": firestore.ServerTimestamp,
 	}
+	if !message.ExpiresAt.IsZero() {
+		messageData["expires_at"] = message.ExpiresAt
+	}
+	if len(message.Attachments) > 0 {
+		messageData["attachments"] = message.Attachments
+		messageData["has_pending_attachments"] = message.HasPendingAttachments()
+	}
+	if message.Poll != nil {
+		messageData["poll"] = message.Poll
+	}
+	if message.IsBot {
+		messageData["is_bot"] = true
+	}
 
 	docRef, _, err := r.client.Collection("messages").Add(ctx, messageData)
 	if err != nil {
@@ -39,6 +54,14 @@ func (r *MessageRepositoryImpl) Create(ctx context.Context, message *entities.Me
 	return message, nil
 }
 
+func (r *MessageRepositoryImpl) GetByID(ctx context.Context, messageID string) (*entities.Message, error) {
+	doc, err := r.client.Collection("messages").Doc(messageID).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "message")
+	}
+	return r.documentToMessage(doc)
+}
+
 func (r *MessageRepositoryImpl) GetByRoomID(ctx context.Context, roomID string, limit int) ([]*entities.Message, error) {
 	iter := r.client.Collection("messages").
 		Where("room_id", "==", roomID).
@@ -95,14 +118,32 @@ func (r *MessageRepositoryImpl) StreamByRoomID(ctx context.Context, roomID strin
 
 			for _, change := range snap.Changes {
 				log.Printf("🔄 Firestore change: %s document", change.Kind)
-				if change.Kind == firestore.DocumentAdded {
-					message, err := r.documentToMessage(change.Doc)
-					if err != nil {
-						log.Printf("❌ Error parsing document: %v", err)
-						continue
+				message, err := r.documentToMessage(change.Doc)
+				if err != nil {
+					log.Printf("❌ Error parsing document: %v", err)
+					continue
+				}
+
+				switch change.Kind {
+				case firestore.DocumentAdded:
+					message.Event = entities.MessageCreated
+				case firestore.DocumentModified:
+					message.Event = entities.MessageUpdated
+					if e, ok := change.Doc.Data()["event"].(string); ok && e != "" {
+						message.Event = entities.MessageEvent(e)
+					}
+				case firestore.DocumentRemoved:
+					message.Event = entities.MessageDeleted
+					if message.IsExpired(time.Now()) {
+						message.Event = entities.MessageExpired
 					}
-					log.Printf("✅ Sending message to channel: %s", message.Content)
-					messageChan <- message
+				}
+				log.Printf("✅ Sending message to channel: %s", message.Content)
+
+				select {
+				case messageChan <- message:
+				case <-ctx.Done():
+					return
 				}
 			}
 		}
@@ -110,6 +151,364 @@ func (r *MessageRepositoryImpl) StreamByRoomID(ctx context.Context, roomID strin
 
 	return messageChan, nil
 }
+
+func (r *MessageRepositoryImpl) UpdateAttachment(ctx context.Context, messageID string, attachment *entities.Attachment) error {
+	docRef := r.client.Collection("messages").Doc(messageID)
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for i, existing := range message.Attachments {
+			if existing.ID == attachment.ID {
+				message.Attachments[i] = attachment
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("attachment %s %w on message %s", attachment.ID, repositories.ErrNotFound, messageID)
+		}
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "attachments", Value: message.Attachments},
+			{Path: "has_pending_attachments", Value: message.HasPendingAttachments()},
+			{Path: "event", Value: string(entities.MessageUpdated)},
+		})
+	})
+}
+
+func (r *MessageRepositoryImpl) UpdateLinkPreviews(ctx context.Context, messageID string, previews []*entities.LinkPreview) error {
+	_, err := r.client.Collection("messages").Doc(messageID).Update(ctx, []firestore.Update{
+		{Path: "link_previews", Value: previews},
+		{Path: "event", Value: string(entities.MessageUpdated)},
+	})
+	return err
+}
+
+func (r *MessageRepositoryImpl) Pin(ctx context.Context, messageID, pinnedBy string, limit int) (*entities.Message, error) {
+	docRef := r.client.Collection("messages").Doc(messageID)
+	var pinned *entities.Message
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			return err
+		}
+		if message.Pinned {
+			pinned = message
+			return nil
+		}
+
+		existing, err := tx.Documents(r.client.Collection("messages").
+			Where("room_id", "==", message.RoomID).
+			Where("pinned", "==", true)).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(existing) >= limit {
+			return fmt.Errorf("%w: room %s already has the maximum of %d pinned messages", repositories.ErrConflict, message.RoomID, limit)
+		}
+
+		message.Pinned = true
+		message.PinnedBy = pinnedBy
+		message.PinnedAt = time.Now()
+		pinned = message
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "pinned", Value: true},
+			{Path: "pinned_by", Value: pinnedBy},
+			{Path: "pinned_at", Value: message.PinnedAt},
+			{Path: "event", Value: string(entities.MessagePinned)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pinned, nil
+}
+
+func (r *MessageRepositoryImpl) Unpin(ctx context.Context, messageID string) (*entities.Message, error) {
+	docRef := r.client.Collection("messages").Doc(messageID)
+	var unpinned *entities.Message
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			return err
+		}
+		unpinned = message
+		if !message.Pinned {
+			return nil
+		}
+
+		message.Pinned = false
+		message.PinnedBy = ""
+		message.PinnedAt = time.Time{}
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "pinned", Value: false},
+			{Path: "pinned_by", Value: firestore.Delete},
+			{Path: "pinned_at", Value: firestore.Delete},
+			{Path: "event", Value: string(entities.MessageUnpinned)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return unpinned, nil
+}
+
+func (r *MessageRepositoryImpl) GetPinnedByRoomID(ctx context.Context, roomID string) ([]*entities.Message, error) {
+	docs, err := r.client.Collection("messages").
+		Where("room_id", "==", roomID).
+		Where("pinned", "==", true).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*entities.Message
+	for _, doc := range docs {
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].PinnedAt.After(messages[j].PinnedAt)
+	})
+
+	return messages, nil
+}
+
+func (r *MessageRepositoryImpl) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.Message, error) {
+	docs, err := r.client.Collection("messages").
+		Where("expires_at", "<=", before).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*entities.Message
+	for _, doc := range docs {
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (r *MessageRepositoryImpl) DeleteVotes(ctx context.Context, messageID string) error {
+	refs, err := r.client.Collection("messages").Doc(messageID).Collection("votes").DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+	return r.deleteAll(ctx, refs)
+}
+
+func (r *MessageRepositoryImpl) DeleteMessages(ctx context.Context, messageIDs []string) error {
+	refs := make([]*firestore.DocumentRef, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		refs = append(refs, r.client.Collection("messages").Doc(messageID))
+	}
+	return r.deleteAll(ctx, refs)
+}
+
+// maxBatchWrites is the most writes a Firestore batch may hold.
+const maxBatchWrites = 500
+
+func (r *MessageRepositoryImpl) deleteAll(ctx context.Context, refs []*firestore.DocumentRef) error {
+	for len(refs) > 0 {
+		n := min(len(refs), maxBatchWrites)
+		batch := r.client.Batch()
+		for _, ref := range refs[:n] {
+			batch.Delete(ref)
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+		refs = refs[n:]
+	}
+	return nil
+}
+
+// RecordVote stores the vote under the message's votes subcollection, keyed
+// by voterKey, so a second vote from the same user fails inside the same
+// transaction that updates the tallies.
+func (r *MessageRepositoryImpl) RecordVote(ctx context.Context, messageID, voterKey, userID string, optionIDs []string, now time.Time) (*entities.Message, error) {
+	docRef := r.client.Collection("messages").Doc(messageID)
+	voteRef := docRef.Collection("votes").Doc(voterKey)
+	var voted *entities.Message
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			return err
+		}
+		if message.Poll == nil {
+			return fmt.Errorf("%w: message %s is not a poll", repositories.ErrConflict, messageID)
+		}
+		if !message.Poll.IsOpen(now) {
+			return fmt.Errorf("%w: poll is closed", repositories.ErrConflict)
+		}
+
+		if _, err := tx.Get(voteRef); err == nil {
+			return fmt.Errorf("%w: you have already voted in this poll", repositories.ErrConflict)
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		for _, id := range optionIDs {
+			option := message.Poll.Option(id)
+			if option == nil {
+				return fmt.Errorf("poll option %s %w", id, repositories.ErrNotFound)
+			}
+			option.Votes++
+			if !message.Poll.Anonymous {
+				option.VoterIDs = append(option.VoterIDs, userID)
+			}
+		}
+		message.Poll.TotalVoters++
+		voted = message
+
+		vote := map[string]interface{}{
+			"option_ids": optionIDs,
+			"voted_at":   now,
+		}
+		if !message.Poll.Anonymous {
+			vote["user_id"] = userID
+		}
+		if err := tx.Create(voteRef, vote); err != nil {
+			return err
+		}
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "poll", Value: message.Poll},
+			{Path: "event", Value: string(entities.PollUpdated)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return voted, nil
+}
+
+func (r *MessageRepositoryImpl) ClosePoll(ctx context.Context, messageID string) (*entities.Message, error) {
+	docRef := r.client.Collection("messages").Doc(messageID)
+	var closed *entities.Message
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			return err
+		}
+		if message.Poll == nil {
+			return fmt.Errorf("%w: message %s is not a poll", repositories.ErrConflict, messageID)
+		}
+		closed = message
+		if message.Poll.Closed {
+			return nil
+		}
+
+		message.Poll.Closed = true
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "poll.closed", Value: true},
+			{Path: "event", Value: string(entities.PollClosed)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closed, nil
+}
+
+func (r *MessageRepositoryImpl) ListDuePolls(ctx context.Context, now time.Time, limit int) ([]*entities.Message, error) {
+	docs, err := r.client.Collection("messages").
+		Where("poll.closed", "==", false).
+		Where("poll.closes_at", "<=", now).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*entities.Message
+	for _, doc := range docs {
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (r *MessageRepositoryImpl) ListWithPendingAttachments(ctx context.Context, limit int) ([]*entities.Message, error) {
+	docs, err := r.client.Collection("messages").
+		Where("has_pending_attachments", "==", true).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*entities.Message
+	for _, doc := range docs {
+		message, err := r.documentToMessage(doc)
+		if err != nil {
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
 func (r *MessageRepositoryImpl) documentToMessage(doc *firestore.DocumentSnapshot) (*entities.Message, error) {
 	var data map[string]interface{}
 	if err := doc.DataTo(&data); err != nil {
@@ -123,12 +522,34 @@ func (r *MessageRepositoryImpl) documentToMessage(doc *firestore.DocumentSnapsho
 		timestamp = time.Now()
 	}
 
+	var extra struct {
+		Attachments  []*entities.Attachment  `firestore:"attachments"`
+		LinkPreviews []*entities.LinkPreview `firestore:"link_previews"`
+		Pinned       bool                    `firestore:"pinned"`
+		PinnedBy     string                  `firestore:"pinned_by"`
+		PinnedAt     time.Time               `firestore:"pinned_at"`
+		ExpiresAt    time.Time               `firestore:"expires_at"`
+		Poll         *entities.Poll          `firestore:"poll"`
+		IsBot        bool                    `firestore:"is_bot"`
+	}
+	if err := doc.DataTo(&extra); err != nil {
+		return nil, err
+	}
+
 	return &entities.Message{
-		ID:        doc.Ref.ID,
-		UserID:    data["user_id"].(string),
-		Username:  data["username"].(string),
-		Content:   data["content"].(string),
-		RoomID:    data["room_id"].(string),
-		Timestamp: timestamp,
+		ID:           doc.Ref.ID,
+		UserID:       data["user_id"].(string),
+		Username:     data["username"].(string),
+		Content:      data["content"].(string),
+		RoomID:       data["room_id"].(string),
+		Timestamp:    timestamp,
+		Attachments:  extra.Attachments,
+		LinkPreviews: extra.LinkPreviews,
+		Pinned:       extra.Pinned,
+		PinnedBy:     extra.PinnedBy,
+		PinnedAt:     extra.PinnedAt,
+		ExpiresAt:    extra.ExpiresAt,
+		Poll:         extra.Poll,
+		IsBot:        extra.IsBot,
 	}, nil
 }
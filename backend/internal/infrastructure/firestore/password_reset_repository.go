@@ -0,0 +1,79 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type PasswordResetRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewPasswordResetRepository(client *firestore.Client) repositories.PasswordResetRepository {
+	return &PasswordResetRepositoryImpl{client: client}
+}
+
+func (r *PasswordResetRepositoryImpl) Create(ctx context.Context, token *entities.PasswordResetToken) error {
+	_, err := r.client.Collection("password_resets").Doc(token.TokenHash).Create(ctx, token)
+	return err
+}
+
+func (r *PasswordResetRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error) {
+	doc, err := r.client.Collection("password_resets").Doc(tokenHash).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "password reset token")
+	}
+
+	var token entities.PasswordResetToken
+	if err := doc.DataTo(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *PasswordResetRepositoryImpl) Consume(ctx context.Context, tokenHash string, at time.Time) (*entities.PasswordResetToken, error) {
+	docRef := r.client.Collection("password_resets").Doc(tokenHash)
+
+	var token entities.PasswordResetToken
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return notFound(err, "password reset token")
+		}
+		if err := doc.DataTo(&token); err != nil {
+			return err
+		}
+		if !token.UsedAt.IsZero() {
+			return fmt.Errorf("%w: password reset token was already used", repositories.ErrConflict)
+		}
+
+		token.UsedAt = at
+		return tx.Update(docRef, []firestore.Update{{Path: "used_at", Value: at}})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *PasswordResetRepositoryImpl) DeleteForUser(ctx context.Context, userID string) error {
+	docs, err := r.client.Collection("password_resets").Where("user_id", "==", userID).Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	batch := r.client.Batch()
+	for _, doc := range docs {
+		batch.Delete(doc.Ref)
+	}
+	if len(docs) > 0 {
+		_, err = batch.Commit(ctx)
+	}
+	return err
+}
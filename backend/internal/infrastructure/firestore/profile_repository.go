@@ -0,0 +1,64 @@
+package firestore
+
+import (
+	"context"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type ProfileRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewProfileRepository(client *firestore.Client) repositories.ProfileRepository {
+	return &ProfileRepositoryImpl{client: client}
+}
+
+func (r *ProfileRepositoryImpl) Get(ctx context.Context, userID string) (*entities.Profile, error) {
+	doc, err := r.client.Collection("profiles").Doc(userID).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "profile")
+	}
+
+	var profile entities.Profile
+	if err := doc.DataTo(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *ProfileRepositoryImpl) GetMany(ctx context.Context, userIDs []string) ([]*entities.Profile, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(userIDs))
+	for _, userID := range userIDs {
+		refs = append(refs, r.client.Collection("profiles").Doc(userID))
+	}
+	docs, err := r.client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*entities.Profile, 0, len(docs))
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var profile entities.Profile
+		if err := doc.DataTo(&profile); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, &profile)
+	}
+	return profiles, nil
+}
+
+func (r *ProfileRepositoryImpl) Save(ctx context.Context, profile *entities.Profile) error {
+	_, err := r.client.Collection("profiles").Doc(profile.UserID).Set(ctx, profile)
+	return err
+}
@@ -0,0 +1,84 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type RefreshTokenRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewRefreshTokenRepository(client *firestore.Client) repositories.RefreshTokenRepository {
+	return &RefreshTokenRepositoryImpl{client: client}
+}
+
+// Tokens are stored under their hash, like API keys. Used tokens are kept
+// until their session ends so that reuse can be detected; a TTL policy on
+// expires_at removes them after that.
+func (r *RefreshTokenRepositoryImpl) Create(ctx context.Context, token *entities.RefreshToken) error {
+	_, err := r.client.Collection("refresh_tokens").Doc(token.TokenHash).Create(ctx, token)
+	return err
+}
+
+func (r *RefreshTokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	doc, err := r.client.Collection("refresh_tokens").Doc(tokenHash).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "refresh token")
+	}
+
+	var token entities.RefreshToken
+	if err := doc.DataTo(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepositoryImpl) Rotate(ctx context.Context, tokenHash string, next *entities.RefreshToken) error {
+	docRef := r.client.Collection("refresh_tokens").Doc(tokenHash)
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return notFound(err, "refresh token")
+		}
+
+		var token entities.RefreshToken
+		if err := doc.DataTo(&token); err != nil {
+			return err
+		}
+		if !token.UsedAt.IsZero() {
+			return fmt.Errorf("%w: refresh token was already used", repositories.ErrConflict)
+		}
+
+		if err := tx.Update(docRef, []firestore.Update{{Path: "used_at", Value: next.CreatedAt}}); err != nil {
+			return err
+		}
+		return tx.Create(r.client.Collection("refresh_tokens").Doc(next.TokenHash), next)
+	})
+}
+
+func (r *RefreshTokenRepositoryImpl) DeleteSession(ctx context.Context, sessionID string) error {
+	docs, err := r.client.Collection("refresh_tokens").Where("session_id", "==", sessionID).Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	// A batch holds at most 500 writes, and every refresh leaves a used
+	// token behind.
+	for start := 0; start < len(docs); start += 500 {
+		batch := r.client.Batch()
+		for _, doc := range docs[start:min(start+500, len(docs))] {
+			batch.Delete(doc.Ref)
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type RevokedSessionRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewRevokedSessionRepository(client *firestore.Client) repositories.RevokedSessionRepository {
+	return &RevokedSessionRepositoryImpl{client: client}
+}
+
+// Revocations are stored under the session ID, so revoking a session twice
+// keeps one document. A TTL policy on expires_at removes them.
+func (r *RevokedSessionRepositoryImpl) Create(ctx context.Context, session *entities.RevokedSession) error {
+	_, err := r.client.Collection("revoked_sessions").Doc(session.SessionID).Set(ctx, session)
+	return err
+}
+
+func (r *RevokedSessionRepositoryImpl) ListRevokedSince(ctx context.Context, since time.Time) ([]*entities.RevokedSession, error) {
+	docs, err := r.client.Collection("revoked_sessions").
+		Where("revoked_at", ">=", since).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entities.RevokedSession, 0, len(docs))
+	for _, doc := range docs {
+		var session entities.RevokedSession
+		if err := doc.DataTo(&session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
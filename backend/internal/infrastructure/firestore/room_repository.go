@@ -0,0 +1,73 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RoomRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewRoomRepository(client *firestore.Client) repositories.RoomRepository {
+	return &RoomRepositoryImpl{client: client}
+}
+
+func (r *RoomRepositoryImpl) GetRoom(ctx context.Context, roomID string) (*entities.Room, error) {
+	doc, err := r.client.Collection("rooms").Doc(roomID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return &entities.Room{ID: roomID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var room entities.Room
+	if err := doc.DataTo(&room); err != nil {
+		return nil, err
+	}
+	room.ID = roomID
+
+	return &room, nil
+}
+
+func (r *RoomRepositoryImpl) UpdateRoom(ctx context.Context, roomID string, update func(room *entities.Room) error) (*entities.Room, error) {
+	ref := r.client.Collection("rooms").Doc(roomID)
+
+	var room *entities.Room
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		room = &entities.Room{}
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(room); err != nil {
+				return err
+			}
+		}
+		room.ID = roomID
+
+		if err := update(room); err != nil {
+			return err
+		}
+		now := time.Now()
+		if room.CreatedAt.IsZero() {
+			room.CreatedAt = now
+		}
+		room.UpdatedAt = now
+		return tx.Set(ref, room)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
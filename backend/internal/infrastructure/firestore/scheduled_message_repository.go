@@ -0,0 +1,163 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type ScheduledMessageRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewScheduledMessageRepository(client *firestore.Client) repositories.ScheduledMessageRepository {
+	return &ScheduledMessageRepositoryImpl{client: client}
+}
+
+func (r *ScheduledMessageRepositoryImpl) Create(ctx context.Context, scheduled *entities.ScheduledMessage) error {
+	_, err := r.client.Collection("scheduled_messages").Doc(scheduled.ID).Create(ctx, scheduled)
+	return err
+}
+
+func (r *ScheduledMessageRepositoryImpl) GetByID(ctx context.Context, id string) (*entities.ScheduledMessage, error) {
+	doc, err := r.client.Collection("scheduled_messages").Doc(id).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "scheduled message")
+	}
+	return r.documentToScheduledMessage(doc)
+}
+
+func (r *ScheduledMessageRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*entities.ScheduledMessage, error) {
+	docs, err := r.client.Collection("scheduled_messages").
+		Where("user_id", "==", userID).
+		Where("status", "in", []string{string(entities.ScheduledPending), string(entities.ScheduledSending)}).
+		OrderBy("send_at", firestore.Asc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return r.documentsToScheduledMessages(docs), nil
+}
+
+func (r *ScheduledMessageRepositoryImpl) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.ScheduledMessage, error) {
+	docs, err := r.client.Collection("scheduled_messages").
+		Where("status", "in", []string{string(entities.ScheduledPending), string(entities.ScheduledSending)}).
+		Where("send_at", "<=", now).
+		OrderBy("send_at", firestore.Asc).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return r.documentsToScheduledMessages(docs), nil
+}
+
+// Claim marks a due job as being sent by this instance. It returns nil when
+// the job was cancelled, already delivered, or is leased by another instance.
+func (r *ScheduledMessageRepositoryImpl) Claim(ctx context.Context, id string, now, leaseUntil time.Time) (*entities.ScheduledMessage, error) {
+	docRef := r.client.Collection("scheduled_messages").Doc(id)
+	var claimed *entities.ScheduledMessage
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		scheduled, err := r.documentToScheduledMessage(doc)
+		if err != nil {
+			return err
+		}
+
+		switch scheduled.Status {
+		case entities.ScheduledPending:
+		case entities.ScheduledSending:
+			if now.Before(scheduled.LeaseUntil) {
+				return nil
+			}
+		default:
+			return nil
+		}
+
+		scheduled.Status = entities.ScheduledSending
+		scheduled.LeaseUntil = leaseUntil
+		scheduled.Attempts++
+		claimed = scheduled
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "status", Value: string(scheduled.Status)},
+			{Path: "lease_until", Value: scheduled.LeaseUntil},
+			{Path: "attempts", Value: scheduled.Attempts},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (r *ScheduledMessageRepositoryImpl) Cancel(ctx context.Context, id string) (*entities.ScheduledMessage, error) {
+	docRef := r.client.Collection("scheduled_messages").Doc(id)
+	var cancelled *entities.ScheduledMessage
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		scheduled, err := r.documentToScheduledMessage(doc)
+		if err != nil {
+			return err
+		}
+		if scheduled.Status != entities.ScheduledPending {
+			return fmt.Errorf("%w: scheduled message is already %s", repositories.ErrConflict, scheduled.Status)
+		}
+
+		scheduled.Status = entities.ScheduledCancelled
+		cancelled = scheduled
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "status", Value: string(scheduled.Status)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cancelled, nil
+}
+
+func (r *ScheduledMessageRepositoryImpl) Update(ctx context.Context, scheduled *entities.ScheduledMessage) error {
+	_, err := r.client.Collection("scheduled_messages").Doc(scheduled.ID).Set(ctx, scheduled)
+	return err
+}
+
+func (r *ScheduledMessageRepositoryImpl) documentsToScheduledMessages(docs []*firestore.DocumentSnapshot) []*entities.ScheduledMessage {
+	var scheduled []*entities.ScheduledMessage
+	for _, doc := range docs {
+		s, err := r.documentToScheduledMessage(doc)
+		if err != nil {
+			continue
+		}
+		scheduled = append(scheduled, s)
+	}
+	return scheduled
+}
+
+func (r *ScheduledMessageRepositoryImpl) documentToScheduledMessage(doc *firestore.DocumentSnapshot) (*entities.ScheduledMessage, error) {
+	var scheduled entities.ScheduledMessage
+	if err := doc.DataTo(&scheduled); err != nil {
+		return nil, err
+	}
+	scheduled.ID = doc.Ref.ID
+	return &scheduled, nil
+}
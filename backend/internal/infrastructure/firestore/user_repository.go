@@ -2,6 +2,8 @@ package firestore
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type UserRepositoryImpl struct {
@@ -20,31 +24,72 @@ func NewUserRepository(client *firestore.Client) repositories.UserRepository {
 	return &UserRepositoryImpl{client: client}
 }
 
+// CreateUser reserves the user's username key in "usernames" in the same
+// transaction, so that of two users registering alike usernames at once
+// only one succeeds.
 func (r *UserRepositoryImpl) CreateUser(ctx context.Context, user *entities.User) error {
-	iter := r.client.Collection("users").Where("username", "==", user.Username).Documents(ctx)
-	_, err := iter.Next()
-	if err != iterator.Done {
-		return fmt.Errorf("username already exists")
-	}
+	user.UsernameKey = entities.UsernameKey(user.Username)
+	reservation := r.client.Collection("usernames").Doc(usernameDocID(user.UsernameKey))
 
-	_, err = r.client.Collection("users").Doc(user.ID).Set(ctx, map[string]interface{}{
-		"id":            user.ID,
-		"username":      user.Username,
-		"password_hash": user.PasswordHash,
-		"created_at":    user.CreatedAt,
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Create(reservation, map[string]interface{}{
+			"user_id":    user.ID,
+			"username":   user.Username,
+			"created_at": user.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		return tx.Set(r.client.Collection("users").Doc(user.ID), map[string]interface{}{
+			"id":            user.ID,
+			"username":      user.Username,
+			"username_key":  user.UsernameKey,
+			"password_hash": user.PasswordHash,
+			"created_at":    user.CreatedAt,
+			"is_bot":        user.IsBot,
+			"bot_scopes":    user.BotScopes,
+			"created_by":    user.CreatedBy,
+			"email":         user.Email,
+			"sso_issuer":    user.SSOIssuer,
+			"sso_subject":   user.SSOSubject,
+		})
 	})
+	if status.Code(err) == codes.AlreadyExists {
+		return fmt.Errorf("username %w", repositories.ErrAlreadyExists)
+	}
 	return err
 }
 
+// GetUserByUsername finds the user by username key. Only users from before
+// keys existed can share one (see MigrateUsernameKeys), and among those the
+// exact username wins.
 func (r *UserRepositoryImpl) GetUserByUsername(ctx context.Context, username string) (*entities.User, error) {
-	iter := r.client.Collection("users").Where("username", "==", username).Documents(ctx)
-	doc, err := iter.Next()
-	if err == iterator.Done {
-		return nil, fmt.Errorf("user not found")
-	}
+	docs, err := r.client.Collection("users").Where("username_key", "==", entities.UsernameKey(username)).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("user %w", repositories.ErrNotFound)
+	}
+
+	var found *entities.User
+	for _, doc := range docs {
+		var user entities.User
+		if err := doc.DataTo(&user); err != nil {
+			return nil, err
+		}
+		if found == nil || user.Username == username {
+			found = &user
+		}
+	}
+
+	return found, nil
+}
+
+func (r *UserRepositoryImpl) GetUserByID(ctx context.Context, userID string) (*entities.User, error) {
+	doc, err := r.client.Collection("users").Doc(userID).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "user")
+	}
 
 	var user entities.User
 	if err := doc.DataTo(&user); err != nil {
@@ -54,8 +99,16 @@ func (r *UserRepositoryImpl) GetUserByUsername(ctx context.Context, username str
 	return &user, nil
 }
 
-func (r *UserRepositoryImpl) GetUserByID(ctx context.Context, userID string) (*entities.User, error) {
-	doc, err := r.client.Collection("users").Doc(userID).Get(ctx)
+func (r *UserRepositoryImpl) GetUserBySSOIdentity(ctx context.Context, issuer, subject string) (*entities.User, error) {
+	iter := r.client.Collection("users").
+		Where("sso_issuer", "==", issuer).
+		Where("sso_subject", "==", subject).
+		Limit(1).
+		Documents(ctx)
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, fmt.Errorf("user %w", repositories.ErrNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +123,16 @@ func (r *UserRepositoryImpl) GetUserByID(ctx context.Context, userID string) (*e
 
 func (r *UserRepositoryImpl) StoreToken(ctx context.Context, token *entities.AuthToken) error {
 	_, err := r.client.Collection("tokens").Doc(token.Token).Set(ctx, map[string]interface{}{
-		"token":      token.Token,
-		"user_id":    token.UserID,
-		"expires_at": token.ExpiresAt,
+		"token":              token.Token,
+		"id":                 token.ID,
+		"user_id":            token.UserID,
+		"expires_at":         token.ExpiresAt,
+		"session_expires_at": token.SessionExpiresAt,
+		"created_at":         token.CreatedAt,
+		"last_used_at":       token.LastUsedAt,
+		"device_label":       token.DeviceLabel,
+		"ip_address":         token.IPAddress,
+		"user_agent":         token.UserAgent,
 	})
 	return err
 }
@@ -80,7 +140,7 @@ func (r *UserRepositoryImpl) StoreToken(ctx context.Context, token *entities.Aut
 func (r *UserRepositoryImpl) ValidateToken(ctx context.Context, token string) (*entities.AuthToken, error) {
 	doc, err := r.client.Collection("tokens").Doc(token).Get(ctx)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "token")
 	}
 
 	var authToken entities.AuthToken
@@ -88,15 +148,122 @@ func (r *UserRepositoryImpl) ValidateToken(ctx context.Context, token string) (*
 		return nil, err
 	}
 
-	if time.Now().After(authToken.ExpiresAt) {
-		r.client.Collection("tokens").Doc(token).Delete(ctx)
-		return nil, fmt.Errorf("token expired")
+	if now := time.Now(); now.After(authToken.ExpiresAt) {
+		// The token still lists its session until the session ends.
+		if now.After(authToken.SessionExpiry()) {
+			r.client.Collection("tokens").Doc(token).Delete(ctx)
+		}
+		return nil, fmt.Errorf("token %w", repositories.ErrNotFound)
 	}
 
 	return &authToken, nil
 }
 
+func (r *UserRepositoryImpl) UpdateBotScopes(ctx context.Context, userID string, scopes *entities.BotScopes) error {
+	_, err := r.client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "bot_scopes", Value: scopes},
+	})
+	return err
+}
+
+func (r *UserRepositoryImpl) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	_, err := r.client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "password_hash", Value: passwordHash},
+	})
+	return notFound(err, "user")
+}
+
+func (r *UserRepositoryImpl) UpdateEmail(ctx context.Context, userID, email string) error {
+	_, err := r.client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "email", Value: email},
+	})
+	return notFound(err, "user")
+}
+
+func (r *UserRepositoryImpl) UpdateTwoFactor(ctx context.Context, userID string, twoFactor *entities.TwoFactor) error {
+	_, err := r.client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "two_factor", Value: twoFactor},
+	})
+	return notFound(err, "user")
+}
+
+func (r *UserRepositoryImpl) UseTOTPCounter(ctx context.Context, userID string, counter int64) error {
+	return r.updateTwoFactor(ctx, userID, func(twoFactor *entities.TwoFactor) error {
+		if counter <= twoFactor.LastCounter {
+			return fmt.Errorf("%w: code was already used", repositories.ErrConflict)
+		}
+		twoFactor.LastCounter = counter
+		return nil
+	})
+}
+
+func (r *UserRepositoryImpl) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	return r.updateTwoFactor(ctx, userID, func(twoFactor *entities.TwoFactor) error {
+		for i, code := range twoFactor.RecoveryCodes {
+			if code == codeHash {
+				twoFactor.RecoveryCodes = append(twoFactor.RecoveryCodes[:i], twoFactor.RecoveryCodes[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("recovery code %w", repositories.ErrNotFound)
+	})
+}
+
+// updateTwoFactor changes the user's two-factor settings in a transaction,
+// so that concurrent logins cannot use the same code.
+func (r *UserRepositoryImpl) updateTwoFactor(ctx context.Context, userID string, update func(*entities.TwoFactor) error) error {
+	docRef := r.client.Collection("users").Doc(userID)
+
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return notFound(err, "user")
+		}
+
+		var user entities.User
+		if err := doc.DataTo(&user); err != nil {
+			return err
+		}
+		if user.TwoFactor == nil {
+			return fmt.Errorf("%w: two-factor authentication is off", repositories.ErrConflict)
+		}
+		if err := update(user.TwoFactor); err != nil {
+			return err
+		}
+		return tx.Update(docRef, []firestore.Update{{Path: "two_factor", Value: user.TwoFactor}})
+	})
+}
+
 func (r *UserRepositoryImpl) DeleteToken(ctx context.Context, token string) error {
 	_, err := r.client.Collection("tokens").Doc(token).Delete(ctx)
 	return err
 }
+
+func (r *UserRepositoryImpl) ListTokens(ctx context.Context, userID string) ([]*entities.AuthToken, error) {
+	docs, err := r.client.Collection("tokens").Where("user_id", "==", userID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*entities.AuthToken, 0, len(docs))
+	for _, doc := range docs {
+		var token entities.AuthToken
+		if err := doc.DataTo(&token); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+	return tokens, nil
+}
+
+func (r *UserRepositoryImpl) TouchToken(ctx context.Context, token string, at time.Time) error {
+	_, err := r.client.Collection("tokens").Doc(token).Update(ctx, []firestore.Update{
+		{Path: "last_used_at", Value: at},
+	})
+	return err
+}
+
+func usernameDocID(usernameKey string) string {
+	sum := sha256.Sum256([]byte(usernameKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,80 @@
+package firestore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MigrateUsernameKeys gives users created before username keys existed
+// their "username_key" field and "usernames" reservation, and returns how
+// many it updated. It is run before serving, as users are only looked up
+// by their key.
+//
+// Legacy users whose usernames only differ in case or look-alike
+// characters share a key; the first one keeps the reservation and
+// GetUserByUsername tells them apart by the exact username.
+func MigrateUsernameKeys(ctx context.Context, client *firestore.Client) (int, error) {
+	iter := client.Collection("users").Documents(ctx)
+	defer iter.Stop()
+
+	migrated := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return migrated, nil
+		}
+		if err != nil {
+			return migrated, err
+		}
+
+		var user entities.User
+		if err := doc.DataTo(&user); err != nil {
+			return migrated, err
+		}
+		if user.UsernameKey != "" {
+			continue
+		}
+		if err := migrateUsernameKey(ctx, client, doc.Ref, &user); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+}
+
+func migrateUsernameKey(ctx context.Context, client *firestore.Client, ref *firestore.DocumentRef, user *entities.User) error {
+	key := entities.UsernameKey(user.Username)
+	reservation := client.Collection("usernames").Doc(usernameDocID(key))
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(reservation)
+		switch {
+		case status.Code(err) == codes.NotFound:
+			createdAt := user.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = time.Now()
+			}
+			if err := tx.Create(reservation, map[string]interface{}{
+				"user_id":    ref.ID,
+				"username":   user.Username,
+				"created_at": createdAt,
+			}); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			if owner, _ := doc.DataAt("user_id"); owner != ref.ID {
+				log.Printf("Username %q of user %s has the same key as the one of user %v", user.Username, ref.ID, owner)
+			}
+		}
+		return tx.Update(ref, []firestore.Update{{Path: "username_key", Value: key}})
+	})
+}
@@ -0,0 +1,131 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type WebhookDeliveryRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewWebhookDeliveryRepository(client *firestore.Client) repositories.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepositoryImpl{client: client}
+}
+
+func (r *WebhookDeliveryRepositoryImpl) Create(ctx context.Context, delivery *entities.WebhookDelivery) (bool, error) {
+	_, err := r.client.Collection("webhook_deliveries").Doc(delivery.ID).Create(ctx, delivery)
+	if status.Code(err) == codes.AlreadyExists {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *WebhookDeliveryRepositoryImpl) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	docs, err := r.client.Collection("webhook_deliveries").
+		Where("status", "in", []string{string(entities.DeliveryPending), string(entities.DeliverySending)}).
+		Where("next_attempt_at", "<=", now).
+		OrderBy("next_attempt_at", firestore.Asc).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return r.documentsToDeliveries(docs), nil
+}
+
+// Claim leases a due delivery to this instance. It returns nil when the
+// delivery is finished or leased by another instance.
+func (r *WebhookDeliveryRepositoryImpl) Claim(ctx context.Context, id string, now, leaseUntil time.Time) (*entities.WebhookDelivery, error) {
+	docRef := r.client.Collection("webhook_deliveries").Doc(id)
+	var claimed *entities.WebhookDelivery
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		delivery, err := r.documentToDelivery(doc)
+		if err != nil {
+			return err
+		}
+
+		switch delivery.Status {
+		case entities.DeliveryPending:
+		case entities.DeliverySending:
+			if now.Before(delivery.LeaseUntil) {
+				return nil
+			}
+		default:
+			return nil
+		}
+
+		delivery.Status = entities.DeliverySending
+		delivery.LeaseUntil = leaseUntil
+		delivery.Attempts++
+		claimed = delivery
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "status", Value: string(delivery.Status)},
+			{Path: "lease_until", Value: delivery.LeaseUntil},
+			{Path: "attempts", Value: delivery.Attempts},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (r *WebhookDeliveryRepositoryImpl) Update(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	_, err := r.client.Collection("webhook_deliveries").Doc(delivery.ID).Set(ctx, delivery)
+	return err
+}
+
+func (r *WebhookDeliveryRepositoryImpl) ListByWebhook(ctx context.Context, webhookID string, limit int) ([]*entities.WebhookDelivery, error) {
+	docs, err := r.client.Collection("webhook_deliveries").
+		Where("webhook_id", "==", webhookID).
+		OrderBy("created_at", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return r.documentsToDeliveries(docs), nil
+}
+
+func (r *WebhookDeliveryRepositoryImpl) documentsToDeliveries(docs []*firestore.DocumentSnapshot) []*entities.WebhookDelivery {
+	var deliveries []*entities.WebhookDelivery
+	for _, doc := range docs {
+		delivery, err := r.documentToDelivery(doc)
+		if err != nil {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func (r *WebhookDeliveryRepositoryImpl) documentToDelivery(doc *firestore.DocumentSnapshot) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+	if err := doc.DataTo(&delivery); err != nil {
+		return nil, err
+	}
+	delivery.ID = doc.Ref.ID
+	return &delivery, nil
+}
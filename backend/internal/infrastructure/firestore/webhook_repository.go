@@ -0,0 +1,76 @@
+package firestore
+
+import (
+	"context"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+
+	"cloud.google.com/go/firestore"
+)
+
+type WebhookRepositoryImpl struct {
+	client *firestore.Client
+}
+
+func NewWebhookRepository(client *firestore.Client) repositories.WebhookRepository {
+	return &WebhookRepositoryImpl{client: client}
+}
+
+func (r *WebhookRepositoryImpl) Create(ctx context.Context, webhook *entities.Webhook) error {
+	_, err := r.client.Collection("webhooks").Doc(webhook.ID).Create(ctx, webhook)
+	return err
+}
+
+func (r *WebhookRepositoryImpl) GetByID(ctx context.Context, id string) (*entities.Webhook, error) {
+	doc, err := r.client.Collection("webhooks").Doc(id).Get(ctx)
+	if err != nil {
+		return nil, notFound(err, "webhook")
+	}
+	return r.documentToWebhook(doc)
+}
+
+func (r *WebhookRepositoryImpl) ListByRoom(ctx context.Context, roomID string) ([]*entities.Webhook, error) {
+	docs, err := r.client.Collection("webhooks").
+		Where("room_id", "==", roomID).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return r.documentsToWebhooks(docs), nil
+}
+
+func (r *WebhookRepositoryImpl) ListAll(ctx context.Context) ([]*entities.Webhook, error) {
+	docs, err := r.client.Collection("webhooks").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return r.documentsToWebhooks(docs), nil
+}
+
+func (r *WebhookRepositoryImpl) Delete(ctx context.Context, id string) error {
+	_, err := r.client.Collection("webhooks").Doc(id).Delete(ctx)
+	return err
+}
+
+func (r *WebhookRepositoryImpl) documentsToWebhooks(docs []*firestore.DocumentSnapshot) []*entities.Webhook {
+	var webhooks []*entities.Webhook
+	for _, doc := range docs {
+		webhook, err := r.documentToWebhook(doc)
+		if err != nil {
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks
+}
+
+func (r *WebhookRepositoryImpl) documentToWebhook(doc *firestore.DocumentSnapshot) (*entities.Webhook, error) {
+	var webhook entities.Webhook
+	if err := doc.DataTo(&webhook); err != nil {
+		return nil, err
+	}
+	webhook.ID = doc.Ref.ID
+	return &webhook, nil
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+// LogNotifier writes messages to w instead of sending them, for running the
+// server locally. Anyone who can read w can reset any password.
+type LogNotifier struct {
+	mu       sync.Mutex
+	w        io.Writer
+	resetURL string
+}
+
+func NewLogNotifier(w io.Writer, resetURL string) *LogNotifier {
+	return &LogNotifier{w: w, resetURL: resetURL}
+}
+
+func (n *LogNotifier) SendPasswordReset(ctx context.Context, user *entities.User, token string, expiresAt time.Time) error {
+	body, err := resetMessage(n.resetURL, token, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = fmt.Fprintf(n.w, "--- password reset for %s <%s> ---\r\n%s\r\n", user.Username, user.Email, body)
+	return err
+}
+
+func (n *LogNotifier) SendAccountLocked(ctx context.Context, user *entities.User, lockedUntil time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err := fmt.Fprintf(n.w, "--- account locked for %s <%s> ---\r\n%s\r\n", user.Username, user.Email, lockoutMessage(lockedUntil))
+	return err
+}
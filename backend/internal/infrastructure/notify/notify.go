@@ -0,0 +1,36 @@
+// Package notify delivers account messages such as password resets and
+// lockout warnings.
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// resetMessage is the text of a password reset message. With a resetURL
+// the token is sent as a link to it, otherwise as is.
+func resetMessage(resetURL, token string, expiresAt time.Time) (string, error) {
+	instructions := "Use this token to choose a new password:\r\n\r\n" + token
+	if resetURL != "" {
+		link, err := url.Parse(resetURL)
+		if err != nil {
+			return "", err
+		}
+		query := link.Query()
+		query.Set("token", token)
+		link.RawQuery = query.Encode()
+		instructions = "Open this link to choose a new password:\r\n\r\n" + link.String()
+	}
+
+	return fmt.Sprintf("Someone asked to reset the password of your chat account.\r\n\r\n"+
+		"%s\r\n\r\nIt expires at %s. If you did not ask for this, ignore this message.\r\n",
+		instructions, expiresAt.UTC().Format(time.RFC1123)), nil
+}
+
+func lockoutMessage(lockedUntil time.Time) string {
+	return fmt.Sprintf("Your chat account received too many logins with a wrong password, so logins "+
+		"are refused until %s.\r\n\r\nIf this was not you, someone may be guessing your password. "+
+		"Once you can log in again, consider choosing a stronger one.\r\n",
+		lockedUntil.UTC().Format(time.RFC1123))
+}
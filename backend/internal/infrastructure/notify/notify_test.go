@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPNotifier_SendPasswordReset(t *testing.T) {
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Addr:     "smtp.example.com:587",
+		Username: "chat",
+		Password: "secret",
+		From:     "Chat <noreply@example.com>",
+		ResetURL: "https://chat.example.com/reset?lang=en",
+	})
+
+	var sent []byte
+	var recipients []string
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.NotNil(t, a)
+		sent, recipients = msg, to
+		return nil
+	}
+
+	user := &entities.User{Username: "mariem", Email: "mariem@example.com"}
+	require.NoError(t, notifier.SendPasswordReset(context.Background(), user, "abc123", time.Now().Add(time.Hour)))
+
+	assert.Equal(t, []string{"mariem@example.com"}, recipients)
+	assert.Contains(t, string(sent), "To: <mariem@example.com>\r\n")
+	assert.Contains(t, string(sent), "https://chat.example.com/reset?lang=en&token=abc123")
+
+	t.Run("recipient cannot add headers", func(t *testing.T) {
+		user := &entities.User{Email: "a@example.com\r\nBcc: b@example.com"}
+		err := notifier.SendPasswordReset(context.Background(), user, "abc123", time.Now())
+		assert.Error(t, err)
+	})
+}
+
+func TestLogNotifier_SendPasswordReset(t *testing.T) {
+	var out bytes.Buffer
+	notifier := NewLogNotifier(&out, "")
+
+	user := &entities.User{Username: "mariem", Email: "mariem@example.com"}
+	require.NoError(t, notifier.SendPasswordReset(context.Background(), user, "abc123", time.Now().Add(time.Hour)))
+
+	assert.True(t, strings.Contains(out.String(), "mariem <mariem@example.com>"))
+	assert.Contains(t, out.String(), "abc123")
+}
+
+func TestSMTPNotifier_SendAccountLocked(t *testing.T) {
+	notifier := NewSMTPNotifier(SMTPConfig{Addr: "smtp.example.com:25", From: "noreply@example.com"})
+
+	var sent []byte
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Nil(t, a)
+		sent = msg
+		return nil
+	}
+
+	lockedUntil := time.Date(2030, 1, 2, 15, 4, 0, 0, time.UTC)
+	user := &entities.User{Username: "mariem", Email: "mariem@example.com"}
+	require.NoError(t, notifier.SendAccountLocked(context.Background(), user, lockedUntil))
+
+	assert.Contains(t, string(sent), "Subject: Your account is locked\r\n")
+	assert.Contains(t, string(sent), "Wed, 02 Jan 2030 15:04:00 UTC")
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+type SMTPConfig struct {
+	// Addr is the server's host:port.
+	Addr     string
+	Username string
+	Password string
+	From     string
+	// ResetURL is the page of the frontend that takes the reset token as
+	// its "token" query parameter.
+	ResetURL string
+}
+
+// SMTPNotifier sends mail through an SMTP server, with STARTTLS when the
+// server offers it.
+type SMTPNotifier struct {
+	config   SMTPConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{config: config, sendMail: smtp.SendMail}
+}
+
+func (n *SMTPNotifier) SendPasswordReset(ctx context.Context, user *entities.User, token string, expiresAt time.Time) error {
+	body, err := resetMessage(n.config.ResetURL, token, expiresAt)
+	if err != nil {
+		return err
+	}
+	return n.send(user.Email, "Reset your password", body)
+}
+
+func (n *SMTPNotifier) SendAccountLocked(ctx context.Context, user *entities.User, lockedUntil time.Time) error {
+	return n.send(user.Email, "Your account is locked", lockoutMessage(lockedUntil))
+}
+
+func (n *SMTPNotifier) send(to, subject, body string) error {
+	msg, err := n.message(to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		host, _, _ := net.SplitHostPort(n.config.Addr)
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, host)
+	}
+	return n.sendMail(n.config.Addr, auth, n.config.From, []string{to}, msg)
+}
+
+func (n *SMTPNotifier) message(to, subject, body string) ([]byte, error) {
+	// Addresses are parsed so that one holding a line break cannot add
+	// headers of its own.
+	toAddress, err := mail.ParseAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %v", err)
+	}
+	fromAddress, err := mail.ParseAddress(n.config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %v", err)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", fromAddress)
+	fmt.Fprintf(&msg, "To: %s\r\n", toAddress)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(body)
+	return []byte(msg.String()), nil
+}
@@ -0,0 +1,158 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/infrastructure/oidc/oidctest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "https://chat.example.com/v1/auth/oidc/callback"
+
+func newTestProvider(t *testing.T, claims map[string]interface{}) (*Provider, *oidctest.Server) {
+	idp := oidctest.NewServer(claims)
+	t.Cleanup(idp.Close)
+
+	provider, err := Discover(context.Background(), Config{
+		IssuerURL:    idp.URL,
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  redirectURL,
+	}, idp.Client())
+	require.NoError(t, err)
+	return provider, idp
+}
+
+// authorize visits the authorization URL and returns the code and state
+// the provider redirects back with.
+func authorize(t *testing.T, provider *Provider, nonce, verifier string) (string, string) {
+	challenge := sha256.Sum256([]byte(verifier))
+	authURL := provider.AuthCodeURL("state-1", nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "chat.example.com", location.Host)
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	provider, _ := newTestProvider(t, map[string]interface{}{
+		"sub":                "user-42",
+		"preferred_username": "mariem",
+	})
+	assert.NotEmpty(t, provider.Issuer())
+
+	code, state := authorize(t, provider, "nonce-1", "verifier-1")
+	assert.Equal(t, "state-1", state)
+
+	claims, err := provider.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", claims["sub"])
+	assert.Equal(t, "mariem", claims["preferred_username"])
+
+	_, err = provider.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	assert.Error(t, err, "codes are single-use")
+}
+
+func TestProvider_Exchange_Rejections(t *testing.T) {
+	provider, _ := newTestProvider(t, map[string]interface{}{"sub": "user-42"})
+
+	code, _ := authorize(t, provider, "nonce-1", "verifier-1")
+	_, err := provider.Exchange(context.Background(), code, "another-verifier", "nonce-1")
+	assert.ErrorContains(t, err, "PKCE")
+
+	code, _ = authorize(t, provider, "nonce-1", "verifier-1")
+	_, err = provider.Exchange(context.Background(), code, "verifier-1", "nonce-2")
+	assert.ErrorContains(t, err, "nonce")
+}
+
+func TestProvider_Verify(t *testing.T) {
+	provider, idp := newTestProvider(t, nil)
+	now := time.Now()
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":   idp.URL,
+			"aud":   oidctest.ClientID,
+			"sub":   "user-42",
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Minute).Unix(),
+			"nonce": "n",
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string]interface{}
+		wantErr   string
+	}{
+		{name: "valid"},
+		{name: "audience list", overrides: map[string]interface{}{"aud": []string{"other", oidctest.ClientID}}},
+		{name: "other issuer", overrides: map[string]interface{}{"iss": "https://evil.example.com"}, wantErr: "issuer"},
+		{name: "other audience", overrides: map[string]interface{}{"aud": "other"}, wantErr: "not for this client"},
+		{name: "other authorized party", overrides: map[string]interface{}{"azp": "other"}, wantErr: "another client"},
+		{name: "expired", overrides: map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()}, wantErr: "expired"},
+		{name: "issued in the future", overrides: map[string]interface{}{"iat": now.Add(time.Hour).Unix()}, wantErr: "future"},
+		{name: "no subject", overrides: map[string]interface{}{"sub": ""}, wantErr: "subject"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.verify(context.Background(), idp.Sign(claims(tt.overrides)), "n", now)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	token := idp.Sign(claims(nil))
+	_, err := provider.verify(context.Background(), token[:len(token)-4]+"AAAA", "n", now)
+	assert.ErrorContains(t, err, "signature")
+}
+
+func TestProvider_KeyRotation(t *testing.T) {
+	provider, idp := newTestProvider(t, map[string]interface{}{"sub": "user-42"})
+
+	code, _ := authorize(t, provider, "n", "v")
+	_, err := provider.Exchange(context.Background(), code, "v", "n")
+	require.NoError(t, err)
+
+	idp.RotateKey()
+	code, _ = authorize(t, provider, "n", "v")
+	_, err = provider.Exchange(context.Background(), code, "v", "n")
+	assert.ErrorContains(t, err, "unknown signing key", "keys were fetched less than a minute ago")
+
+	provider.keysFetchedAt = time.Now().Add(-keyRefreshInterval)
+	code, _ = authorize(t, provider, "n", "v")
+	_, err = provider.Exchange(context.Background(), code, "v", "n")
+	assert.NoError(t, err)
+}
+
+func TestDiscover_IssuerMismatch(t *testing.T) {
+	idp := oidctest.NewServer(nil)
+	defer idp.Close()
+
+	_, err := Discover(context.Background(), Config{IssuerURL: idp.URL + "/other"}, idp.Client())
+	assert.Error(t, err)
+}
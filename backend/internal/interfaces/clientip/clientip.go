@@ -0,0 +1,93 @@
+// Package clientip finds the address of a client that may be behind
+// reverse proxies.
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies are the reverse proxies whose X-Forwarded-For header is
+// believed. A nil *TrustedProxies trusts none, so the address is always the
+// one the connection came from.
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// ParseTrustedProxies reads proxy addresses and CIDR ranges such as
+// "10.0.0.0/8". Empty entries are skipped.
+func ParseTrustedProxies(entries []string) (*TrustedProxies, error) {
+	proxies := &TrustedProxies{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy range %q: %v", entry, err)
+			}
+			proxies.prefixes = append(proxies.prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy address %q: %v", entry, err)
+		}
+		addr = addr.Unmap()
+		proxies.prefixes = append(proxies.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
+
+func (p *TrustedProxies) trusts(addr netip.Addr) bool {
+	if p == nil {
+		return false
+	}
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientAddress returns the address of the client given the address the
+// connection came from, as "host:port" or a bare host, and the values of
+// its X-Forwarded-For headers. Each proxy appends the address it was
+// reached from, so the hops are read right to left while they are trusted
+// proxies, and the first one that is not is the client. Hops to the left of
+// it were written by the client and are ignored.
+func (p *TrustedProxies) ClientAddress(remoteAddr string, forwardedFor []string) string {
+	host := remoteAddr
+	if h, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		host = h
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	addr = addr.Unmap()
+
+	var hops []string
+	for _, value := range forwardedFor {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0 && p.trusts(addr); i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		addr = hop.Unmap()
+	}
+	return addr.String()
+}
+
+// Request returns the address of the client that sent r.
+func (p *TrustedProxies) Request(r *http.Request) string {
+	return p.ClientAddress(r.RemoteAddr, r.Header.Values("X-Forwarded-For"))
+}
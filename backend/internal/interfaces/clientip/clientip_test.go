@@ -0,0 +1,55 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies_ClientAddress(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.0.2.1", ""})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct client", "203.0.113.7:5555", nil, "203.0.113.7"},
+		{"untrusted peer cannot forward", "203.0.113.7:5555", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:5555", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"client written hops are ignored", "10.0.0.2:5555", []string{"198.51.100.1, 203.0.113.7"}, "203.0.113.7"},
+		{"proxy chain", "10.0.0.2:5555", []string{"203.0.113.7, 192.0.2.1", "10.1.1.1"}, "203.0.113.7"},
+		{"all hops trusted", "10.0.0.2:5555", []string{"10.0.0.3"}, "10.0.0.3"},
+		{"malformed hop", "10.0.0.2:5555", []string{"203.0.113.7, unknown"}, "10.0.0.2"},
+		{"ipv6 peer", "[2001:db8::1]:443", nil, "2001:db8::1"},
+		{"bare host", "203.0.113.7", nil, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, proxies.ClientAddress(tt.remoteAddr, tt.forwardedFor))
+		})
+	}
+
+	t.Run("nil trusts nobody", func(t *testing.T) {
+		var none *TrustedProxies
+		assert.Equal(t, "10.0.0.2", none.ClientAddress("10.0.0.2:5555", []string{"203.0.113.7"}))
+	})
+
+	t.Run("request", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "10.0.0.2:5555"
+		r.Header.Add("X-Forwarded-For", "198.51.100.1, 203.0.113.7")
+		assert.Equal(t, "203.0.113.7", proxies.Request(r))
+	})
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy.internal"})
+	assert.Error(t, err)
+}
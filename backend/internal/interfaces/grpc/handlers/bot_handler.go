@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) CreateBot(ctx context.Context, req *pb.CreateBotRequest) (*pb.BotResponse, error) {
+	admin, err := h.authenticate(ctx, req.GetToken(), "CreateBot", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Creating bot %s by user %s", req.GetUsername(), admin.ID)
+
+	bot, key, err := h.botUseCase.CreateBot(ctx, admin, req.GetUsername(), toBotScopes(req.GetScopes()))
+	if err != nil {
+		log.Printf("Error creating bot: %v", err)
+		return nil, statusError(err)
+	}
+
+	resp := toBotResponse(bot)
+	resp.ApiKeyId = key.Key.ID
+	resp.ApiKey = key.Secret
+	return resp, nil
+}
+
+func (h *ChatHandler) UpdateBotScopes(ctx context.Context, req *pb.UpdateBotScopesRequest) (*pb.BotResponse, error) {
+	admin, err := h.authenticate(ctx, req.GetToken(), "UpdateBotScopes", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Updating scopes of bot %s by user %s", req.GetBotId(), admin.ID)
+
+	bot, err := h.botUseCase.UpdateBotScopes(ctx, admin, req.GetBotId(), toBotScopes(req.GetScopes()))
+	if err != nil {
+		log.Printf("Error updating bot scopes: %v", err)
+		return nil, statusError(err)
+	}
+
+	return toBotResponse(bot), nil
+}
+
+func (h *ChatHandler) CreateBotKey(ctx context.Context, req *pb.BotKeyRequest) (*pb.BotResponse, error) {
+	admin, err := h.authenticate(ctx, req.GetToken(), "CreateBotKey", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Issuing api key for bot %s by user %s", req.GetBotId(), admin.ID)
+
+	key, err := h.botUseCase.CreateBotKey(ctx, admin, req.GetBotId())
+	if err != nil {
+		log.Printf("Error issuing bot api key: %v", err)
+		return nil, statusError(err)
+	}
+
+	return &pb.BotResponse{
+		BotId:    key.Key.UserID,
+		ApiKeyId: key.Key.ID,
+		ApiKey:   key.Secret,
+	}, nil
+}
+
+func (h *ChatHandler) RevokeBotKey(ctx context.Context, req *pb.RevokeBotKeyRequest) (*pb.BotResponse, error) {
+	admin, err := h.authenticate(ctx, req.GetToken(), "RevokeBotKey", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Revoking api key %s by user %s", req.GetApiKeyId(), admin.ID)
+
+	if err := h.botUseCase.RevokeBotKey(ctx, admin, req.GetApiKeyId()); err != nil {
+		log.Printf("Error revoking bot api key: %v", err)
+		return nil, statusError(err)
+	}
+
+	return &pb.BotResponse{ApiKeyId: req.GetApiKeyId()}, nil
+}
+
+func toBotScopes(scopes *pb.BotScopes) *entities.BotScopes {
+	if scopes == nil {
+		return nil
+	}
+	return &entities.BotScopes{
+		RoomIDs: scopes.GetRoomIds(),
+		RPCs:    scopes.GetRpcs(),
+	}
+}
+
+func toBotResponse(bot *entities.User) *pb.BotResponse {
+	resp := &pb.BotResponse{
+		BotId:    bot.ID,
+		Username: bot.Username,
+	}
+	if bot.BotScopes != nil {
+		resp.Scopes = &pb.BotScopes{
+			RoomIds: bot.BotScopes.RoomIDs,
+			Rpcs:    bot.BotScopes.RPCs,
+		}
+	}
+	return resp
+}
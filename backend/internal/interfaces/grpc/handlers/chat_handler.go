@@ -2,66 +2,142 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/interfaces/clientip"
 	pb "chat-app/backend/internal/interfaces/grpc/proto"
 	"chat-app/backend/internal/usecases"
 )
 
 type ChatHandler struct {
 	pb.UnimplementedChatServiceServer
-	messageUseCase usecases.MessageUseCase
-	authUseCase    usecases.AuthUseCase
+	messageUseCase   usecases.MessageUseCase
+	authUseCase      usecases.AuthUseCase
+	roomUseCase      usecases.RoomUseCase
+	scheduledUseCase usecases.ScheduledMessageUseCase
+	pollUseCase      usecases.PollUseCase
+	botUseCase       usecases.BotUseCase
+	webhookUseCase   usecases.WebhookUseCase
+	incomingUseCase  usecases.IncomingWebhookUseCase
+	typingUseCase    usecases.TypingUseCase
+	profileUseCase   usecases.ProfileUseCase
+	chatConfig       ChatStreamConfig
+	trustedProxies   *clientip.TrustedProxies
 }
 
-func NewChatHandler(messageUseCase usecases.MessageUseCase, authUseCase usecases.AuthUseCase) *ChatHandler {
-	return &ChatHandler{
+type ChatHandlerOption func(*ChatHandler)
+
+func WithRoomUseCase(roomUseCase usecases.RoomUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.roomUseCase = roomUseCase
+	}
+}
+
+func WithScheduledMessageUseCase(scheduledUseCase usecases.ScheduledMessageUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.scheduledUseCase = scheduledUseCase
+	}
+}
+
+func WithPollUseCase(pollUseCase usecases.PollUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.pollUseCase = pollUseCase
+	}
+}
+
+func WithBotUseCase(botUseCase usecases.BotUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.botUseCase = botUseCase
+	}
+}
+
+func WithWebhookUseCase(webhookUseCase usecases.WebhookUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.webhookUseCase = webhookUseCase
+	}
+}
+
+func WithTypingUseCase(typingUseCase usecases.TypingUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.typingUseCase = typingUseCase
+	}
+}
+
+func WithChatStreamConfig(config ChatStreamConfig) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.chatConfig = config
+	}
+}
+
+func WithIncomingWebhookUseCase(incomingUseCase usecases.IncomingWebhookUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.incomingUseCase = incomingUseCase
+	}
+}
+
+// WithProfileUseCase serves profiles and adds the author's display name
+// and avatar to messages.
+func WithProfileUseCase(profileUseCase usecases.ProfileUseCase) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.profileUseCase = profileUseCase
+	}
+}
+
+// WithTrustedProxies sets the reverse proxies whose X-Forwarded-For
+// metadata is believed when recording the address of a client.
+func WithTrustedProxies(proxies *clientip.TrustedProxies) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.trustedProxies = proxies
+	}
+}
+
+func NewChatHandler(messageUseCase usecases.MessageUseCase, authUseCase usecases.AuthUseCase, opts ...ChatHandlerOption) *ChatHandler {
+	h := &ChatHandler{
 		messageUseCase: messageUseCase,
 		authUseCase:    authUseCase,
+		chatConfig:     DefaultChatStreamConfig(),
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 func (h *ChatHandler) Register(ctx context.Context, req *pb.UserRequest) (*pb.AuthResponse, error) {
 	log.Printf("Register attempt for user: %s", req.GetUsername())
 
-	token, err := h.authUseCase.Register(ctx, req.GetUsername(), req.GetPassword())
+	token, err := h.authUseCase.Register(ctx, req.GetUsername(), req.GetPassword(), h.sessionMetadata(ctx, req.GetDeviceLabel()))
 	if err != nil {
-		return &pb.AuthResponse{
-			Success: false,
-			Error:   err.Error(),
-		}, nil
+		return nil, authError(err)
 	}
 
 	user, _ := h.authUseCase.ValidateToken(ctx, token.Token)
 
-	return &pb.AuthResponse{
-		Success:  true,
-		Token:    token.Token,
-		UserId:   user.ID,
-		Username: user.Username,
-	}, nil
+	return authResponse(token, user), nil
 }
 
 func (h *ChatHandler) Login(ctx context.Context, req *pb.UserRequest) (*pb.AuthResponse, error) {
 	log.Printf("Login attempt for user: %s", req.GetUsername())
 
-	token, err := h.authUseCase.Login(ctx, req.GetUsername(), req.GetPassword())
+	token, err := h.authUseCase.Login(ctx, req.GetUsername(), req.GetPassword(), h.sessionMetadata(ctx, req.GetDeviceLabel()))
 	if err != nil {
+		return nil, authError(err)
+	}
+	if token.Challenge != "" {
 		return &pb.AuthResponse{
-			Success: false,
-			Error:   err.Error(),
+			Success:   true,
+			UserId:    token.UserID,
+			Challenge: token.Challenge,
+			ExpiresAt: token.ExpiresAt.Format(time.RFC3339),
 		}, nil
 	}
 
 	user, _ := h.authUseCase.ValidateToken(ctx, token.Token)
 
-	return &pb.AuthResponse{
-		Success:  true,
-		Token:    token.Token,
-		UserId:   user.ID,
-		Username: user.Username,
-	}, nil
+	return authResponse(token, user), nil
 }
 
 func (h *ChatHandler) ValidateToken(ctx context.Context, req *pb.TokenRequest) (*pb.UserResponse, error) {
@@ -76,28 +152,91 @@ func (h *ChatHandler) ValidateToken(ctx context.Context, req *pb.TokenRequest) (
 		UserId:   user.ID,
 		Username: user.Username,
 		Valid:    true,
+		IsBot:    user.IsBot,
 	}, nil
 }
 
+// authenticate validates the token and, for bot accounts, checks that the
+// bot's scopes allow calling rpc in roomID.
+func (h *ChatHandler) authenticate(ctx context.Context, token, rpc, roomID string) (*entities.User, error) {
+	user, err := h.authUseCase.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if err := botScopeError(user, rpc, roomID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// authenticateIn is authenticate for RPCs that name a poll, webhook or
+// other resource of a room rather than the room. For bots, roomOf loads the
+// resource and returns its room, which their scopes are checked against.
+func (h *ChatHandler) authenticateIn(ctx context.Context, token, rpc string, roomOf func(user *entities.User) (string, error)) (*entities.User, error) {
+	user, err := h.authenticate(ctx, token, rpc, "")
+	if err != nil || !user.IsBot {
+		return user, err
+	}
+	roomID, err := roomOf(user)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if err := botScopeError(user, rpc, roomID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// botScopeError refuses a bot whose scopes do not allow rpc in roomID.
+func botScopeError(user *entities.User, rpc, roomID string) error {
+	if !user.IsBot || user.BotScopes.Allows(rpc, roomID) {
+		return nil
+	}
+	log.Printf("Bot %s denied %s in room %s", user.ID, rpc, roomID)
+	return domainStatus(&usecases.Error{
+		Kind:    usecases.KindPermissionDenied,
+		Reason:  "BOT_SCOPE",
+		Message: fmt.Sprintf("bot is not allowed to call %s in this room", rpc),
+	}).Err()
+}
+
 func (h *ChatHandler) SendMessage(ctx context.Context, req *pb.MessageRequest) (*pb.MessageResponse, error) {
 	log.Printf("Storing message from user: %s", req.GetUserId())
 
-	message, err := h.messageUseCase.SendMessage(ctx, req.GetUserId(), req.GetUsername(), req.GetContent(), req.GetRoomId())
+	params := entities.MessageCreateParams{
+		UserID:   req.GetUserId(),
+		Username: req.GetUsername(),
+		Content:  req.GetContent(),
+		RoomID:   req.GetRoomId(),
+		TTL:      time.Duration(req.GetTtlSeconds()) * time.Second,
+	}
+	if token := req.GetToken(); token != "" {
+		user, err := h.authenticate(ctx, token, "SendMessage", req.GetRoomId())
+		if err != nil {
+			return nil, statusError(err)
+		}
+		params.UserID, params.Username, params.IsBot = user.ID, user.Username, user.IsBot
+	} else if params.TTL != 0 {
+		return nil, statusError(&usecases.Error{
+			Kind:    usecases.KindUnauthenticated,
+			Reason:  "TOKEN_REQUIRED",
+			Message: "ttl_seconds needs a session token",
+		})
+	} else {
+		params.Unverified = true
+	}
+
+	message, err := h.messageUseCase.SendMessageWithParams(ctx, params)
 	if err != nil {
 		log.Printf("Error storing message: %v", err)
-		return nil, err
+		return nil, statusError(err)
 	}
 
 	log.Printf("Message stored with ID: %s", message.ID)
 
-	return &pb.MessageResponse{
-		MessageId: message.ID,
-		UserId:    message.UserID,
-		Username:  message.Username,
-		Content:   message.Content,
-		RoomId:    message.RoomID,
-		Timestamp: message.Timestamp.Format(time.RFC3339),
-	}, nil
+	resp := toMessageResponse(message, message.Username)
+	h.setAuthors(ctx, resp)
+	return resp, nil
 }
 
 func (h *ChatHandler) StreamMessages(req *pb.StreamRequest, stream pb.ChatService_StreamMessagesServer) error {
@@ -105,10 +244,10 @@ func (h *ChatHandler) StreamMessages(req *pb.StreamRequest, stream pb.ChatServic
 	roomID := req.GetRoomId()
 
 	if token := req.GetToken(); token != "" {
-		_, err := h.authUseCase.ValidateToken(ctx, token)
+		_, err := h.authenticate(ctx, token, "StreamMessages", roomID)
 		if err != nil {
 			log.Printf("❌ Stream auth failed: %v", err)
-			return err
+			return statusError(err)
 		}
 	}
 
@@ -117,11 +256,12 @@ func (h *ChatHandler) StreamMessages(req *pb.StreamRequest, stream pb.ChatServic
 	messageChan, err := h.messageUseCase.StreamMessages(ctx, roomID)
 	if err != nil {
 		log.Printf("❌ Stream error: %v", err)
-		return err
+		return statusError(err)
 	}
 
 	log.Printf("✅ Stream connected, waiting for messages...")
 
+	authors := h.newAuthorCache()
 	for {
 		select {
 		case <-ctx.Done():
@@ -133,21 +273,14 @@ func (h *ChatHandler) StreamMessages(req *pb.StreamRequest, stream pb.ChatServic
 				return nil
 			}
 
-			log.Printf("📨 Stream received message: %s", message.Content)
-
-			username := "user_" + message.Username
-			if user, err := h.authUseCase.ValidateToken(ctx, req.GetToken()); err == nil {
-				username = user.Username
+			if message.Event == entities.MessageUpdated && !req.GetIncludeUpdates() {
+				continue
 			}
 
-			resp := &pb.MessageResponse{
-				MessageId: message.ID,
-				UserId:    message.UserID,
-				Username:  username,
-				Content:   message.Content,
-				RoomId:    message.RoomID,
-				Timestamp: message.Timestamp.Format(time.RFC3339),
-			}
+			log.Printf("📨 Stream received message: %s", message.Content)
+
+			resp := toMessageResponse(message, message.Username)
+			authors.setAuthor(ctx, resp)
 
 			log.Printf("🚀 Sending message to client: %s", resp.GetContent())
 
@@ -165,39 +298,84 @@ func (h *ChatHandler) GetMessageHistory(ctx context.Context, req *pb.HistoryRequ
 	roomID := req.GetRoomId()
 
 	if token := req.GetToken(); token != "" {
-		_, err := h.authUseCase.ValidateToken(ctx, token)
+		_, err := h.authenticate(ctx, token, "GetMessageHistory", roomID)
 		if err != nil {
-			return nil, err
+			return nil, statusError(err)
 		}
 	}
 
 	log.Printf("Fetching message history for room: %s", roomID)
 
-	messages, err := h.messageUseCase.GetMessageHistory(ctx, roomID, 50)
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	messages, err := h.messageUseCase.GetMessageHistory(ctx, roomID, limit)
 	if err != nil {
 		log.Printf("Error fetching history: %v", err)
-		return nil, err
+		return nil, statusError(err)
 	}
 
 	var pbMessages []*pb.MessageResponse
 	for i := len(messages) - 1; i >= 0; i-- {
 		message := messages[i]
+		pbMessages = append(pbMessages, toMessageResponse(message, message.Username))
+	}
+	h.setAuthors(ctx, pbMessages...)
 
-		username := "user_" + message.Username
-		if user, err := h.authUseCase.ValidateToken(ctx, req.GetToken()); err == nil {
-			username = user.Username
-		}
+	log.Printf("Returning %d historical messages", len(pbMessages))
+	return &pb.HistoryResponse{Messages: pbMessages}, nil
+}
+
+func toMessageResponse(message *entities.Message, username string) *pb.MessageResponse {
+	resp := &pb.MessageResponse{
+		MessageId: message.ID,
+		UserId:    message.UserID,
+		Username:  username,
+		Content:   message.Content,
+		RoomId:    message.RoomID,
+		Timestamp: message.Timestamp.Format(time.RFC3339),
+		Event:     string(message.Event),
+		Pinned:    message.Pinned,
+		PinnedBy:  message.PinnedBy,
+		Ephemeral: message.Ephemeral,
+		IsBot:     message.IsBot,
+	}
+	if !message.PinnedAt.IsZero() {
+		resp.PinnedAt = message.PinnedAt.Format(time.RFC3339)
+	}
+	if !message.ExpiresAt.IsZero() {
+		resp.ExpiresAt = message.ExpiresAt.Format(time.RFC3339)
+	}
+	if message.Poll != nil {
+		resp.Poll = toPollResponse(message.Poll)
+	}
 
-		pbMessages = append(pbMessages, &pb.MessageResponse{
-			MessageId: message.ID,
-			UserId:    message.UserID,
-			Username:  username,
-			Content:   message.Content,
-			RoomId:    message.RoomID,
-			Timestamp: message.Timestamp.Format(time.RFC3339),
+	for _, attachment := range message.Attachments {
+		resp.Attachments = append(resp.Attachments, &pb.Attachment{
+			AttachmentId:    attachment.ID,
+			FileName:        attachment.FileName,
+			ContentType:     attachment.ContentType,
+			Size:            attachment.Size,
+			Width:           int32(attachment.Width),
+			Height:          int32(attachment.Height),
+			ThumbnailId:     attachment.ThumbnailID,
+			ThumbnailWidth:  int32(attachment.ThumbnailWidth),
+			ThumbnailHeight: int32(attachment.ThumbnailHeight),
+			Status:          string(attachment.Status),
 		})
 	}
 
-	log.Printf("Returning %d historical messages", len(pbMessages))
-	return &pb.HistoryResponse{Messages: pbMessages}, nil
+	for _, preview := range message.LinkPreviews {
+		resp.LinkPreviews = append(resp.LinkPreviews, &pb.LinkPreview{
+			Url:         preview.URL,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageUrl:    preview.ImageURL,
+			SiteName:    preview.SiteName,
+		})
+	}
+
+	return resp
 }
@@ -7,11 +7,14 @@ import (
 
 	"chat-app/backend/internal/domain/entities"
 	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/usecases"
 	"chat-app/backend/internal/usecases/mocks"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestChatHandler_Register(t *testing.T) {
@@ -36,7 +39,7 @@ func TestChatHandler_Register(t *testing.T) {
 		}
 
 		mockAuthUC.EXPECT().
-			Register(ctx, "mariem", "password_mariem").
+			Register(ctx, "mariem", "password_mariem", gomock.Any()).
 			Return(token, nil)
 
 		mockAuthUC.EXPECT().
@@ -57,8 +60,8 @@ func TestChatHandler_Register(t *testing.T) {
 
 	t.Run("registration failure", func(t *testing.T) {
 		mockAuthUC.EXPECT().
-			Register(ctx, "mariem", "password_mariem").
-			Return(nil, assert.AnError)
+			Register(ctx, "mariem", "password_mariem", gomock.Any()).
+			Return(nil, &usecases.Error{Kind: usecases.KindAlreadyExists, Reason: "ALREADY_EXISTS", Message: "username already exists"})
 
 		req := &pb.UserRequest{
 			Username: "mariem",
@@ -66,9 +69,15 @@ func TestChatHandler_Register(t *testing.T) {
 		}
 
 		resp, err := handler.Register(ctx, req)
-		require.NoError(t, err)
-		assert.False(t, resp.Success)
-		assert.Contains(t, resp.Error, "error")
+		assert.Nil(t, resp)
+		st := status.Convert(err)
+		assert.Equal(t, codes.AlreadyExists, st.Code())
+		assert.Equal(t, "username already exists", st.Message())
+
+		legacy := legacyAuthResponse(st)
+		require.NotNil(t, legacy)
+		assert.False(t, legacy.Success)
+		assert.Equal(t, "username already exists", legacy.Error)
 	})
 }
 
@@ -94,7 +103,7 @@ func TestChatHandler_Login(t *testing.T) {
 		}
 
 		mockAuthUC.EXPECT().
-			Login(ctx, "mariem", "password_mariem").
+			Login(ctx, "mariem", "password_mariem", gomock.Any()).
 			Return(token, nil)
 
 		mockAuthUC.EXPECT().
@@ -114,8 +123,8 @@ func TestChatHandler_Login(t *testing.T) {
 
 	t.Run("login failure", func(t *testing.T) {
 		mockAuthUC.EXPECT().
-			Login(ctx, "mariem", "password_mariem").
-			Return(nil, assert.AnError)
+			Login(ctx, "mariem", "password_mariem", gomock.Any()).
+			Return(nil, usecases.ErrUnauthenticated)
 
 		req := &pb.UserRequest{
 			Username: "mariem",
@@ -123,12 +132,26 @@ func TestChatHandler_Login(t *testing.T) {
 		}
 
 		resp, err := handler.Login(ctx, req)
-		require.NoError(t, err)
-		assert.False(t, resp.Success)
-		assert.Contains(t, resp.Error, "error")
+		assert.Nil(t, resp)
+		st := status.Convert(err)
+		assert.Equal(t, codes.Unauthenticated, st.Code())
+
+		legacy := legacyAuthResponse(st)
+		require.NotNil(t, legacy)
+		assert.False(t, legacy.Success)
+		assert.Equal(t, "unauthenticated", legacy.Error)
 	})
 }
 
+func legacyAuthResponse(st *status.Status) *pb.AuthResponse {
+	for _, detail := range st.Details() {
+		if resp, ok := detail.(*pb.AuthResponse); ok {
+			return resp
+		}
+	}
+	return nil
+}
+
 func TestChatHandler_SendMessage(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -138,6 +161,13 @@ func TestChatHandler_SendMessage(t *testing.T) {
 	handler := NewChatHandler(mockMsgUC, mockAuthUC)
 
 	ctx := context.Background()
+	unverified := entities.MessageCreateParams{
+		UserID:     "user123",
+		Username:   "testuser",
+		Content:    "Hello world",
+		RoomID:     "room123",
+		Unverified: true,
+	}
 
 	t.Run("successful message send", func(t *testing.T) {
 		message := &entities.Message{
@@ -150,7 +180,7 @@ func TestChatHandler_SendMessage(t *testing.T) {
 		}
 
 		mockMsgUC.EXPECT().
-			SendMessage(ctx, "user123", "testuser", "Hello world", "room123").
+			SendMessageWithParams(ctx, unverified).
 			Return(message, nil)
 
 		req := &pb.MessageRequest{
@@ -168,7 +198,7 @@ func TestChatHandler_SendMessage(t *testing.T) {
 
 	t.Run("message send error", func(t *testing.T) {
 		mockMsgUC.EXPECT().
-			SendMessage(ctx, "user123", "testuser", "Hello world", "room123").
+			SendMessageWithParams(ctx, unverified).
 			Return(nil, assert.AnError)
 
 		req := &pb.MessageRequest{
@@ -182,4 +212,122 @@ func TestChatHandler_SendMessage(t *testing.T) {
 		require.Error(t, err)
 		assert.Nil(t, resp)
 	})
+
+	t.Run("ttl needs a token", func(t *testing.T) {
+		resp, err := handler.SendMessage(ctx, &pb.MessageRequest{
+			UserId:     "user123",
+			Content:    "Hello world",
+			RoomId:     "room123",
+			TtlSeconds: 60,
+		})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Nil(t, resp)
+	})
+
+	bot := &entities.User{
+		ID:        "bot1",
+		Username:  "deploybot",
+		IsBot:     true,
+		BotScopes: &entities.BotScopes{RoomIDs: []string{"deploys"}, RPCs: []string{"SendMessage"}},
+	}
+
+	t.Run("bots post as themselves", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "bot_key").Return(bot, nil)
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(ctx, entities.MessageCreateParams{
+				UserID:   "bot1",
+				Username: "deploybot",
+				Content:  "v1.2 shipped",
+				RoomID:   "deploys",
+				IsBot:    true,
+			}).
+			Return(&entities.Message{ID: "msg456", UserID: "bot1", Username: "deploybot", IsBot: true}, nil)
+
+		resp, err := handler.SendMessage(ctx, &pb.MessageRequest{
+			Content: "v1.2 shipped",
+			RoomId:  "deploys",
+			Token:   "bot_key",
+		})
+		require.NoError(t, err)
+		assert.True(t, resp.IsBot)
+	})
+
+	t.Run("bot scopes limit rooms", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "bot_key").Return(bot, nil)
+
+		resp, err := handler.SendMessage(ctx, &pb.MessageRequest{
+			Content: "wrong room",
+			RoomId:  "general",
+			Token:   "bot_key",
+		})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestChatHandler_BotScopesOfRoomResources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	mockPollUC := mocks.NewMockPollUseCase(ctrl)
+	handler := NewChatHandler(nil, mockAuthUC, WithPollUseCase(mockPollUC))
+
+	ctx := context.Background()
+	bot := &entities.User{
+		ID:        "bot1",
+		Username:  "deploybot",
+		IsBot:     true,
+		BotScopes: &entities.BotScopes{RoomIDs: []string{"deploys"}, RPCs: []string{"Vote"}},
+	}
+
+	t.Run("poll in another room", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "bot_key").Return(bot, nil)
+		mockPollUC.EXPECT().GetPoll(ctx, "poll1").Return(&entities.Message{ID: "poll1", RoomID: "general"}, nil)
+
+		_, err := handler.Vote(ctx, &pb.VoteRequest{Token: "bot_key", MessageId: "poll1", OptionIds: []string{"a"}})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("poll in a room of the bot", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "bot_key").Return(bot, nil)
+		mockPollUC.EXPECT().GetPoll(ctx, "poll2").Return(&entities.Message{ID: "poll2", RoomID: "deploys"}, nil)
+		mockPollUC.EXPECT().Vote(ctx, bot, "poll2", []string{"a"}).Return(&entities.Message{ID: "poll2", RoomID: "deploys"}, nil)
+
+		resp, err := handler.Vote(ctx, &pb.VoteRequest{Token: "bot_key", MessageId: "poll2", OptionIds: []string{"a"}})
+		require.NoError(t, err)
+		assert.Equal(t, "poll2", resp.MessageId)
+	})
+
+	t.Run("account rpcs are scoped too", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "bot_key").Return(bot, nil)
+
+		_, err := handler.ListSessions(ctx, &pb.TokenRequest{Token: "bot_key"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestChatHandler_GetMessageHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	handler := NewChatHandler(mockMsgUC, mockAuthUC)
+
+	ctx := context.Background()
+	viewer := &entities.User{ID: "viewer", Username: "viewer"}
+
+	// The token is checked once, not once per message.
+	mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(viewer, nil)
+	mockMsgUC.EXPECT().GetMessageHistory(ctx, "general", 50).Return([]*entities.Message{
+		{ID: "msg2", UserID: "user456", Username: "sam", Timestamp: time.Now()},
+		{ID: "msg1", UserID: "user123", Username: "mariem", Timestamp: time.Now()},
+	}, nil)
+
+	resp, err := handler.GetMessageHistory(ctx, &pb.HistoryRequest{RoomId: "general", Token: "test-token"})
+	require.NoError(t, err)
+	require.Len(t, resp.Messages, 2)
+	assert.Equal(t, "mariem", resp.Messages[0].Username)
+	assert.Equal(t, "sam", resp.Messages[1].Username)
 }
@@ -0,0 +1,458 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ChatStreamConfig struct {
+	// DefaultWindow is the number of unacknowledged events sent to clients
+	// that do not ask for a window in their hello.
+	DefaultWindow int
+	MaxWindow     int
+	// QueueSize bounds the results, errors and typing indicators waiting for
+	// window space. A client that falls this far behind is disconnected with
+	// RESOURCE_EXHAUSTED. Room messages, history replay included, are not
+	// queued: their subscriptions wait for window space instead.
+	QueueSize        int
+	MaxSubscriptions int
+	// DrainTimeout bounds how long queued events are still delivered after
+	// the client closes its side of the stream.
+	DrainTimeout time.Duration
+}
+
+func DefaultChatStreamConfig() ChatStreamConfig {
+	return ChatStreamConfig{
+		DefaultWindow:    256,
+		MaxWindow:        1024,
+		QueueSize:        1024,
+		MaxSubscriptions: 50,
+		DrainTimeout:     5 * time.Second,
+	}
+}
+
+var errChatSessionClosed = errors.New("chat session closed")
+
+// Chat multiplexes room subscriptions, sends, typing indicators and acks over
+// one stream. Subscriptions and sends go through StreamMessages and
+// SendMessage, so they behave exactly like the unary and streaming RPCs.
+func (h *ChatHandler) Chat(stream pb.ChatService_ChatServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	hello := first.GetHello()
+	if hello == nil {
+		return handlerError(codes.InvalidArgument, "the first event must be a hello")
+	}
+
+	user, err := h.authenticate(stream.Context(), hello.GetToken(), "Chat", "")
+	if err != nil {
+		return err
+	}
+
+	window := int(hello.GetWindow())
+	if window <= 0 {
+		window = h.chatConfig.DefaultWindow
+	}
+	if window > h.chatConfig.MaxWindow {
+		window = h.chatConfig.MaxWindow
+	}
+
+	log.Printf("Chat stream opened by user %s", user.ID)
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	s := &chatSession{
+		h:             h,
+		stream:        stream,
+		user:          user,
+		token:         hello.GetToken(),
+		window:        int64(window),
+		ctx:           ctx,
+		cancel:        cancel,
+		queue:         make(chan *pb.ServerEvent, h.chatConfig.QueueSize),
+		messages:      make(chan *pb.ServerEvent),
+		acked:         make(chan struct{}, 1),
+		draining:      make(chan struct{}),
+		subscriptions: make(map[string]*chatSubscription),
+	}
+	s.enqueue(&pb.ServerEvent{RequestId: first.GetRequestId(), Event: &pb.ServerEvent_Ready{Ready: &pb.ChatReady{
+		UserId:   user.ID,
+		Username: user.Username,
+		Window:   int32(window),
+	}}})
+
+	err = s.run()
+	log.Printf("Chat stream closed for user %s: %v", user.ID, err)
+	return err
+}
+
+type chatSession struct {
+	h      *ChatHandler
+	stream pb.ChatService_ChatServer
+	user   *entities.User
+	token  string
+	window int64
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	queue    chan *pb.ServerEvent
+	messages chan *pb.ServerEvent
+
+	sent     int64
+	ackedSeq atomic.Int64
+	acked    chan struct{}
+	draining chan struct{}
+
+	failMu  sync.Mutex
+	failErr error
+
+	mu            sync.Mutex
+	subscriptions map[string]*chatSubscription
+	streams       sync.WaitGroup
+}
+
+type chatSubscription struct {
+	cancel context.CancelFunc
+}
+
+func (s *chatSession) run() error {
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- s.sendLoop() }()
+
+	recvErr := make(chan error, 1)
+	go func() { recvErr <- s.recvLoop() }()
+
+	var err error
+	select {
+	case err = <-recvErr:
+		s.stopSubscriptions()
+		if err == nil {
+			// The client closed its side: deliver what is queued, then end
+			// the stream with OK.
+			close(s.draining)
+			select {
+			case err = <-sendErr:
+			case <-time.After(s.h.chatConfig.DrainTimeout):
+				s.cancel()
+				<-sendErr
+			}
+			s.cancel()
+			return err
+		}
+	case err = <-sendErr:
+	case <-s.ctx.Done():
+	}
+
+	s.cancel()
+	s.stopSubscriptions()
+	if failErr := s.failure(); failErr != nil {
+		return failErr
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return status.FromContextError(s.ctx.Err()).Err()
+}
+
+// sendLoop is the only goroutine that calls stream.Send. It waits for window
+// space before each event, except while draining: a client that closed its
+// side can no longer ack. Queued events go before room messages, so a
+// subscribe result comes ahead of the room's history.
+func (s *chatSession) sendLoop() error {
+	for {
+	wait:
+		for s.sent-s.ackedSeq.Load() >= s.window {
+			select {
+			case <-s.acked:
+			case <-s.draining:
+				break wait
+			case <-s.ctx.Done():
+				return nil
+			}
+		}
+
+		var event *pb.ServerEvent
+		select {
+		case event = <-s.queue:
+		default:
+			select {
+			case event = <-s.queue:
+			case event = <-s.messages:
+			case <-s.draining:
+				if len(s.queue) == 0 {
+					return nil
+				}
+				continue
+			case <-s.ctx.Done():
+				return nil
+			}
+		}
+
+		s.sent++
+		event.Seq = s.sent
+		if err := s.stream.Send(event); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *chatSession) recvLoop() error {
+	for {
+		event, err := s.stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch e := event.GetEvent().(type) {
+		case *pb.ClientEvent_Subscribe:
+			s.subscribe(event.GetRequestId(), e.Subscribe)
+		case *pb.ClientEvent_Unsubscribe:
+			s.unsubscribe(event.GetRequestId(), e.Unsubscribe.GetRoomId())
+		case *pb.ClientEvent_Send:
+			s.send(event.GetRequestId(), e.Send)
+		case *pb.ClientEvent_Typing:
+			s.typing(event.GetRequestId(), e.Typing)
+		case *pb.ClientEvent_Ack:
+			s.ack(e.Ack.GetSeq())
+		default:
+			s.sendError(event.GetRequestId(), "", handlerError(codes.InvalidArgument, "unsupported event"))
+		}
+
+		if s.ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// enqueue never blocks; a full queue means the client stopped reading or
+// acking and the stream is failed.
+func (s *chatSession) enqueue(event *pb.ServerEvent) error {
+	if s.ctx.Err() != nil {
+		return errChatSessionClosed
+	}
+	select {
+	case s.queue <- event:
+		return nil
+	default:
+		s.fail(handlerError(codes.ResourceExhausted, "client is not keeping up with the stream"))
+		return errChatSessionClosed
+	}
+}
+
+func (s *chatSession) fail(err error) {
+	s.failMu.Lock()
+	if s.failErr == nil {
+		s.failErr = err
+	}
+	s.failMu.Unlock()
+	s.cancel()
+}
+
+func (s *chatSession) failure() error {
+	s.failMu.Lock()
+	defer s.failMu.Unlock()
+	return s.failErr
+}
+
+func (s *chatSession) ack(seq int64) {
+	for {
+		current := s.ackedSeq.Load()
+		if seq <= current || s.ackedSeq.CompareAndSwap(current, seq) {
+			break
+		}
+	}
+	select {
+	case s.acked <- struct{}{}:
+	default:
+	}
+}
+
+func (s *chatSession) result(requestID, roomID string, message *pb.MessageResponse) {
+	s.enqueue(&pb.ServerEvent{RequestId: requestID, Event: &pb.ServerEvent_Result{Result: &pb.ChatResult{
+		RoomId:  roomID,
+		Message: message,
+	}}})
+}
+
+func (s *chatSession) sendError(requestID, roomID string, err error) {
+	st := status.Convert(statusError(err))
+	s.enqueue(&pb.ServerEvent{RequestId: requestID, Event: &pb.ServerEvent_Error{Error: &pb.ChatError{
+		RoomId:  roomID,
+		Code:    chatCodeName(st.Code()),
+		Message: st.Message(),
+	}}})
+}
+
+func (s *chatSession) subscribe(requestID string, req *pb.ChatSubscribe) {
+	roomID := req.GetRoomId()
+	if roomID == "" {
+		s.sendError(requestID, "", handlerError(codes.InvalidArgument, "room_id is required"))
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.subscriptions[roomID]; ok {
+		s.mu.Unlock()
+		s.result(requestID, roomID, nil)
+		return
+	}
+	if len(s.subscriptions) >= s.h.chatConfig.MaxSubscriptions {
+		s.mu.Unlock()
+		s.sendError(requestID, roomID, handlerError(codes.ResourceExhausted, "too many subscriptions"))
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	subscription := &chatSubscription{cancel: cancel}
+	s.subscriptions[roomID] = subscription
+	s.streams.Add(2)
+	s.mu.Unlock()
+
+	s.result(requestID, roomID, nil)
+
+	go func() {
+		defer s.streams.Done()
+		err := s.h.StreamMessages(&pb.StreamRequest{
+			RoomId:         roomID,
+			Token:          s.token,
+			IncludeUpdates: req.GetIncludeUpdates(),
+		}, &chatRoomStream{ctx: ctx, session: s})
+		if err != nil && ctx.Err() == nil && !errors.Is(err, errChatSessionClosed) {
+			s.sendError(requestID, roomID, err)
+		}
+		cancel()
+		s.mu.Lock()
+		if s.subscriptions[roomID] == subscription {
+			delete(s.subscriptions, roomID)
+		}
+		s.mu.Unlock()
+	}()
+
+	go func() {
+		defer s.streams.Done()
+		if s.h.typingUseCase == nil {
+			return
+		}
+		for event := range s.h.typingUseCase.Subscribe(ctx, roomID) {
+			if event.UserID == s.user.ID {
+				continue
+			}
+			s.enqueue(&pb.ServerEvent{Event: &pb.ServerEvent_Typing{Typing: toTypingResponse(event)}})
+		}
+	}()
+}
+
+func (s *chatSession) unsubscribe(requestID, roomID string) {
+	s.mu.Lock()
+	subscription, ok := s.subscriptions[roomID]
+	delete(s.subscriptions, roomID)
+	s.mu.Unlock()
+
+	if ok {
+		subscription.cancel()
+	}
+	s.result(requestID, roomID, nil)
+}
+
+func (s *chatSession) stopSubscriptions() {
+	s.mu.Lock()
+	for roomID, subscription := range s.subscriptions {
+		subscription.cancel()
+		delete(s.subscriptions, roomID)
+	}
+	s.mu.Unlock()
+	s.streams.Wait()
+}
+
+func (s *chatSession) send(requestID string, req *pb.ChatSend) {
+	message, err := s.h.SendMessage(s.ctx, &pb.MessageRequest{
+		RoomId:     req.GetRoomId(),
+		Content:    req.GetContent(),
+		TtlSeconds: req.GetTtlSeconds(),
+		Token:      s.token,
+	})
+	if err != nil {
+		s.sendError(requestID, req.GetRoomId(), err)
+		return
+	}
+	s.result(requestID, req.GetRoomId(), message)
+}
+
+func (s *chatSession) typing(requestID string, req *pb.ChatTyping) {
+	if s.h.typingUseCase == nil {
+		s.sendError(requestID, req.GetRoomId(), handlerError(codes.Unimplemented, "typing indicators are not enabled"))
+		return
+	}
+	if err := botScopeError(s.user, "Chat", req.GetRoomId()); err != nil {
+		s.sendError(requestID, req.GetRoomId(), err)
+		return
+	}
+	if err := s.h.typingUseCase.SetTyping(s.ctx, s.user, req.GetRoomId(), req.GetTyping()); err != nil {
+		s.sendError(requestID, req.GetRoomId(), err)
+	}
+}
+
+// chatRoomStream feeds one StreamMessages call into the session queue.
+type chatRoomStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	session *chatSession
+}
+
+func (r *chatRoomStream) Context() context.Context {
+	return r.ctx
+}
+
+// Send blocks until the send loop takes the message, so a room's history
+// is replayed as fast as the client acks rather than overflowing the queue.
+func (r *chatRoomStream) Send(message *pb.MessageResponse) error {
+	select {
+	case r.session.messages <- &pb.ServerEvent{Event: &pb.ServerEvent_Message{Message: message}}:
+		return nil
+	case <-r.ctx.Done():
+		return errChatSessionClosed
+	}
+}
+
+func toTypingResponse(event *entities.TypingEvent) *pb.TypingResponse {
+	resp := &pb.TypingResponse{
+		RoomId:   event.RoomID,
+		UserId:   event.UserID,
+		Username: event.Username,
+		Typing:   event.Typing,
+	}
+	if !event.ExpiresAt.IsZero() {
+		resp.ExpiresAt = event.ExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// chatCodeName turns codes.InvalidArgument into "INVALID_ARGUMENT", the
+// canonical gRPC code name.
+func chatCodeName(code codes.Code) string {
+	var b strings.Builder
+	for i, r := range code.String() {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
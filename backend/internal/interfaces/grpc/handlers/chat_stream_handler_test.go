@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/usecases"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func startChatServer(t *testing.T, handler *ChatHandler) pb.ChatServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterChatServiceServer(server, handler)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewChatServiceClient(conn)
+}
+
+func TestChatHandler_Chat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	typingUC := usecases.NewTypingUseCase(usecases.DefaultTypingConfig())
+	config := DefaultChatStreamConfig()
+	config.DefaultWindow = 3
+	config.QueueSize = 8
+	client := startChatServer(t, NewChatHandler(mockMsgUC, mockAuthUC, WithTypingUseCase(typingUC), WithChatStreamConfig(config)))
+
+	user := &entities.User{ID: "user123", Username: "mariem"}
+	mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "tok").Return(user, nil).AnyTimes()
+
+	open := func(t *testing.T) (pb.ChatService_ChatClient, func() *pb.ServerEvent) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		t.Cleanup(cancel)
+		stream, err := client.Chat(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&pb.ClientEvent{RequestId: "hello", Event: &pb.ClientEvent_Hello{Hello: &pb.ChatHello{Token: "tok"}}}))
+
+		recv := func() *pb.ServerEvent {
+			t.Helper()
+			event, err := stream.Recv()
+			require.NoError(t, err)
+			return event
+		}
+		ready := recv()
+		require.NotNil(t, ready.GetReady())
+		assert.Equal(t, int64(1), ready.GetSeq())
+		assert.Equal(t, "user123", ready.GetReady().GetUserId())
+		return stream, recv
+	}
+	subscribe := func(t *testing.T, stream pb.ChatService_ChatClient, recv func() *pb.ServerEvent, messages chan *entities.Message) {
+		mockMsgUC.EXPECT().StreamMessages(gomock.Any(), "general").Return(messages, nil)
+		require.NoError(t, stream.Send(&pb.ClientEvent{RequestId: "sub", Event: &pb.ClientEvent_Subscribe{Subscribe: &pb.ChatSubscribe{RoomId: "general"}}}))
+		result := recv()
+		assert.Equal(t, "sub", result.GetRequestId())
+		assert.Equal(t, "general", result.GetResult().GetRoomId())
+	}
+
+	t.Run("hello is required", func(t *testing.T) {
+		stream, err := client.Chat(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&pb.ClientEvent{Event: &pb.ClientEvent_Ack{Ack: &pb.ChatAck{Seq: 1}}}))
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("subscribe, send, typing and clean shutdown", func(t *testing.T) {
+		stream, recv := open(t)
+		messages := make(chan *entities.Message, 1)
+		subscribe(t, stream, recv, messages)
+
+		messages <- &entities.Message{ID: "msg1", RoomID: "general", Content: "hello", Event: entities.MessageCreated}
+		event := recv()
+		assert.Equal(t, "msg1", event.GetMessage().GetMessageId())
+		require.NoError(t, stream.Send(&pb.ClientEvent{Event: &pb.ClientEvent_Ack{Ack: &pb.ChatAck{Seq: event.GetSeq()}}}))
+
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+				assert.Equal(t, "user123", params.UserID)
+				return &entities.Message{ID: "msg2", RoomID: params.RoomID, Content: params.Content}, nil
+			})
+		require.NoError(t, stream.Send(&pb.ClientEvent{RequestId: "send", Event: &pb.ClientEvent_Send{Send: &pb.ChatSend{RoomId: "general", Content: "hi"}}}))
+		event = recv()
+		assert.Equal(t, "send", event.GetRequestId())
+		assert.Equal(t, "msg2", event.GetResult().GetMessage().GetMessageId())
+
+		// The typing subscription starts asynchronously, so keep typing until
+		// the first indicator arrives.
+		stopTyping := make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(20 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				typingUC.SetTyping(context.Background(), &entities.User{ID: "user2", Username: "bob"}, "general", true)
+				select {
+				case <-stopTyping:
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+		event = recv()
+		close(stopTyping)
+		assert.Equal(t, "bob", event.GetTyping().GetUsername())
+		assert.True(t, event.GetTyping().GetTyping())
+
+		require.NoError(t, stream.CloseSend())
+		for {
+			_, err := stream.Recv()
+			if err != nil {
+				assert.Equal(t, io.EOF, err)
+				break
+			}
+		}
+	})
+
+	t.Run("events wait for window space", func(t *testing.T) {
+		stream, recv := open(t)
+		messages := make(chan *entities.Message, 4)
+		subscribe(t, stream, recv, messages)
+
+		for i := 0; i < 3; i++ {
+			messages <- &entities.Message{ID: "msg", RoomID: "general", Event: entities.MessageCreated}
+		}
+		event := recv()
+		assert.Equal(t, int64(3), event.GetSeq())
+
+		received := make(chan *pb.ServerEvent, 1)
+		go func() {
+			if event, err := stream.Recv(); err == nil {
+				received <- event
+			}
+		}()
+		select {
+		case <-received:
+			t.Fatal("event sent beyond the window")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		require.NoError(t, stream.Send(&pb.ClientEvent{Event: &pb.ClientEvent_Ack{Ack: &pb.ChatAck{Seq: 3}}}))
+		select {
+		case event := <-received:
+			assert.Equal(t, int64(4), event.GetSeq())
+		case <-time.After(2 * time.Second):
+			t.Fatal("ack did not release the next event")
+		}
+	})
+
+	t.Run("bot scope errors keep their status", func(t *testing.T) {
+		bot := &entities.User{ID: "bot1", IsBot: true, BotScopes: &entities.BotScopes{RPCs: []string{"SendMessage"}}}
+		mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "bot-key").Return(bot, nil)
+		stream, err := client.Chat(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&pb.ClientEvent{Event: &pb.ClientEvent_Hello{Hello: &pb.ChatHello{Token: "bot-key"}}}))
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("history longer than the queue waits for acks", func(t *testing.T) {
+		stream, recv := open(t)
+		messages := make(chan *entities.Message, 16)
+		subscribe(t, stream, recv, messages)
+
+		for i := 0; i < 16; i++ {
+			messages <- &entities.Message{ID: "msg", RoomID: "general", Event: entities.MessageCreated}
+		}
+		for i := 0; i < 16; i++ {
+			event := recv()
+			require.NotNil(t, event.GetMessage())
+			require.NoError(t, stream.Send(&pb.ClientEvent{Event: &pb.ClientEvent_Ack{Ack: &pb.ChatAck{Seq: event.GetSeq()}}}))
+		}
+	})
+
+	t.Run("clients that stop acking are disconnected", func(t *testing.T) {
+		stream, _ := open(t)
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(gomock.Any(), gomock.Any()).
+			Return(&entities.Message{ID: "msg", RoomID: "general"}, nil).
+			AnyTimes()
+
+		for i := 0; i < 16; i++ {
+			require.NoError(t, stream.Send(&pb.ClientEvent{RequestId: "send", Event: &pb.ClientEvent_Send{Send: &pb.ChatSend{RoomId: "general", Content: "hi"}}}))
+		}
+
+		var err error
+		for err == nil {
+			_, err = stream.Recv()
+		}
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
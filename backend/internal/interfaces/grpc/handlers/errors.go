@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/usecases"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain is the ErrorInfo domain of every error the service reports.
+const errorDomain = "chat-app"
+
+var errorCodes = map[usecases.ErrorKind]codes.Code{
+	usecases.KindInvalidArgument:    codes.InvalidArgument,
+	usecases.KindNotFound:           codes.NotFound,
+	usecases.KindAlreadyExists:      codes.AlreadyExists,
+	usecases.KindUnauthenticated:    codes.Unauthenticated,
+	usecases.KindPermissionDenied:   codes.PermissionDenied,
+	usecases.KindFailedPrecondition: codes.FailedPrecondition,
+	usecases.KindRateLimited:        codes.ResourceExhausted,
+}
+
+// ErrorCode returns the gRPC code errors of the given kind are reported
+// with, and whether the kind has one. The HTTP gateway uses it too, so both
+// APIs report a domain error alike.
+func ErrorCode(kind usecases.ErrorKind) (codes.Code, bool) {
+	code, ok := errorCodes[kind]
+	return code, ok
+}
+
+// statusError turns an error from a usecase into a gRPC status. Domain
+// errors keep their message and carry an ErrorInfo detail, plus BadRequest
+// for invalid fields and RetryInfo when rate limited. Statuses the handlers
+// made themselves pass through. Other errors, including statuses from
+// backends such as Firestore, are logged and reported as Internal without
+// their message.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok && ownStatus(st) {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	domainErr, ok := usecases.AsError(err)
+	if !ok {
+		log.Printf("Internal error: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+	return domainStatus(domainErr).Err()
+}
+
+// handlerError reports a failure the handler found itself.
+func handlerError(code codes.Code, message string) error {
+	return newStatus(code, message, &errdetails.ErrorInfo{Reason: chatCodeName(code), Domain: errorDomain}).Err()
+}
+
+// ownStatus reports whether st was made by domainStatus or handlerError,
+// which mark it with an ErrorInfo of errorDomain.
+func ownStatus(st *status.Status) bool {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain {
+			return true
+		}
+	}
+	return false
+}
+
+func domainStatus(domainErr *usecases.Error, extra ...protoadapt.MessageV1) *status.Status {
+	code, ok := ErrorCode(domainErr.Kind)
+	if !ok {
+		code = codes.Internal
+	}
+	reason := domainErr.Reason
+	if reason == "" {
+		reason = chatCodeName(code)
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}}
+	if domainErr.Field != "" {
+		details = append(details, &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       domainErr.Field,
+			Description: domainErr.Message,
+		}}})
+	}
+	if domainErr.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(domainErr.RetryAfter)})
+	}
+	details = append(details, extra...)
+	return newStatus(code, domainErr.Message, details...)
+}
+
+func newStatus(code codes.Code, message string, details ...protoadapt.MessageV1) *status.Status {
+	st := status.New(code, message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st
+}
+
+// fieldError reports a request field the handler itself could not parse.
+func fieldError(field, reason, message string) error {
+	return domainStatus(&usecases.Error{
+		Kind:    usecases.KindInvalidArgument,
+		Reason:  reason,
+		Field:   field,
+		Message: message,
+	}).Err()
+}
+
+// authError reports a failed Register or Login. Clients written before the
+// service used status codes read Success and Error from the AuthResponse, so
+// that response is attached to the status as a detail too.
+func authError(err error) error {
+	domainErr, ok := usecases.AsError(err)
+	if !ok {
+		return statusError(err)
+	}
+	legacy := &pb.AuthResponse{Success: false, Error: domainErr.Message}
+	return domainStatus(domainErr, legacy).Err()
+}
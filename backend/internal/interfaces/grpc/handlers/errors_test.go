@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/repositories"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	t.Run("invalid argument carries the field", func(t *testing.T) {
+		err := statusError(&usecases.Error{
+			Kind:    usecases.KindInvalidArgument,
+			Reason:  "TOPIC_TOO_LONG",
+			Field:   "topic",
+			Message: "room topic cannot be longer than 250 characters",
+		})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, "room topic cannot be longer than 250 characters", st.Message())
+		require.Len(t, st.Details(), 2)
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "TOPIC_TOO_LONG", info.GetReason())
+		assert.Equal(t, errorDomain, info.GetDomain())
+		badRequest := st.Details()[1].(*errdetails.BadRequest)
+		assert.Equal(t, "topic", badRequest.GetFieldViolations()[0].GetField())
+	})
+
+	t.Run("rate limited carries the retry delay", func(t *testing.T) {
+		err := statusError(&usecases.Error{Kind: usecases.KindRateLimited, Message: "slow down", RetryAfter: 30 * time.Second})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.ResourceExhausted, st.Code())
+		require.Len(t, st.Details(), 2)
+		assert.Equal(t, "RESOURCE_EXHAUSTED", st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+		assert.Equal(t, 30*time.Second, st.Details()[1].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+	})
+
+	t.Run("repository errors", func(t *testing.T) {
+		assert.Equal(t, codes.NotFound, status.Code(statusError(fmt.Errorf("webhook %w", repositories.ErrNotFound))))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(statusError(fmt.Errorf("%w: poll is closed", repositories.ErrConflict))))
+	})
+
+	t.Run("other errors are internal and hidden", func(t *testing.T) {
+		st := status.Convert(statusError(errors.New("dial tcp: connection refused")))
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "internal error", st.Message())
+	})
+
+	t.Run("backend statuses are internal and hidden", func(t *testing.T) {
+		st := status.Convert(statusError(status.Error(codes.NotFound, "projects/chat/databases/(default)/documents/users/u1 not found")))
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "internal error", st.Message())
+	})
+
+	t.Run("handler statuses and context errors pass through", func(t *testing.T) {
+		original := handlerError(codes.Unavailable, "try later")
+		assert.Same(t, original, statusError(original))
+		field := fieldError("limit", "INVALID_LIMIT", "limit is too large")
+		assert.Same(t, field, statusError(field))
+		assert.Equal(t, codes.Canceled, status.Code(statusError(context.Canceled)))
+		assert.Nil(t, statusError(nil))
+	})
+}
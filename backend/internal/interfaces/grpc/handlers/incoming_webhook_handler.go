@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) CreateIncomingWebhook(ctx context.Context, req *pb.CreateIncomingWebhookRequest) (*pb.IncomingWebhookResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "CreateIncomingWebhook", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Creating incoming webhook for room %s by user %s", req.GetRoomId(), user.ID)
+
+	webhook, secret, err := h.incomingUseCase.CreateIncomingWebhook(ctx, user, req.GetRoomId(), req.GetDisplayName())
+	if err != nil {
+		log.Printf("Error creating incoming webhook: %v", err)
+		return nil, statusError(err)
+	}
+
+	resp := toIncomingWebhookResponse(webhook)
+	resp.Secret = secret
+	return resp, nil
+}
+
+func (h *ChatHandler) ListIncomingWebhooks(ctx context.Context, req *pb.RoomRequest) (*pb.ListIncomingWebhooksResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "ListIncomingWebhooks", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	webhooks, err := h.incomingUseCase.ListIncomingWebhooks(ctx, user, req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &pb.ListIncomingWebhooksResponse{}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, toIncomingWebhookResponse(webhook))
+	}
+	return resp, nil
+}
+
+func (h *ChatHandler) DeleteIncomingWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.IncomingWebhookResponse, error) {
+	user, err := h.authenticateIn(ctx, req.GetToken(), "DeleteIncomingWebhook", func(user *entities.User) (string, error) {
+		webhook, err := h.incomingUseCase.GetIncomingWebhook(ctx, user, req.GetWebhookId())
+		if err != nil {
+			return "", err
+		}
+		return webhook.RoomID, nil
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Deleting incoming webhook %s by user %s", req.GetWebhookId(), user.ID)
+
+	if err := h.incomingUseCase.DeleteIncomingWebhook(ctx, user, req.GetWebhookId()); err != nil {
+		log.Printf("Error deleting incoming webhook: %v", err)
+		return nil, statusError(err)
+	}
+
+	return &pb.IncomingWebhookResponse{WebhookId: req.GetWebhookId()}, nil
+}
+
+// toIncomingWebhookResponse sets Path to the URL path without the token;
+// clients post to Path + "/" + secret.
+func toIncomingWebhookResponse(webhook *entities.IncomingWebhook) *pb.IncomingWebhookResponse {
+	return &pb.IncomingWebhookResponse{
+		WebhookId:   webhook.ID,
+		RoomId:      webhook.RoomID,
+		DisplayName: webhook.DisplayName,
+		Path:        "/hooks/" + webhook.ID,
+		CreatedAt:   webhook.CreatedAt.Format(time.RFC3339),
+	}
+}
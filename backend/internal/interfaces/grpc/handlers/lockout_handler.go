@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) UnlockAccount(ctx context.Context, req *pb.UnlockAccountRequest) (*pb.UnlockAccountResponse, error) {
+	admin, err := h.authenticate(ctx, req.GetToken(), "UnlockAccount", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	if err := h.authUseCase.UnlockAccount(ctx, admin, req.GetUsername()); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.UnlockAccountResponse{}, nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.RevokeSessionsResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "ChangePassword", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	revoked, err := h.authUseCase.ChangePassword(ctx, user.ID, req.GetToken(), req.GetCurrentPassword(), req.GetNewPassword())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("User %s changed their password, %d other sessions revoked", user.ID, revoked)
+	return &pb.RevokeSessionsResponse{Revoked: int32(revoked)}, nil
+}
+
+func (h *ChatHandler) SetEmail(ctx context.Context, req *pb.SetEmailRequest) (*pb.SetEmailResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "SetEmail", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	if err := h.authUseCase.SetEmail(ctx, user.ID, req.GetEmail()); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.SetEmailResponse{Email: req.GetEmail()}, nil
+}
+
+func (h *ChatHandler) RequestPasswordReset(ctx context.Context, req *pb.PasswordResetRequest) (*pb.PasswordResetResponse, error) {
+	if err := h.authUseCase.RequestPasswordReset(ctx, req.GetUsername(), h.sessionMetadata(ctx, "")); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.PasswordResetResponse{}, nil
+}
+
+func (h *ChatHandler) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.RevokeSessionsResponse, error) {
+	revoked, err := h.authUseCase.ResetPassword(ctx, req.GetResetToken(), req.GetNewPassword())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.RevokeSessionsResponse{Revoked: int32(revoked)}, nil
+}
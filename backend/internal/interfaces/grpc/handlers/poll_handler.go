@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) CreatePoll(ctx context.Context, req *pb.CreatePollRequest) (*pb.MessageResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "CreatePoll", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	params := entities.PollParams{
+		Question:       req.GetQuestion(),
+		Options:        req.GetOptions(),
+		MultipleChoice: req.GetMultipleChoice(),
+		Anonymous:      req.GetAnonymous(),
+	}
+	if req.GetClosesAt() != "" {
+		params.ClosesAt, err = time.Parse(time.RFC3339, req.GetClosesAt())
+		if err != nil {
+			return nil, fieldError("closes_at", "INVALID_TIMESTAMP", "closes_at must be an RFC3339 timestamp")
+		}
+	}
+
+	log.Printf("Creating poll in room %s by user %s", req.GetRoomId(), user.ID)
+
+	message, err := h.pollUseCase.CreatePoll(ctx, user, req.GetRoomId(), params)
+	if err != nil {
+		log.Printf("Error creating poll: %v", err)
+		return nil, statusError(err)
+	}
+
+	resp := toMessageResponse(message, message.Username)
+	h.setAuthors(ctx, resp)
+	return resp, nil
+}
+
+func (h *ChatHandler) Vote(ctx context.Context, req *pb.VoteRequest) (*pb.MessageResponse, error) {
+	user, err := h.authenticateIn(ctx, req.GetToken(), "Vote", func(user *entities.User) (string, error) {
+		poll, err := h.pollUseCase.GetPoll(ctx, req.GetMessageId())
+		if err != nil {
+			return "", err
+		}
+		return poll.RoomID, nil
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	message, err := h.pollUseCase.Vote(ctx, user, req.GetMessageId(), req.GetOptionIds())
+	if err != nil {
+		log.Printf("Error voting in poll %s: %v", req.GetMessageId(), err)
+		return nil, statusError(err)
+	}
+
+	resp := toMessageResponse(message, message.Username)
+	h.setAuthors(ctx, resp)
+	return resp, nil
+}
+
+func (h *ChatHandler) ClosePoll(ctx context.Context, req *pb.ClosePollRequest) (*pb.MessageResponse, error) {
+	user, err := h.authenticateIn(ctx, req.GetToken(), "ClosePoll", func(user *entities.User) (string, error) {
+		poll, err := h.pollUseCase.GetPoll(ctx, req.GetMessageId())
+		if err != nil {
+			return "", err
+		}
+		return poll.RoomID, nil
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Closing poll %s by user %s", req.GetMessageId(), user.ID)
+
+	message, err := h.pollUseCase.ClosePoll(ctx, user, req.GetMessageId())
+	if err != nil {
+		log.Printf("Error closing poll: %v", err)
+		return nil, statusError(err)
+	}
+
+	resp := toMessageResponse(message, message.Username)
+	h.setAuthors(ctx, resp)
+	return resp, nil
+}
+
+func toPollResponse(poll *entities.Poll) *pb.Poll {
+	resp := &pb.Poll{
+		Question:       poll.Question,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Closed:         poll.Closed,
+		TotalVoters:    int32(poll.TotalVoters),
+	}
+	if !poll.ClosesAt.IsZero() {
+		resp.ClosesAt = poll.ClosesAt.Format(time.RFC3339)
+	}
+
+	for _, option := range poll.Options {
+		resp.Options = append(resp.Options, &pb.PollOption{
+			OptionId: option.ID,
+			Text:     option.Text,
+			Votes:    int32(option.Votes),
+			VoterIds: option.VoterIDs,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+// maxProfilesPerRequest is how many users GetProfiles looks up at once.
+const maxProfilesPerRequest = 100
+
+func (h *ChatHandler) GetProfile(ctx context.Context, req *pb.GetProfileRequest) (*pb.ProfileResponse, error) {
+	if _, err := h.authenticate(ctx, req.GetToken(), "GetProfile", ""); err != nil {
+		return nil, statusError(err)
+	}
+
+	profile, err := h.profileUseCase.GetProfile(ctx, req.GetUsername())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toProfileResponse(profile), nil
+}
+
+func (h *ChatHandler) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.ProfileResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "UpdateProfile", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	update := entities.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		AvatarURL:   req.AvatarUrl,
+		Bio:         req.Bio,
+		StatusText:  req.StatusText,
+		StatusTTL:   time.Duration(req.GetStatusTtlSeconds()) * time.Second,
+		Timezone:    req.Timezone,
+	}
+	profile, err := h.profileUseCase.UpdateProfile(ctx, user, update)
+	if err != nil {
+		log.Printf("Error updating profile of user %s: %v", user.ID, err)
+		return nil, statusError(err)
+	}
+	return toProfileResponse(profile), nil
+}
+
+func (h *ChatHandler) GetProfiles(ctx context.Context, req *pb.GetProfilesRequest) (*pb.GetProfilesResponse, error) {
+	if _, err := h.authenticate(ctx, req.GetToken(), "GetProfiles", ""); err != nil {
+		return nil, statusError(err)
+	}
+	if len(req.GetUserIds()) > maxProfilesPerRequest {
+		return nil, fieldError("user_ids", "TOO_MANY_USERS", fmt.Sprintf("at most %d profiles can be read at once", maxProfilesPerRequest))
+	}
+
+	profiles, err := h.profileUseCase.GetProfiles(ctx, req.GetUserIds())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &pb.GetProfilesResponse{}
+	for _, userID := range req.GetUserIds() {
+		if profile, ok := profiles[userID]; ok {
+			resp.Profiles = append(resp.Profiles, toProfileResponse(profile))
+			delete(profiles, userID)
+		}
+	}
+	return resp, nil
+}
+
+// setAuthors fills in the display names and avatars of the authors of
+// messages with one profile lookup. Messages keep only the username if
+// the profiles cannot be read.
+func (h *ChatHandler) setAuthors(ctx context.Context, messages ...*pb.MessageResponse) {
+	if h.profileUseCase == nil || len(messages) == 0 {
+		return
+	}
+
+	userIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		userIDs = append(userIDs, message.GetUserId())
+	}
+	profiles, err := h.profileUseCase.GetProfiles(ctx, userIDs)
+	if err != nil {
+		log.Printf("Error reading profiles of message authors: %v", err)
+		return
+	}
+
+	for _, message := range messages {
+		setAuthor(message, profiles[message.GetUserId()])
+	}
+}
+
+// authorCache remembers the profiles of the authors seen on one stream, so
+// that a room's history replay reads each author's profile once rather than
+// once per message.
+type authorCache struct {
+	h *ChatHandler
+	// profiles holds nil for authors without a profile.
+	profiles map[string]*entities.Profile
+}
+
+func (h *ChatHandler) newAuthorCache() *authorCache {
+	return &authorCache{h: h, profiles: make(map[string]*entities.Profile)}
+}
+
+func (c *authorCache) setAuthor(ctx context.Context, message *pb.MessageResponse) {
+	if c.h.profileUseCase == nil {
+		return
+	}
+
+	userID := message.GetUserId()
+	profile, ok := c.profiles[userID]
+	if !ok {
+		profiles, err := c.h.profileUseCase.GetProfiles(ctx, []string{userID})
+		if err != nil {
+			log.Printf("Error reading profile of message author %s: %v", userID, err)
+			return
+		}
+		profile = profiles[userID]
+		c.profiles[userID] = profile
+	}
+	setAuthor(message, profile)
+}
+
+func setAuthor(message *pb.MessageResponse, profile *entities.Profile) {
+	if profile != nil {
+		message.AuthorDisplayName = profile.DisplayName
+		message.AuthorAvatarUrl = profile.AvatarURL
+	}
+}
+
+func toProfileResponse(profile *entities.Profile) *pb.ProfileResponse {
+	resp := &pb.ProfileResponse{
+		UserId:      profile.UserID,
+		Username:    profile.Username,
+		DisplayName: profile.DisplayName,
+		AvatarUrl:   profile.AvatarURL,
+		Bio:         profile.Bio,
+		StatusText:  profile.StatusText,
+		Timezone:    profile.Timezone,
+	}
+	if !profile.StatusExpiresAt.IsZero() {
+		resp.StatusExpiresAt = profile.StatusExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
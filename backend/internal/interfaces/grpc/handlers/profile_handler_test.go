@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChatHandler_Profiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	mockRoomUC := mocks.NewMockRoomUseCase(ctrl)
+	mockProfileUC := mocks.NewMockProfileUseCase(ctrl)
+	handler := NewChatHandler(mockMsgUC, mockAuthUC, WithRoomUseCase(mockRoomUC), WithProfileUseCase(mockProfileUC))
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+	profile := &entities.Profile{UserID: "user123", Username: "mariem", DisplayName: "Mariem B.", AvatarURL: "https://example.com/mariem.png"}
+
+	t.Run("update sets only the given fields", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(user, nil)
+		mockProfileUC.EXPECT().
+			UpdateProfile(ctx, user, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entities.User, update entities.ProfileUpdate) (*entities.Profile, error) {
+				require.NotNil(t, update.StatusText)
+				assert.Equal(t, "In a meeting", *update.StatusText)
+				assert.Equal(t, time.Hour, update.StatusTTL)
+				assert.Nil(t, update.DisplayName)
+				assert.Nil(t, update.Timezone)
+				return &entities.Profile{UserID: "user123", StatusText: "In a meeting", StatusExpiresAt: expiresAt}, nil
+			})
+
+		resp, err := handler.UpdateProfile(ctx, &pb.UpdateProfileRequest{
+			Token:            "test-token",
+			StatusText:       proto.String("In a meeting"),
+			StatusTtlSeconds: 3600,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "In a meeting", resp.StatusText)
+		assert.Equal(t, expiresAt.Format(time.RFC3339), resp.StatusExpiresAt)
+	})
+
+	t.Run("lookup keeps the requested order", func(t *testing.T) {
+		other := &entities.Profile{UserID: "user456", DisplayName: "Sam"}
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(user, nil)
+		mockProfileUC.EXPECT().
+			GetProfiles(ctx, []string{"user456", "nobody", "user123"}).
+			Return(map[string]*entities.Profile{"user123": profile, "user456": other}, nil)
+
+		resp, err := handler.GetProfiles(ctx, &pb.GetProfilesRequest{Token: "test-token", UserIds: []string{"user456", "nobody", "user123"}})
+		require.NoError(t, err)
+		require.Len(t, resp.Profiles, 2)
+		assert.Equal(t, "Sam", resp.Profiles[0].DisplayName)
+		assert.Equal(t, "Mariem B.", resp.Profiles[1].DisplayName)
+	})
+
+	t.Run("lookup of too many users", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(user, nil)
+
+		_, err := handler.GetProfiles(ctx, &pb.GetProfilesRequest{Token: "test-token", UserIds: make([]string, maxProfilesPerRequest+1)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("messages carry their author's profile", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(user, nil)
+		mockRoomUC.EXPECT().ListPinnedMessages(ctx, "general").Return([]*entities.Message{
+			{ID: "msg1", UserID: "user123", Username: "mariem"},
+			{ID: "msg2", UserID: "user456", Username: "sam"},
+			{ID: "msg3", UserID: "user123", Username: "mariem"},
+		}, nil)
+		// One lookup for every message of the page.
+		mockProfileUC.EXPECT().
+			GetProfiles(ctx, []string{"user123", "user456", "user123"}).
+			Return(map[string]*entities.Profile{"user123": profile}, nil)
+
+		resp, err := handler.ListPinnedMessages(ctx, &pb.RoomRequest{RoomId: "general", Token: "test-token"})
+		require.NoError(t, err)
+		require.Len(t, resp.Messages, 3)
+		assert.Equal(t, "Mariem B.", resp.Messages[0].AuthorDisplayName)
+		assert.Equal(t, "https://example.com/mariem.png", resp.Messages[0].AuthorAvatarUrl)
+		assert.Empty(t, resp.Messages[1].AuthorDisplayName)
+		assert.Equal(t, "Mariem B.", resp.Messages[2].AuthorDisplayName)
+	})
+
+	t.Run("messages are sent without profiles that cannot be read", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(user, nil)
+		mockRoomUC.EXPECT().ListPinnedMessages(ctx, "general").Return([]*entities.Message{{ID: "msg1", UserID: "user123", Username: "mariem"}}, nil)
+		mockProfileUC.EXPECT().GetProfiles(ctx, []string{"user123"}).Return(nil, assert.AnError)
+
+		resp, err := handler.ListPinnedMessages(ctx, &pb.RoomRequest{RoomId: "general", Token: "test-token"})
+		require.NoError(t, err)
+		assert.Equal(t, "mariem", resp.Messages[0].Username)
+		assert.Empty(t, resp.Messages[0].AuthorDisplayName)
+	})
+
+	t.Run("streams read each author's profile once", func(t *testing.T) {
+		messages := make(chan *entities.Message, 3)
+		messages <- &entities.Message{ID: "msg1", UserID: "user123", Username: "mariem", Event: entities.MessageCreated}
+		messages <- &entities.Message{ID: "msg2", UserID: "user456", Username: "sam", Event: entities.MessageCreated}
+		messages <- &entities.Message{ID: "msg3", UserID: "user123", Username: "mariem", Event: entities.MessageCreated}
+		close(messages)
+		mockMsgUC.EXPECT().StreamMessages(gomock.Any(), "general").Return(messages, nil)
+		mockProfileUC.EXPECT().GetProfiles(gomock.Any(), []string{"user123"}).Return(map[string]*entities.Profile{"user123": profile}, nil)
+		mockProfileUC.EXPECT().GetProfiles(gomock.Any(), []string{"user456"}).Return(map[string]*entities.Profile{}, nil)
+
+		stream := &recordingStream{ctx: ctx}
+		require.NoError(t, handler.StreamMessages(&pb.StreamRequest{RoomId: "general"}, stream))
+		require.Len(t, stream.sent, 3)
+		assert.Equal(t, "Mariem B.", stream.sent[0].AuthorDisplayName)
+		assert.Empty(t, stream.sent[1].AuthorDisplayName)
+		assert.Equal(t, "Mariem B.", stream.sent[2].AuthorDisplayName)
+	})
+}
+
+// recordingStream collects what StreamMessages sends.
+type recordingStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.MessageResponse
+}
+
+func (s *recordingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *recordingStream) Send(message *pb.MessageResponse) error {
+	s.sent = append(s.sent, message)
+	return nil
+}
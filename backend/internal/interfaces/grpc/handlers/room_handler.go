@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) GetRoom(ctx context.Context, req *pb.RoomRequest) (*pb.RoomResponse, error) {
+	if _, err := h.authenticate(ctx, req.GetToken(), "GetRoom", req.GetRoomId()); err != nil {
+		return nil, statusError(err)
+	}
+
+	room, err := h.roomUseCase.GetRoom(ctx, req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toRoomResponse(room), nil
+}
+
+func (h *ChatHandler) UpdateRoomSettings(ctx context.Context, req *pb.RoomSettingsRequest) (*pb.RoomResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "UpdateRoomSettings", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Updating settings for room %s by user %s", req.GetRoomId(), user.ID)
+
+	settings := entities.RoomSettings{
+		RoomID:       req.GetRoomId(),
+		PinLimit:     int(req.GetPinLimit()),
+		ModeratorIDs: req.GetModeratorIds(),
+	}
+	if req.DefaultTtlSeconds != nil {
+		ttl := time.Duration(req.GetDefaultTtlSeconds()) * time.Second
+		settings.DefaultTTL = &ttl
+	}
+	if req.Topic != nil {
+		topic := req.GetTopic()
+		settings.Topic = &topic
+	}
+
+	room, err := h.roomUseCase.UpdateRoomSettings(ctx, user, settings)
+	if err != nil {
+		log.Printf("Error updating room settings: %v", err)
+		return nil, statusError(err)
+	}
+
+	return toRoomResponse(room), nil
+}
+
+func (h *ChatHandler) PinMessage(ctx context.Context, req *pb.PinRequest) (*pb.MessageResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "PinMessage", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Pinning message %s in room %s", req.GetMessageId(), req.GetRoomId())
+
+	message, err := h.roomUseCase.PinMessage(ctx, user, req.GetRoomId(), req.GetMessageId())
+	if err != nil {
+		log.Printf("Error pinning message: %v", err)
+		return nil, statusError(err)
+	}
+
+	resp := toMessageResponse(message, message.Username)
+	h.setAuthors(ctx, resp)
+	return resp, nil
+}
+
+func (h *ChatHandler) UnpinMessage(ctx context.Context, req *pb.PinRequest) (*pb.MessageResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "UnpinMessage", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Unpinning message %s in room %s", req.GetMessageId(), req.GetRoomId())
+
+	message, err := h.roomUseCase.UnpinMessage(ctx, user, req.GetRoomId(), req.GetMessageId())
+	if err != nil {
+		log.Printf("Error unpinning message: %v", err)
+		return nil, statusError(err)
+	}
+
+	resp := toMessageResponse(message, message.Username)
+	h.setAuthors(ctx, resp)
+	return resp, nil
+}
+
+func (h *ChatHandler) ListPinnedMessages(ctx context.Context, req *pb.RoomRequest) (*pb.HistoryResponse, error) {
+	if _, err := h.authenticate(ctx, req.GetToken(), "ListPinnedMessages", req.GetRoomId()); err != nil {
+		return nil, statusError(err)
+	}
+
+	messages, err := h.roomUseCase.ListPinnedMessages(ctx, req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	pbMessages := make([]*pb.MessageResponse, 0, len(messages))
+	for _, message := range messages {
+		pbMessages = append(pbMessages, toMessageResponse(message, message.Username))
+	}
+	h.setAuthors(ctx, pbMessages...)
+
+	return &pb.HistoryResponse{Messages: pbMessages}, nil
+}
+
+func toRoomResponse(room *entities.Room) *pb.RoomResponse {
+	return &pb.RoomResponse{
+		RoomId:            room.ID,
+		Topic:             room.Topic,
+		ModeratorIds:      room.ModeratorIDs,
+		PinLimit:          int32(room.PinLimit),
+		DefaultTtlSeconds: int32(room.DefaultTTLSeconds),
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatHandler_PinMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	mockRoomUC := mocks.NewMockRoomUseCase(ctrl)
+	handler := NewChatHandler(mockMsgUC, mockAuthUC, WithRoomUseCase(mockRoomUC))
+
+	ctx := context.Background()
+	user := &entities.User{ID: "mod123", Username: "moderator"}
+	req := &pb.PinRequest{RoomId: "general", MessageId: "msg123", Token: "test-token"}
+
+	t.Run("successful pin", func(t *testing.T) {
+		pinnedAt := time.Now()
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(user, nil)
+		mockRoomUC.EXPECT().
+			PinMessage(ctx, user, "general", "msg123").
+			Return(&entities.Message{ID: "msg123", RoomID: "general", Pinned: true, PinnedBy: "mod123", PinnedAt: pinnedAt}, nil)
+
+		resp, err := handler.PinMessage(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, resp.Pinned)
+		assert.Equal(t, "mod123", resp.PinnedBy)
+		assert.Equal(t, pinnedAt.Format(time.RFC3339), resp.PinnedAt)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(nil, assert.AnError)
+
+		resp, err := handler.PinMessage(ctx, req)
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("pin rejected", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "test-token").Return(user, nil)
+		mockRoomUC.EXPECT().
+			PinMessage(ctx, user, "general", "msg123").
+			Return(nil, assert.AnError)
+
+		resp, err := handler.PinMessage(ctx, req)
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
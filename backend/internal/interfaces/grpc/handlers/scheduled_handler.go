@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) ScheduleMessage(ctx context.Context, req *pb.ScheduleMessageRequest) (*pb.ScheduledMessageResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "ScheduleMessage", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	sendAt, err := time.Parse(time.RFC3339, req.GetSendAt())
+	if err != nil {
+		return nil, fieldError("send_at", "INVALID_TIMESTAMP", "send_at must be an RFC3339 timestamp")
+	}
+
+	log.Printf("Scheduling message from user %s in room %s for %s", user.ID, req.GetRoomId(), sendAt.Format(time.RFC3339))
+
+	scheduled, err := h.scheduledUseCase.ScheduleMessage(ctx, user, req.GetRoomId(), req.GetContent(), sendAt, time.Duration(req.GetTtlSeconds())*time.Second)
+	if err != nil {
+		log.Printf("Error scheduling message: %v", err)
+		return nil, statusError(err)
+	}
+
+	return toScheduledMessageResponse(scheduled), nil
+}
+
+func (h *ChatHandler) ListScheduledMessages(ctx context.Context, req *pb.ListScheduledMessagesRequest) (*pb.ListScheduledMessagesResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "ListScheduledMessages", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	scheduled, err := h.scheduledUseCase.ListScheduledMessages(ctx, user, req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &pb.ListScheduledMessagesResponse{}
+	for _, s := range scheduled {
+		resp.ScheduledMessages = append(resp.ScheduledMessages, toScheduledMessageResponse(s))
+	}
+	return resp, nil
+}
+
+func (h *ChatHandler) CancelScheduledMessage(ctx context.Context, req *pb.CancelScheduledMessageRequest) (*pb.ScheduledMessageResponse, error) {
+	user, err := h.authenticateIn(ctx, req.GetToken(), "CancelScheduledMessage", func(user *entities.User) (string, error) {
+		scheduled, err := h.scheduledUseCase.GetScheduledMessage(ctx, user, req.GetScheduledMessageId())
+		if err != nil {
+			return "", err
+		}
+		return scheduled.RoomID, nil
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Cancelling scheduled message %s", req.GetScheduledMessageId())
+
+	scheduled, err := h.scheduledUseCase.CancelScheduledMessage(ctx, user, req.GetScheduledMessageId())
+	if err != nil {
+		log.Printf("Error cancelling scheduled message: %v", err)
+		return nil, statusError(err)
+	}
+
+	return toScheduledMessageResponse(scheduled), nil
+}
+
+func toScheduledMessageResponse(scheduled *entities.ScheduledMessage) *pb.ScheduledMessageResponse {
+	return &pb.ScheduledMessageResponse{
+		ScheduledMessageId: scheduled.ID,
+		RoomId:             scheduled.RoomID,
+		Content:            scheduled.Content,
+		SendAt:             scheduled.SendAt.Format(time.RFC3339),
+		Status:             string(scheduled.Status),
+		MessageId:          scheduled.MessageID,
+		CreatedAt:          scheduled.CreatedAt.Format(time.RFC3339),
+		Error:              scheduled.Error,
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const maxDeviceLabelLength = 100
+
+func (h *ChatHandler) Logout(ctx context.Context, req *pb.TokenRequest) (*pb.RevokeSessionsResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "Logout", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	if err := h.authUseCase.Logout(ctx, req.GetToken()); err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("User %s logged out", user.ID)
+	return &pb.RevokeSessionsResponse{Revoked: 1}, nil
+}
+
+func (h *ChatHandler) ListSessions(ctx context.Context, req *pb.TokenRequest) (*pb.ListSessionsResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "ListSessions", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	sessions, err := h.authUseCase.ListSessions(ctx, user.ID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &pb.ListSessionsResponse{}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, toSessionResponse(session, session.Token == req.GetToken()))
+	}
+	return resp, nil
+}
+
+func (h *ChatHandler) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionsResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "RevokeSession", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Revoking session %s of user %s", req.GetSessionId(), user.ID)
+
+	if err := h.authUseCase.RevokeSession(ctx, user.ID, req.GetSessionId()); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.RevokeSessionsResponse{Revoked: 1}, nil
+}
+
+func (h *ChatHandler) RevokeAllOtherSessions(ctx context.Context, req *pb.TokenRequest) (*pb.RevokeSessionsResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "RevokeAllOtherSessions", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	revoked, err := h.authUseCase.RevokeOtherSessions(ctx, user.ID, req.GetToken())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Revoked %d other sessions of user %s", revoked, user.ID)
+	return &pb.RevokeSessionsResponse{Revoked: int32(revoked)}, nil
+}
+
+func (h *ChatHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.AuthResponse, error) {
+	token, err := h.authUseCase.RefreshToken(ctx, req.GetRefreshToken(), h.sessionMetadata(ctx, ""))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	user, err := h.authUseCase.ValidateToken(ctx, token.Token)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return authResponse(token, user), nil
+}
+
+// sessionMetadata describes the client calling Register, Login or
+// RefreshToken. Its address is the peer's, or the one a trusted proxy put in
+// X-Forwarded-For.
+func (h *ChatHandler) sessionMetadata(ctx context.Context, deviceLabel string) entities.SessionMetadata {
+	if len(deviceLabel) > maxDeviceLabelLength {
+		deviceLabel = strings.ToValidUTF8(deviceLabel[:maxDeviceLabelLength], "")
+	}
+	session := entities.SessionMetadata{DeviceLabel: deviceLabel}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		session.IPAddress = h.trustedProxies.ClientAddress(p.Addr.String(), md.Get("x-forwarded-for"))
+	}
+	if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+		session.UserAgent = userAgent[0]
+	}
+
+	return session
+}
+
+func toSessionResponse(session *entities.AuthToken, current bool) *pb.SessionResponse {
+	resp := &pb.SessionResponse{
+		SessionId:   session.ID,
+		DeviceLabel: session.DeviceLabel,
+		IpAddress:   session.IPAddress,
+		UserAgent:   session.UserAgent,
+		ExpiresAt:   session.ExpiresAt.Format(time.RFC3339),
+		Current:     current,
+	}
+	if !session.CreatedAt.IsZero() {
+		resp.CreatedAt = session.CreatedAt.Format(time.RFC3339)
+	}
+	if !session.LastUsedAt.IsZero() {
+		resp.LastUsedAt = session.LastUsedAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func authResponse(token *entities.AuthToken, user *entities.User) *pb.AuthResponse {
+	resp := &pb.AuthResponse{
+		Success:      true,
+		Token:        token.Token,
+		UserId:       user.ID,
+		Username:     user.Username,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.ExpiresAt.Format(time.RFC3339),
+	}
+	if token.RefreshToken != "" {
+		resp.RefreshExpiresAt = token.SessionExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/interfaces/clientip"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/usecases"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestChatHandler_Sessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	handler := NewChatHandler(mockMsgUC, mockAuthUC)
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+
+	t.Run("list marks the current session", func(t *testing.T) {
+		now := time.Now()
+		mockAuthUC.EXPECT().ValidateToken(ctx, "tok1").Return(user, nil)
+		mockAuthUC.EXPECT().ListSessions(ctx, "user123").Return([]*entities.AuthToken{
+			{Token: "tok1", ID: "s1", DeviceLabel: "laptop", CreatedAt: now, LastUsedAt: now, ExpiresAt: now.Add(time.Hour)},
+			{Token: "tok2", ID: "s2", UserAgent: "curl/8.0", ExpiresAt: now.Add(time.Hour)},
+		}, nil)
+
+		resp, err := handler.ListSessions(ctx, &pb.TokenRequest{Token: "tok1"})
+		require.NoError(t, err)
+		require.Len(t, resp.Sessions, 2)
+		assert.True(t, resp.Sessions[0].Current)
+		assert.Equal(t, "laptop", resp.Sessions[0].DeviceLabel)
+		assert.False(t, resp.Sessions[1].Current)
+		assert.Empty(t, resp.Sessions[1].LastUsedAt)
+	})
+
+	t.Run("revoke other sessions", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(ctx, "tok1").Return(user, nil)
+		mockAuthUC.EXPECT().RevokeOtherSessions(ctx, "user123", "tok1").Return(3, nil)
+
+		resp, err := handler.RevokeAllOtherSessions(ctx, &pb.TokenRequest{Token: "tok1"})
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), resp.Revoked)
+	})
+
+	t.Run("login records the client", func(t *testing.T) {
+		loginCtx := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 5555}})
+		loginCtx = metadata.NewIncomingContext(loginCtx, metadata.Pairs("user-agent", "Mozilla/5.0"))
+
+		mockAuthUC.EXPECT().
+			Login(loginCtx, "mariem", "secret", entities.SessionMetadata{DeviceLabel: "phone", IPAddress: "10.0.0.2", UserAgent: "Mozilla/5.0"}).
+			Return(&entities.AuthToken{Token: "tok3"}, nil)
+		mockAuthUC.EXPECT().ValidateToken(loginCtx, "tok3").Return(user, nil)
+
+		_, err := handler.Login(loginCtx, &pb.UserRequest{Username: "mariem", Password: "secret", DeviceLabel: "phone"})
+		require.NoError(t, err)
+	})
+
+	t.Run("login with two-factor returns a challenge", func(t *testing.T) {
+		mockAuthUC.EXPECT().
+			Login(ctx, "mariem", "secret", gomock.Any()).
+			Return(&entities.AuthToken{UserID: "user123", Challenge: "chal", ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+		resp, err := handler.Login(ctx, &pb.UserRequest{Username: "mariem", Password: "secret"})
+		require.NoError(t, err)
+		assert.Equal(t, "chal", resp.Challenge)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("refresh returns the new token pair", func(t *testing.T) {
+		expiresAt := time.Now().Add(15 * time.Minute)
+		mockAuthUC.EXPECT().RefreshToken(ctx, "refresh1", gomock.Any()).Return(&entities.AuthToken{
+			Token:            "tok4",
+			RefreshToken:     "refresh2",
+			ExpiresAt:        expiresAt,
+			SessionExpiresAt: expiresAt.Add(time.Hour),
+		}, nil)
+		mockAuthUC.EXPECT().ValidateToken(ctx, "tok4").Return(user, nil)
+
+		resp, err := handler.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: "refresh1"})
+		require.NoError(t, err)
+		assert.Equal(t, "tok4", resp.Token)
+		assert.Equal(t, "refresh2", resp.RefreshToken)
+		assert.Equal(t, "user123", resp.UserId)
+		assert.Equal(t, expiresAt.Format(time.RFC3339), resp.ExpiresAt)
+		assert.NotEmpty(t, resp.RefreshExpiresAt)
+	})
+
+	t.Run("reused refresh token", func(t *testing.T) {
+		mockAuthUC.EXPECT().RefreshToken(ctx, "refresh1", gomock.Any()).Return(nil, usecases.ErrUnauthenticated)
+
+		_, err := handler.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: "refresh1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestSessionMetadata(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 5555}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "203.0.113.7, 10.0.0.1"))
+
+	t.Run("forwarded address is ignored by default", func(t *testing.T) {
+		h := NewChatHandler(nil, nil)
+		assert.Equal(t, "10.0.0.2", h.sessionMetadata(ctx, "").IPAddress)
+	})
+
+	t.Run("trusted proxy", func(t *testing.T) {
+		proxies, err := clientip.ParseTrustedProxies([]string{"10.0.0.0/8"})
+		require.NoError(t, err)
+		h := NewChatHandler(nil, nil, WithTrustedProxies(proxies))
+		assert.Equal(t, "203.0.113.7", h.sessionMetadata(ctx, "").IPAddress)
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) EnrollTwoFactor(ctx context.Context, req *pb.TokenRequest) (*pb.TwoFactorEnrollmentResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "EnrollTwoFactor", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	enrollment, err := h.authUseCase.EnrollTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.TwoFactorEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningUri: enrollment.URI,
+		RecoveryCodes:   enrollment.RecoveryCodes,
+	}, nil
+}
+
+func (h *ChatHandler) ConfirmTwoFactor(ctx context.Context, req *pb.TwoFactorCodeRequest) (*pb.TwoFactorStatusResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "ConfirmTwoFactor", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	if err := h.authUseCase.ConfirmTwoFactor(ctx, user.ID, req.GetCode()); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.TwoFactorStatusResponse{Enabled: true}, nil
+}
+
+func (h *ChatHandler) DisableTwoFactor(ctx context.Context, req *pb.DisableTwoFactorRequest) (*pb.TwoFactorStatusResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "DisableTwoFactor", "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	if err := h.authUseCase.DisableTwoFactor(ctx, user.ID, req.GetPassword(), req.GetCode()); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.TwoFactorStatusResponse{Enabled: false}, nil
+}
+
+func (h *ChatHandler) CompleteLogin(ctx context.Context, req *pb.CompleteLoginRequest) (*pb.AuthResponse, error) {
+	token, err := h.authUseCase.CompleteLogin(ctx, req.GetChallenge(), req.GetCode())
+	if err != nil {
+		return nil, authError(err)
+	}
+
+	user, err := h.authUseCase.ValidateToken(ctx, token.Token)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return authResponse(token, user), nil
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+func (h *ChatHandler) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.WebhookResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "CreateWebhook", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Creating webhook for room %s by user %s", req.GetRoomId(), user.ID)
+
+	webhook, err := h.webhookUseCase.CreateWebhook(ctx, user, req.GetRoomId(), req.GetUrl(), req.GetSecret(), req.GetEvents())
+	if err != nil {
+		log.Printf("Error creating webhook: %v", err)
+		return nil, statusError(err)
+	}
+
+	resp := toWebhookResponse(webhook)
+	resp.Secret = webhook.Secret
+	return resp, nil
+}
+
+func (h *ChatHandler) ListWebhooks(ctx context.Context, req *pb.RoomRequest) (*pb.ListWebhooksResponse, error) {
+	user, err := h.authenticate(ctx, req.GetToken(), "ListWebhooks", req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	webhooks, err := h.webhookUseCase.ListWebhooks(ctx, user, req.GetRoomId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &pb.ListWebhooksResponse{}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, toWebhookResponse(webhook))
+	}
+	return resp, nil
+}
+
+func (h *ChatHandler) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.WebhookResponse, error) {
+	user, err := h.authenticateIn(ctx, req.GetToken(), "DeleteWebhook", func(user *entities.User) (string, error) {
+		webhook, err := h.webhookUseCase.GetWebhook(ctx, user, req.GetWebhookId())
+		if err != nil {
+			return "", err
+		}
+		return webhook.RoomID, nil
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	log.Printf("Deleting webhook %s by user %s", req.GetWebhookId(), user.ID)
+
+	if err := h.webhookUseCase.DeleteWebhook(ctx, user, req.GetWebhookId()); err != nil {
+		log.Printf("Error deleting webhook: %v", err)
+		return nil, statusError(err)
+	}
+
+	return &pb.WebhookResponse{WebhookId: req.GetWebhookId()}, nil
+}
+
+func (h *ChatHandler) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	user, err := h.authenticateIn(ctx, req.GetToken(), "ListWebhookDeliveries", func(user *entities.User) (string, error) {
+		webhook, err := h.webhookUseCase.GetWebhook(ctx, user, req.GetWebhookId())
+		if err != nil {
+			return "", err
+		}
+		return webhook.RoomID, nil
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	deliveries, err := h.webhookUseCase.ListDeliveries(ctx, user, req.GetWebhookId(), int(req.GetLimit()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &pb.ListWebhookDeliveriesResponse{}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, toWebhookDeliveryResponse(delivery))
+	}
+	return resp, nil
+}
+
+func toWebhookResponse(webhook *entities.Webhook) *pb.WebhookResponse {
+	return &pb.WebhookResponse{
+		WebhookId: webhook.ID,
+		RoomId:    webhook.RoomID,
+		Url:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toWebhookDeliveryResponse(delivery *entities.WebhookDelivery) *pb.WebhookDelivery {
+	resp := &pb.WebhookDelivery{
+		DeliveryId:   delivery.ID,
+		WebhookId:    delivery.WebhookID,
+		MessageId:    delivery.MessageID,
+		Event:        delivery.Event,
+		Status:       string(delivery.Status),
+		Attempts:     int32(delivery.Attempts),
+		ResponseCode: int32(delivery.ResponseCode),
+		Error:        delivery.Error,
+		CreatedAt:    delivery.CreatedAt.Format(time.RFC3339),
+	}
+	if delivery.Status == entities.DeliveryPending {
+		resp.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+	if !delivery.DeliveredAt.IsZero() {
+		resp.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+	return resp
+}
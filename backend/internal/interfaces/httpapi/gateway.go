@@ -0,0 +1,521 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chat-app/backend/internal/interfaces/clientip"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const maxGatewayBody = 1 << 20
+
+var (
+	gatewayMarshal   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	gatewayUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// gatewayRoute maps a REST endpoint onto a ChatService RPC. Path wildcards
+// and query parameters are named after request fields; the bearer token
+// fills the request's "token" field.
+type gatewayRoute struct {
+	method      string
+	path        string
+	rpc         string
+	summary     string
+	public      bool
+	bodyFields  []string
+	queryFields []string
+	request     proto.Message
+	response    proto.Message
+	call        func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error)
+}
+
+var gatewayRoutes = []gatewayRoute{
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/login",
+		rpc:        "Login",
+		summary:    "Log in and get a session token",
+		public:     true,
+		bodyFields: []string{"username", "password", "device_label"},
+		request:    &pb.UserRequest{},
+		response:   &pb.AuthResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.Login(ctx, req.(*pb.UserRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/refresh",
+		rpc:        "RefreshToken",
+		summary:    "Exchange a refresh token for a new access token and refresh token",
+		public:     true,
+		bodyFields: []string{"refresh_token"},
+		request:    &pb.RefreshTokenRequest{},
+		response:   &pb.AuthResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.RefreshToken(ctx, req.(*pb.RefreshTokenRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/password",
+		rpc:        "ChangePassword",
+		summary:    "Change the password and end every other session",
+		bodyFields: []string{"current_password", "new_password"},
+		request:    &pb.ChangePasswordRequest{},
+		response:   &pb.RevokeSessionsResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.ChangePassword(ctx, req.(*pb.ChangePasswordRequest))
+		},
+	},
+	{
+		method:     http.MethodPut,
+		path:       "/v1/auth/email",
+		rpc:        "SetEmail",
+		summary:    "Set the email that password reset tokens are sent to",
+		bodyFields: []string{"email"},
+		request:    &pb.SetEmailRequest{},
+		response:   &pb.SetEmailResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.SetEmail(ctx, req.(*pb.SetEmailRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/password-reset",
+		rpc:        "RequestPasswordReset",
+		summary:    "Email a password reset token to the user",
+		public:     true,
+		bodyFields: []string{"username"},
+		request:    &pb.PasswordResetRequest{},
+		response:   &pb.PasswordResetResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.RequestPasswordReset(ctx, req.(*pb.PasswordResetRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/password-reset/confirm",
+		rpc:        "ResetPassword",
+		summary:    "Set a new password with a reset token and end every session",
+		public:     true,
+		bodyFields: []string{"reset_token", "new_password"},
+		request:    &pb.ResetPasswordRequest{},
+		response:   &pb.RevokeSessionsResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.ResetPassword(ctx, req.(*pb.ResetPasswordRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/login/complete",
+		rpc:        "CompleteLogin",
+		summary:    "Finish a two-factor login with a TOTP or recovery code",
+		public:     true,
+		bodyFields: []string{"challenge", "code"},
+		request:    &pb.CompleteLoginRequest{},
+		response:   &pb.AuthResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.CompleteLogin(ctx, req.(*pb.CompleteLoginRequest))
+		},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/v1/auth/two-factor",
+		rpc:      "EnrollTwoFactor",
+		summary:  "Start setting up two-factor authentication",
+		request:  &pb.TokenRequest{},
+		response: &pb.TwoFactorEnrollmentResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.EnrollTwoFactor(ctx, req.(*pb.TokenRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/two-factor/confirm",
+		rpc:        "ConfirmTwoFactor",
+		summary:    "Turn two-factor authentication on with a first code",
+		bodyFields: []string{"code"},
+		request:    &pb.TwoFactorCodeRequest{},
+		response:   &pb.TwoFactorStatusResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.ConfirmTwoFactor(ctx, req.(*pb.TwoFactorCodeRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/auth/two-factor/disable",
+		rpc:        "DisableTwoFactor",
+		summary:    "Turn two-factor authentication off",
+		bodyFields: []string{"password", "code"},
+		request:    &pb.DisableTwoFactorRequest{},
+		response:   &pb.TwoFactorStatusResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.DisableTwoFactor(ctx, req.(*pb.DisableTwoFactorRequest))
+		},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/v1/admin/users/{username}/unlock",
+		rpc:      "UnlockAccount",
+		summary:  "Clear the failed logins of a locked out username",
+		request:  &pb.UnlockAccountRequest{},
+		response: &pb.UnlockAccountResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.UnlockAccount(ctx, req.(*pb.UnlockAccountRequest))
+		},
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/v1/users/{username}/profile",
+		rpc:      "GetProfile",
+		summary:  "Get a user's profile",
+		request:  &pb.GetProfileRequest{},
+		response: &pb.ProfileResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.GetProfile(ctx, req.(*pb.GetProfileRequest))
+		},
+	},
+	{
+		method:     http.MethodPatch,
+		path:       "/v1/profile",
+		rpc:        "UpdateProfile",
+		summary:    "Change the fields of the current user's profile that are in the body",
+		bodyFields: []string{"display_name", "avatar_url", "bio", "status_text", "status_ttl_seconds", "timezone"},
+		request:    &pb.UpdateProfileRequest{},
+		response:   &pb.ProfileResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.UpdateProfile(ctx, req.(*pb.UpdateProfileRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/profiles/lookup",
+		rpc:        "GetProfiles",
+		summary:    "Get the profiles of several users by user ID",
+		bodyFields: []string{"user_ids"},
+		request:    &pb.GetProfilesRequest{},
+		response:   &pb.GetProfilesResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.GetProfiles(ctx, req.(*pb.GetProfilesRequest))
+		},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/v1/auth/logout",
+		rpc:      "Logout",
+		summary:  "End the session of the bearer token",
+		request:  &pb.TokenRequest{},
+		response: &pb.RevokeSessionsResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.Logout(ctx, req.(*pb.TokenRequest))
+		},
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/v1/sessions",
+		rpc:      "ListSessions",
+		summary:  "List the current user's sessions, most recently used first",
+		request:  &pb.TokenRequest{},
+		response: &pb.ListSessionsResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.ListSessions(ctx, req.(*pb.TokenRequest))
+		},
+	},
+	{
+		method:   http.MethodDelete,
+		path:     "/v1/sessions/{session_id}",
+		rpc:      "RevokeSession",
+		summary:  "End one of the current user's sessions",
+		request:  &pb.RevokeSessionRequest{},
+		response: &pb.RevokeSessionsResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.RevokeSession(ctx, req.(*pb.RevokeSessionRequest))
+		},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/v1/sessions/revoke-others",
+		rpc:      "RevokeAllOtherSessions",
+		summary:  "End every session of the current user except this one",
+		request:  &pb.TokenRequest{},
+		response: &pb.RevokeSessionsResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.RevokeAllOtherSessions(ctx, req.(*pb.TokenRequest))
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       "/v1/rooms/{room_id}/messages",
+		rpc:        "SendMessage",
+		summary:    "Send a message to a room",
+		bodyFields: []string{"content", "ttl_seconds"},
+		request:    &pb.MessageRequest{},
+		response:   &pb.MessageResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.SendMessage(ctx, req.(*pb.MessageRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/rooms/{room_id}/messages",
+		rpc:         "GetMessageHistory",
+		summary:     "List the latest messages of a room, oldest first",
+		queryFields: []string{"limit"},
+		request:     &pb.HistoryRequest{},
+		response:    &pb.HistoryResponse{},
+		call: func(ctx context.Context, chat pb.ChatServiceServer, req proto.Message) (proto.Message, error) {
+			return chat.GetMessageHistory(ctx, req.(*pb.HistoryRequest))
+		},
+	},
+}
+
+// Gateway serves a JSON/REST view of the ChatService next to gRPC-Web. Every
+// route calls the same ChatHandler method the gRPC clients use.
+type Gateway struct {
+	chat    pb.ChatServiceServer
+	proxies *clientip.TrustedProxies
+}
+
+// NewGateway serves the routes with chat. The client address handed to it
+// is the one proxies forwarded, if the request came through them.
+func NewGateway(chat pb.ChatServiceServer, proxies *clientip.TrustedProxies) *Gateway {
+	return &Gateway{chat: chat, proxies: proxies}
+}
+
+func (g *Gateway) Register(mux *http.ServeMux) {
+	for _, route := range gatewayRoutes {
+		route := route
+		mux.HandleFunc(route.method+" "+route.path, func(w http.ResponseWriter, r *http.Request) {
+			g.serve(w, r, route)
+		})
+	}
+	mux.HandleFunc("GET /v1/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, openAPIDocument(gatewayRoutes))
+	})
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+	})
+}
+
+func (g *Gateway) serve(w http.ResponseWriter, r *http.Request, route gatewayRoute) {
+	req := route.request.ProtoReflect().New()
+
+	if err := bindGatewayRequest(w, r, route, req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	if !route.public {
+		token := bearerToken(r)
+		if token == "" {
+			writeError(w, http.StatusUnauthorized, "unauthenticated", "bearer token is required")
+			return
+		}
+		req.Set(req.Descriptor().Fields().ByName("token"), protoreflect.ValueOfString(token))
+	}
+
+	// The handlers read the client's address from the peer and its user
+	// agent from gRPC metadata, as they would for a gRPC-Web call.
+	ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs("user-agent", r.UserAgent()))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: clientAddr(g.proxies.Request(r))})
+	resp, err := route.call(ctx, g.chat, req.Interface())
+	if err != nil {
+		writeGatewayError(w, route.rpc, err)
+		return
+	}
+
+	body, err := gatewayMarshal.Marshal(resp)
+	if err != nil {
+		log.Printf("Error encoding %s response: %v", route.rpc, err)
+		writeError(w, http.StatusInternalServerError, "internal", "failed to encode response")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// bindGatewayRequest fills req from the JSON body, the query string and the
+// path. Body fields not listed for the route are dropped, so clients cannot
+// set the token or impersonate another user through the body.
+func bindGatewayRequest(w http.ResponseWriter, r *http.Request, route gatewayRoute, req protoreflect.Message) error {
+	fields := req.Descriptor().Fields()
+
+	if len(route.bodyFields) > 0 {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxGatewayBody))
+		if err != nil {
+			return fmt.Errorf("request body is too large")
+		}
+		if len(body) > 0 {
+			if err := gatewayUnmarshal.Unmarshal(body, req.Interface()); err != nil {
+				return fmt.Errorf("body must be a JSON object matching the request schema")
+			}
+		}
+		req.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			if !containsString(route.bodyFields, string(fd.Name())) {
+				req.Clear(fd)
+			}
+			return true
+		})
+	}
+
+	for _, name := range route.queryFields {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		if err := setScalarField(req, fields.ByName(protoreflect.Name(name)), raw); err != nil {
+			return fmt.Errorf("query parameter %s: %v", name, err)
+		}
+	}
+
+	for _, name := range pathParams(route.path) {
+		if err := setScalarField(req, fields.ByName(protoreflect.Name(name)), r.PathValue(name)); err != nil {
+			return fmt.Errorf("path parameter %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+func setScalarField(req protoreflect.Message, fd protoreflect.FieldDescriptor, raw string) error {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		req.Set(fd, protoreflect.ValueOfString(raw))
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+		req.Set(fd, protoreflect.ValueOfBool(v))
+	case protoreflect.Int32Kind:
+		v, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		req.Set(fd, protoreflect.ValueOfInt32(int32(v)))
+	default:
+		return fmt.Errorf("unsupported field type %s", fd.Kind())
+	}
+	return nil
+}
+
+// clientAddr is a resolved client address as the net.Addr of a peer.
+type clientAddr string
+
+func (a clientAddr) Network() string { return "tcp" }
+func (a clientAddr) String() string  { return string(a) }
+
+func pathParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, segment[1:len(segment)-1])
+		}
+	}
+	return params
+}
+
+func writeGatewayError(w http.ResponseWriter, rpc string, err error) {
+	httpStatus, body := gatewayError(rpc, err)
+	writeErrorBody(w, httpStatus, body)
+}
+
+// gatewayError maps the gRPC status of err onto an HTTP status and error
+// body, copying the reason, field and retry delay from its details.
+// Handlers that return plain errors report them as bad requests.
+func gatewayError(rpc string, err error) (int, errorBody) {
+	st := status.Convert(err)
+	code := st.Code()
+	if code == codes.Unknown {
+		code = codes.InvalidArgument
+	}
+
+	httpStatus := httpStatusFromCode(code)
+	message := st.Message()
+	if httpStatus == http.StatusInternalServerError {
+		log.Printf("Error handling %s: %v", rpc, err)
+		message = "internal error"
+	}
+	body := errorBody{Code: errorCodeName(code), Message: message}
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			body.Reason = detail.GetReason()
+		case *errdetails.BadRequest:
+			if violations := detail.GetFieldViolations(); len(violations) > 0 {
+				body.Field = violations[0].GetField()
+			}
+		case *errdetails.RetryInfo:
+			body.retryAfter = detail.GetRetryDelay().AsDuration()
+		}
+	}
+	return httpStatus, body
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCodeName turns codes.InvalidArgument into "invalid_argument".
+func errorCodeName(code codes.Code) string {
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
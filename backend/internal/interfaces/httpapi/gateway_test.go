@@ -0,0 +1,149 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/interfaces/grpc/handlers"
+	"chat-app/backend/internal/usecases"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGateway(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	mux := http.NewServeMux()
+	NewGateway(handlers.NewChatHandler(mockMsgUC, mockAuthUC), nil).Register(mux)
+
+	serve := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	user := &entities.User{ID: "user123", Username: "mariem"}
+
+	t.Run("login", func(t *testing.T) {
+		mockAuthUC.EXPECT().Login(gomock.Any(), "mariem", "secret", gomock.Any()).Return(&entities.AuthToken{Token: "tok"}, nil)
+		mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "tok").Return(user, nil)
+
+		rec := serve(http.MethodPost, "/v1/auth/login", "", `{"username":"mariem","password":"secret"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "tok", resp["token"])
+		assert.Equal(t, "user123", resp["user_id"])
+	})
+
+	t.Run("forwarded addresses from clients are ignored", func(t *testing.T) {
+		mockAuthUC.EXPECT().
+			Login(gomock.Any(), "mariem", "secret", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, username, password string, session entities.SessionMetadata) (*entities.AuthToken, error) {
+				assert.Equal(t, "192.0.2.1", session.IPAddress)
+				return nil, usecases.ErrUnauthenticated
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(`{"username":"mariem","password":"secret"}`))
+		req.Header.Set("X-Forwarded-For", "198.51.100.9")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("failed login is unauthenticated", func(t *testing.T) {
+		mockAuthUC.EXPECT().Login(gomock.Any(), "mariem", "wrong", gomock.Any()).Return(nil, usecases.ErrUnauthenticated)
+
+		rec := serve(http.MethodPost, "/v1/auth/login", "", `{"username":"mariem","password":"wrong"}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":"unauthenticated","message":"unauthenticated","reason":"UNAUTHENTICATED"}}`, rec.Body.String())
+	})
+
+	t.Run("storage failures are internal errors", func(t *testing.T) {
+		mockAuthUC.EXPECT().Login(gomock.Any(), "mariem", "secret", gomock.Any()).Return(nil, assert.AnError)
+
+		rec := serve(http.MethodPost, "/v1/auth/login", "", `{"username":"mariem","password":"secret"}`)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":"internal","message":"internal error"}}`, rec.Body.String())
+	})
+
+	t.Run("send message uses the path room and token user", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "tok").Return(user, nil)
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+				assert.Equal(t, "general", params.RoomID)
+				assert.Equal(t, "user123", params.UserID)
+				assert.Equal(t, "hello", params.Content)
+				return &entities.Message{ID: "msg1", UserID: params.UserID, Username: params.Username, Content: params.Content, RoomID: params.RoomID, Timestamp: time.Now()}, nil
+			})
+
+		rec := serve(http.MethodPost, "/v1/rooms/general/messages", "tok", `{"content":"hello","room_id":"other","user_id":"someone"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"message_id":"msg1"`)
+	})
+
+	t.Run("history reads the limit from the query", func(t *testing.T) {
+		mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "tok").Return(user, nil).AnyTimes()
+		mockMsgUC.EXPECT().
+			GetMessageHistory(gomock.Any(), "general", 10).
+			Return([]*entities.Message{{ID: "msg1", RoomID: "general"}}, nil)
+
+		rec := serve(http.MethodGet, "/v1/rooms/general/messages?limit=10", "tok", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"messages":[`)
+	})
+
+	t.Run("errors use the error schema", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/v1/rooms/general/messages", "", `{"content":"hello"}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":"unauthenticated","message":"bearer token is required"}}`, rec.Body.String())
+
+		rec = serve(http.MethodGet, "/v1/rooms/general/messages?limit=ten", "tok", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = serve(http.MethodGet, "/v1/nothing", "", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("openapi document", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/v1/openapi.json", "", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var doc struct {
+			Paths      map[string]map[string]json.RawMessage `json:"paths"`
+			Components struct {
+				Schemas map[string]json.RawMessage `json:"schemas"`
+			} `json:"components"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Contains(t, doc.Paths["/v1/rooms/{room_id}/messages"], "post")
+		assert.Contains(t, doc.Paths["/v1/rooms/{room_id}/messages"], "get")
+		assert.Contains(t, doc.Paths, "/v1/auth/login")
+		assert.Contains(t, doc.Components.Schemas, "MessageResponse")
+		assert.Contains(t, doc.Components.Schemas, "PollOption")
+	})
+}
+
+func TestErrorCodeName(t *testing.T) {
+	assert.Equal(t, "invalid_argument", errorCodeName(codes.InvalidArgument))
+	assert.Equal(t, "deadline_exceeded", errorCodeName(codes.DeadlineExceeded))
+}
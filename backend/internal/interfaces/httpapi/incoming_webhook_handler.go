@@ -0,0 +1,89 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/usecases"
+)
+
+const maxIncomingWebhookBody = 64 * 1024
+
+// IncomingWebhookHandler serves POST /hooks/{id}/{token}. The token can also
+// be sent as "Authorization: Bearer <token>" to POST /hooks/{id}, which keeps
+// it out of access logs.
+type IncomingWebhookHandler struct {
+	useCase usecases.IncomingWebhookUseCase
+}
+
+func NewIncomingWebhookHandler(useCase usecases.IncomingWebhookUseCase) *IncomingWebhookHandler {
+	return &IncomingWebhookHandler{useCase: useCase}
+}
+
+func (h *IncomingWebhookHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /hooks/{id}", h.post)
+	mux.HandleFunc("POST /hooks/{id}/{token}", h.post)
+}
+
+type incomingWebhookResponse struct {
+	OK        bool   `json:"ok"`
+	MessageID string `json:"message_id"`
+}
+
+func (h *IncomingWebhookHandler) post(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	if token == "" {
+		token = bearerToken(r)
+	}
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "unauthenticated", "webhook token is required")
+		return
+	}
+
+	var payload entities.IncomingWebhookPayload
+	if err := decodeIncomingWebhookPayload(w, r, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+
+	message, err := h.useCase.Post(r.Context(), r.PathValue("id"), token, &payload)
+	if err != nil {
+		status, body := useCaseError("posting incoming webhook "+r.PathValue("id"), err)
+		writeErrorBody(w, status, body)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, incomingWebhookResponse{OK: true, MessageID: message.ID})
+}
+
+// decodeIncomingWebhookPayload reads a JSON body, or the form field
+// "payload" that Slack clients send as application/x-www-form-urlencoded.
+func decodeIncomingWebhookPayload(w http.ResponseWriter, r *http.Request, payload *entities.IncomingWebhookPayload) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxIncomingWebhookBody)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return errors.New("invalid form body")
+		}
+		if err := json.Unmarshal([]byte(r.PostForm.Get("payload")), payload); err != nil {
+			return errors.New("payload must be a JSON object")
+		}
+		return nil
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		return errors.New("body must be a JSON object")
+	}
+	return nil
+}
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/usecases"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncomingWebhookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIncomingUC := mocks.NewMockIncomingWebhookUseCase(ctrl)
+	mux := http.NewServeMux()
+	NewIncomingWebhookHandler(mockIncomingUC).Register(mux)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("token in the path", func(t *testing.T) {
+		mockIncomingUC.EXPECT().
+			Post(gomock.Any(), "hook1", "secret", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, webhookID, token string, payload *entities.IncomingWebhookPayload) (*entities.Message, error) {
+				assert.Equal(t, "hello", payload.Text)
+				return &entities.Message{ID: "msg1"}, nil
+			})
+
+		rec := serve(httptest.NewRequest(http.MethodPost, "/hooks/hook1/secret", strings.NewReader(`{"text":"hello"}`)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"ok":true,"message_id":"msg1"}`, rec.Body.String())
+	})
+
+	t.Run("bearer token and slack form body", func(t *testing.T) {
+		mockIncomingUC.EXPECT().
+			Post(gomock.Any(), "hook1", "secret", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, webhookID, token string, payload *entities.IncomingWebhookPayload) (*entities.Message, error) {
+				assert.Len(t, payload.Attachments, 1)
+				return &entities.Message{ID: "msg2"}, nil
+			})
+
+		form := url.Values{"payload": {`{"attachments":[{"text":"alert"}]}`}}
+		req := httptest.NewRequest(http.MethodPost, "/hooks/hook1", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		rec := serve(req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		mockIncomingUC.EXPECT().Post(gomock.Any(), "hook1", "wrong", gomock.Any()).Return(nil, usecases.ErrInvalidWebhookToken)
+
+		rec := serve(httptest.NewRequest(http.MethodPost, "/hooks/hook1/wrong", strings.NewReader(`{"text":"hello"}`)))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":"unauthenticated","message":"invalid webhook token","reason":"INVALID_WEBHOOK_TOKEN"}}`, rec.Body.String())
+	})
+
+	t.Run("domain errors keep their status", func(t *testing.T) {
+		mockIncomingUC.EXPECT().
+			Post(gomock.Any(), "hook1", "secret", gomock.Any()).
+			Return(nil, &usecases.Error{Kind: usecases.KindPermissionDenied, Reason: "MUTED", Message: "webhook is muted in this room"})
+
+		rec := serve(httptest.NewRequest(http.MethodPost, "/hooks/hook1/secret", strings.NewReader(`{"text":"hello"}`)))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":"permission_denied","message":"webhook is muted in this room","reason":"MUTED"}}`, rec.Body.String())
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		rec := serve(httptest.NewRequest(http.MethodPost, "/hooks/hook1", strings.NewReader(`{"text":"hello"}`)))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		rec := serve(httptest.NewRequest(http.MethodPost, "/hooks/hook1/secret", strings.NewReader(`text=hello`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,183 @@
+package httpapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/interfaces/clientip"
+	"chat-app/backend/internal/usecases"
+)
+
+const (
+	oidcCookie           = "oidc_state"
+	maxDeviceLabelLength = 100
+)
+
+type OIDCConfig struct {
+	// FrontendURL receives the session after sign-in, in the URL fragment
+	// so that it is not sent to any server. Without it the callback answers
+	// with JSON.
+	FrontendURL string
+	// StateTTL is how long the user has to sign in at the provider.
+	StateTTL time.Duration
+	// TrustedProxies are believed about the address of the client.
+	TrustedProxies *clientip.TrustedProxies
+}
+
+func DefaultOIDCConfig() OIDCConfig {
+	return OIDCConfig{StateTTL: 10 * time.Minute}
+}
+
+// OIDCHandler signs users in with an OpenID Connect provider. GET
+// /v1/auth/oidc/login sends the browser to the provider, which sends it
+// back to GET /v1/auth/oidc/callback. The sign-in request is kept in a
+// cookie in between.
+type OIDCHandler struct {
+	useCase usecases.SSOUseCase
+	config  OIDCConfig
+}
+
+func NewOIDCHandler(useCase usecases.SSOUseCase, config OIDCConfig) *OIDCHandler {
+	return &OIDCHandler{useCase: useCase, config: config}
+}
+
+func (h *OIDCHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/auth/oidc/login", h.login)
+	mux.HandleFunc("GET /v1/auth/oidc/callback", h.callback)
+}
+
+type oidcSessionResponse struct {
+	Token            string `json:"token"`
+	UserID           string `json:"user_id"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresAt        string `json:"expires_at"`
+	RefreshExpiresAt string `json:"refresh_expires_at"`
+}
+
+func (h *OIDCHandler) login(w http.ResponseWriter, r *http.Request) {
+	deviceLabel := r.URL.Query().Get("device_label")
+	if len(deviceLabel) > maxDeviceLabelLength {
+		deviceLabel = strings.ToValidUTF8(deviceLabel[:maxDeviceLabelLength], "")
+	}
+	request, err := h.useCase.BeginLogin(deviceLabel)
+	if err != nil {
+		status, body := useCaseError("starting sign-in", err)
+		writeErrorBody(w, status, body)
+		return
+	}
+
+	stored := *request
+	stored.AuthURL = ""
+	value, err := json.Marshal(stored)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal", "internal error")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    base64.RawURLEncoding.EncodeToString(value),
+		Path:     "/v1/auth/oidc",
+		MaxAge:   int(h.config.StateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		// Lax sends the cookie along with the provider's redirect back.
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, request.AuthURL, http.StatusFound)
+}
+
+func (h *OIDCHandler) callback(w http.ResponseWriter, r *http.Request) {
+	request := readSSORequest(r)
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Path:     "/v1/auth/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.fail(w, r, http.StatusUnauthorized, errorBody{
+			Code:    "unauthenticated",
+			Message: "identity provider sign-in failed: " + providerErr,
+			Reason:  "SSO_FAILED",
+		})
+		return
+	}
+
+	token, err := h.useCase.CompleteLogin(r.Context(), request, query.Get("state"), query.Get("code"), h.sessionMetadata(r))
+	if err != nil {
+		status, body := useCaseError("completing sign-in", err)
+		h.fail(w, r, status, body)
+		return
+	}
+
+	session := oidcSessionResponse{
+		Token:        token.Token,
+		UserID:       token.UserID,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.ExpiresAt.Format(time.RFC3339),
+	}
+	if token.RefreshToken != "" {
+		session.RefreshExpiresAt = token.SessionExpiresAt.Format(time.RFC3339)
+	}
+	if h.config.FrontendURL == "" {
+		writeJSON(w, http.StatusOK, session)
+		return
+	}
+
+	fragment := url.Values{}
+	fragment.Set("token", session.Token)
+	fragment.Set("user_id", session.UserID)
+	fragment.Set("expires_at", session.ExpiresAt)
+	if session.RefreshToken != "" {
+		fragment.Set("refresh_token", session.RefreshToken)
+		fragment.Set("refresh_expires_at", session.RefreshExpiresAt)
+	}
+	http.Redirect(w, r, h.config.FrontendURL+"#"+fragment.Encode(), http.StatusSeeOther)
+}
+
+// fail sends the browser back to the frontend with the error's reason, or
+// writes the error when there is no frontend.
+func (h *OIDCHandler) fail(w http.ResponseWriter, r *http.Request, status int, body errorBody) {
+	if h.config.FrontendURL == "" {
+		writeErrorBody(w, status, body)
+		return
+	}
+	fragment := url.Values{}
+	fragment.Set("error", body.Code)
+	fragment.Set("reason", body.Reason)
+	fragment.Set("message", body.Message)
+	http.Redirect(w, r, h.config.FrontendURL+"#"+fragment.Encode(), http.StatusSeeOther)
+}
+
+func readSSORequest(r *http.Request) *entities.SSORequest {
+	cookie, err := r.Cookie(oidcCookie)
+	if err != nil {
+		return nil
+	}
+	value, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return nil
+	}
+	var request entities.SSORequest
+	if err := json.Unmarshal(value, &request); err != nil {
+		return nil
+	}
+	return &request
+}
+
+func (h *OIDCHandler) sessionMetadata(r *http.Request) entities.SessionMetadata {
+	return entities.SessionMetadata{IPAddress: h.config.TrustedProxies.Request(r), UserAgent: r.UserAgent()}
+}
+
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,149 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/infrastructure/oidc"
+	"chat-app/backend/internal/infrastructure/oidc/oidctest"
+	"chat-app/backend/internal/usecases"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idp := oidctest.NewServer(map[string]interface{}{
+		"sub":                "sub-1",
+		"preferred_username": "mariem",
+		"email":              "mariem@example.com",
+		"email_verified":     true,
+	})
+	defer idp.Close()
+
+	// The provider redirects back to this server, whichever handler
+	// serves it.
+	var handler http.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := oidc.Discover(context.Background(), oidc.Config{
+		IssuerURL:    idp.URL,
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  server.URL + "/v1/auth/oidc/callback",
+	}, idp.Client())
+	require.NoError(t, err)
+
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	ssoUC := usecases.NewSSOUseCase(provider, mockAuthUC, usecases.DefaultSSOConfig())
+	mux := http.NewServeMux()
+	NewOIDCHandler(ssoUC, DefaultOIDCConfig()).Register(mux)
+	handler = mux
+
+	newBrowser := func() *http.Client {
+		jar, _ := cookiejar.New(nil)
+		return &http.Client{Jar: jar}
+	}
+
+	t.Run("signs in through the provider", func(t *testing.T) {
+		expiresAt := time.Now().Add(15 * time.Minute).Truncate(time.Second)
+		mockAuthUC.EXPECT().
+			LoginExternal(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, identity entities.ExternalIdentity, session entities.SessionMetadata) (*entities.AuthToken, error) {
+				assert.Equal(t, idp.URL, identity.Issuer)
+				assert.Equal(t, "sub-1", identity.Subject)
+				assert.Equal(t, "mariem", identity.Username)
+				assert.Equal(t, "mariem@example.com", identity.Email)
+				assert.Equal(t, "laptop", session.DeviceLabel)
+				assert.Equal(t, "127.0.0.1", session.IPAddress)
+				return &entities.AuthToken{Token: "session-token", UserID: "user123", ExpiresAt: expiresAt}, nil
+			})
+
+		resp, err := newBrowser().Get(server.URL + "/v1/auth/oidc/login?device_label=laptop")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body oidcSessionResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "session-token", body.Token)
+		assert.Equal(t, "user123", body.UserID)
+		assert.Equal(t, expiresAt.Format(time.RFC3339), body.ExpiresAt)
+	})
+
+	t.Run("callback without the login cookie", func(t *testing.T) {
+		browser := newBrowser()
+		browser.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if req.URL.Path == "/v1/auth/oidc/callback" {
+				// Another browser finishes the sign-in.
+				browser.Jar, _ = cookiejar.New(nil)
+			}
+			return nil
+		}
+
+		resp, err := browser.Get(server.URL + "/v1/auth/oidc/login")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		var body errorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "SSO_STATE_MISMATCH", body.Error.Reason)
+	})
+
+	t.Run("provider error redirects to the frontend", func(t *testing.T) {
+		oidcHandler := NewOIDCHandler(ssoUC, OIDCConfig{FrontendURL: "https://chat.example.com/sso", StateTTL: time.Minute})
+		rec := httptest.NewRecorder()
+		oidcHandler.callback(rec, httptest.NewRequest(http.MethodGet, "/v1/auth/oidc/callback?error=access_denied", nil))
+
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		location, err := url.Parse(rec.Header().Get("Location"))
+		require.NoError(t, err)
+		fragment, _ := url.ParseQuery(location.Fragment)
+		assert.Equal(t, "SSO_FAILED", fragment.Get("reason"))
+	})
+
+	t.Run("session in the frontend URL fragment", func(t *testing.T) {
+		mockAuthUC.EXPECT().
+			LoginExternal(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&entities.AuthToken{Token: "session-token", UserID: "user123", RefreshToken: "refresh"}, nil)
+
+		frontendMux := http.NewServeMux()
+		NewOIDCHandler(ssoUC, OIDCConfig{FrontendURL: "https://chat.example.com/sso", StateTTL: time.Minute}).Register(frontendMux)
+		handler = frontendMux
+		defer func() { handler = mux }()
+
+		browser := newBrowser()
+		var landed *url.URL
+		browser.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if req.URL.Host == "chat.example.com" {
+				landed = req.URL
+				return http.ErrUseLastResponse
+			}
+			return nil
+		}
+		resp, err := browser.Get(server.URL + "/v1/auth/oidc/login")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.NotNil(t, landed)
+		fragment, _ := url.ParseQuery(landed.Fragment)
+		assert.Equal(t, "session-token", fragment.Get("token"))
+		assert.Equal(t, "refresh", fragment.Get("refresh_token"))
+	})
+}
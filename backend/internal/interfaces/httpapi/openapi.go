@@ -0,0 +1,189 @@
+package httpapi
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPIDocument builds an OpenAPI 3 description of the gateway from the
+// route table and the compiled proto descriptors, so it always matches the
+// messages the endpoints actually send and accept.
+func openAPIDocument(routes []gatewayRoute) map[string]interface{} {
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"error": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"code":    map[string]interface{}{"type": "string", "example": "invalid_argument"},
+						"message": map[string]interface{}{"type": "string"},
+						"reason":  map[string]interface{}{"type": "string", "example": "INVALID_CREDENTIALS"},
+						"field":   map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+	paths := make(map[string]interface{})
+
+	for _, route := range routes {
+		request := route.request.ProtoReflect().Descriptor()
+		response := route.response.ProtoReflect().Descriptor()
+
+		var parameters []interface{}
+		for _, name := range pathParams(route.path) {
+			parameters = append(parameters, openAPIParameter(request, name, "path"))
+		}
+		for _, name := range route.queryFields {
+			parameters = append(parameters, openAPIParameter(request, name, "query"))
+		}
+
+		operation := map[string]interface{}{
+			"operationId": route.rpc,
+			"summary":     route.summary,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "OK",
+					"content":     jsonContent(messageRef(response, schemas)),
+				},
+				"default": map[string]interface{}{
+					"description": "Error",
+					"content":     jsonContent(map[string]interface{}{"$ref": "#/components/schemas/Error"}),
+				},
+			},
+		}
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+		if len(route.bodyFields) > 0 {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  jsonContent(messageSchema(request, route.bodyFields, schemas)),
+			}
+		}
+		if !route.public {
+			operation["security"] = []interface{}{map[string]interface{}{"bearerAuth": []string{}}}
+		}
+
+		item, ok := paths[route.path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[route.path] = item
+		}
+		item[strings.ToLower(route.method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Chat App REST API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":   "http",
+					"scheme": "bearer",
+				},
+			},
+		},
+	}
+}
+
+func openAPIParameter(request protoreflect.MessageDescriptor, name, in string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     name,
+		"in":       in,
+		"required": in == "path",
+		"schema":   scalarSchema(request.Fields().ByName(protoreflect.Name(name))),
+	}
+}
+
+func jsonContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// messageRef registers the message and everything it references under
+// components/schemas and returns a $ref to it.
+func messageRef(md protoreflect.MessageDescriptor, schemas map[string]interface{}) map[string]interface{} {
+	name := string(md.Name())
+	if _, ok := schemas[name]; !ok {
+		// Reserve the name first so recursive messages terminate.
+		schemas[name] = nil
+		schemas[name] = messageSchema(md, nil, schemas)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// messageSchema describes md as a JSON object with proto field names. When
+// only is set, other fields are left out.
+func messageSchema(md protoreflect.MessageDescriptor, only []string, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if only != nil && !containsString(only, string(fd.Name())) {
+			continue
+		}
+		properties[string(fd.Name())] = fieldSchema(fd, schemas)
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]interface{}) map[string]interface{} {
+	if fd.IsMap() {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": fieldSchema(fd.MapValue(), schemas),
+		}
+	}
+
+	var schema map[string]interface{}
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		schema = messageRef(fd.Message(), schemas)
+	} else {
+		schema = scalarSchema(fd)
+	}
+
+	if fd.IsList() {
+		return map[string]interface{}{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+// scalarSchema follows the protojson encoding, which writes 64-bit integers
+// as strings.
+func scalarSchema(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		var values []string
+		enumValues := fd.Enum().Values()
+		for i := 0; i < enumValues.Len(); i++ {
+			values = append(values, string(enumValues.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": values}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
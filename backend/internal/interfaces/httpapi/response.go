@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chat-app/backend/internal/interfaces/grpc/handlers"
+	"chat-app/backend/internal/usecases"
+
+	"google.golang.org/grpc/codes"
+)
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Reason and Field come from the status details of RPC errors.
+	Reason     string        `json:"reason,omitempty"`
+	Field      string        `json:"field,omitempty"`
+	retryAfter time.Duration `json:"-"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// writeError writes {"error": {"code": ..., "message": ...}}. The code is a
+// stable machine-readable string; the message is for humans.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeErrorBody(w, status, errorBody{Code: code, Message: message})
+}
+
+func writeErrorBody(w http.ResponseWriter, status int, body errorBody) {
+	if body.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(body.retryAfter.Round(time.Second)/time.Second)))
+	}
+	writeJSON(w, status, errorResponse{Error: body})
+}
+
+// useCaseError maps an error returned by a usecase the way the gateway maps
+// the status the gRPC handlers would have returned for it.
+func useCaseError(action string, err error) (int, errorBody) {
+	var code codes.Code
+	domainErr, ok := usecases.AsError(err)
+	if ok {
+		code, ok = handlers.ErrorCode(domainErr.Kind)
+	}
+	if !ok {
+		log.Printf("Error %s: %v", action, err)
+		return http.StatusInternalServerError, errorBody{Code: errorCodeName(codes.Internal), Message: "internal error"}
+	}
+	return httpStatusFromCode(code), errorBody{
+		Code:       errorCodeName(code),
+		Message:    domainErr.Message,
+		Reason:     domainErr.Reason,
+		Field:      domainErr.Field,
+		retryAfter: domainErr.RetryAfter,
+	}
+}
@@ -0,0 +1,215 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+)
+
+type SSEConfig struct {
+	HeartbeatInterval time.Duration
+	WriteWait         time.Duration
+	// RetryDelay is sent to clients as the reconnection delay.
+	RetryDelay time.Duration
+}
+
+func DefaultSSEConfig() SSEConfig {
+	return SSEConfig{
+		HeartbeatInterval: 15 * time.Second,
+		WriteWait:         10 * time.Second,
+		RetryDelay:        3 * time.Second,
+	}
+}
+
+// SSEHandler serves GET /v1/rooms/{room_id}/events as a Server-Sent Events
+// feed. Each data line is a MessageResponse in the REST gateway's JSON form;
+// ?include_updates=true adds edits as for StreamMessages.
+//
+// New messages carry an id of "<unix timestamp>-<message id>". A client that
+// reconnects with Last-Event-ID (or ?last_event_id=) resumes after that
+// message; without one, the feed starts at the time of the request.
+type SSEHandler struct {
+	chat   pb.ChatServiceServer
+	config SSEConfig
+}
+
+func NewSSEHandler(chat pb.ChatServiceServer, config SSEConfig) *SSEHandler {
+	return &SSEHandler{chat: chat, config: config}
+}
+
+func (h *SSEHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/rooms/{room_id}/events", h.serve)
+}
+
+func (h *SSEHandler) serve(w http.ResponseWriter, r *http.Request) {
+	roomID := r.PathValue("room_id")
+	token := bearerToken(r)
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "unauthenticated", "token is required")
+		return
+	}
+	if user, err := h.chat.ValidateToken(r.Context(), &pb.TokenRequest{Token: token}); err != nil || !user.GetValid() {
+		writeError(w, http.StatusUnauthorized, "unauthenticated", "invalid token")
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	cursor, err := parseSSECursor(lastEventID, time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	includeUpdates, _ := strconv.ParseBool(r.URL.Query().Get("include_updates"))
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	events := make(chan *pb.MessageResponse)
+	streamErr := make(chan error, 1)
+	go func() {
+		stream := &messageStream{ctx: ctx, send: func(message *pb.MessageResponse) error {
+			select {
+			case events <- message:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}}
+		streamErr <- h.chat.StreamMessages(&pb.StreamRequest{
+			RoomId:         roomID,
+			Token:          token,
+			IncludeUpdates: includeUpdates,
+		}, stream)
+	}()
+
+	controller := http.NewResponseController(w)
+	write := func(format string, args ...interface{}) bool {
+		controller.SetWriteDeadline(time.Now().Add(h.config.WriteWait))
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return false
+		}
+		return controller.Flush() == nil
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Stops nginx-style proxies from buffering the feed.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if !write("retry: %d\n\n", h.config.RetryDelay.Milliseconds()) {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-streamErr:
+			if err != nil && ctx.Err() == nil {
+				_, body := gatewayError("StreamMessages", err)
+				data, _ := json.Marshal(errorResponse{Error: body})
+				write("event: error\ndata: %s\n\n", data)
+			}
+			return
+		case <-heartbeat.C:
+			if !write(": heartbeat\n\n") {
+				return
+			}
+		case message := <-events:
+			if !cursor.accepts(message) {
+				continue
+			}
+			data, err := gatewayMarshal.Marshal(message)
+			if err != nil {
+				log.Printf("Error encoding SSE event: %v", err)
+				continue
+			}
+			// Only new messages move the resume point; updates to older
+			// messages are sent without an id.
+			if message.GetEvent() == "created" {
+				cursor.advance(message)
+				if !write("id: %s\ndata: %s\n\n", cursor.id(), data) {
+					return
+				}
+			} else if !write("data: %s\n\n", data) {
+				return
+			}
+		}
+	}
+}
+
+// sseCursor skips the room history that StreamMessages replays when it
+// starts: created events from before the cursor's second, and those from
+// that second up to and including the cursor's own message. Timestamps only
+// have whole seconds, so messages within a second are told apart by the
+// order of the replay, which is the order they were created in.
+type sseCursor struct {
+	unix      int64
+	messageID string
+	// passed is set once the cursor's message was replayed, or when there
+	// is none to wait for.
+	passed bool
+}
+
+func parseSSECursor(lastEventID string, now time.Time) (*sseCursor, error) {
+	if lastEventID == "" {
+		return &sseCursor{unix: now.Unix(), passed: true}, nil
+	}
+	unix, messageID, ok := strings.Cut(lastEventID, "-")
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if !ok || err != nil || messageID == "" {
+		return nil, fmt.Errorf("invalid Last-Event-ID %q", lastEventID)
+	}
+	return &sseCursor{unix: seconds, messageID: messageID}, nil
+}
+
+func (c *sseCursor) accepts(message *pb.MessageResponse) bool {
+	if message.GetEvent() != "created" {
+		return true
+	}
+	timestamp, err := time.Parse(time.RFC3339, message.GetTimestamp())
+	if err != nil {
+		return true
+	}
+	switch unix := timestamp.Unix(); {
+	case unix < c.unix:
+		return false
+	case unix > c.unix:
+		c.passed = true
+		return true
+	}
+	if c.passed {
+		return true
+	}
+	c.passed = message.GetMessageId() == c.messageID
+	return false
+}
+
+func (c *sseCursor) advance(message *pb.MessageResponse) {
+	if timestamp, err := time.Parse(time.RFC3339, message.GetTimestamp()); err == nil && timestamp.Unix() > c.unix {
+		c.unix = timestamp.Unix()
+	}
+	c.messageID = message.GetMessageId()
+	c.passed = true
+}
+
+func (c *sseCursor) id() string {
+	return fmt.Sprintf("%d-%s", c.unix, c.messageID)
+}
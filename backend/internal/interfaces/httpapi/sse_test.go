@@ -0,0 +1,153 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/interfaces/grpc/handlers"
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	config := DefaultSSEConfig()
+	config.HeartbeatInterval = 20 * time.Millisecond
+	mux := http.NewServeMux()
+	NewSSEHandler(handlers.NewChatHandler(mockMsgUC, mockAuthUC), config).Register(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	user := &entities.User{ID: "user123", Username: "mariem"}
+	mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "tok").Return(user, nil).AnyTimes()
+
+	now := time.Now()
+	history := []*entities.Message{
+		{ID: "old", RoomID: "general", Content: "old", Event: entities.MessageCreated, Timestamp: now.Add(-time.Hour)},
+		{ID: "seen", RoomID: "general", Content: "seen", Event: entities.MessageCreated, Timestamp: now.Add(-time.Minute)},
+		{ID: "missed", RoomID: "general", Content: "missed", Event: entities.MessageCreated, Timestamp: now.Add(-30 * time.Second)},
+	}
+	stream := func(extra ...*entities.Message) {
+		mockMsgUC.EXPECT().
+			StreamMessages(gomock.Any(), "general").
+			DoAndReturn(func(ctx context.Context, roomID string) (<-chan *entities.Message, error) {
+				messages := make(chan *entities.Message, len(history)+len(extra))
+				for _, message := range append(history, extra...) {
+					messages <- message
+				}
+				return messages, nil
+			})
+	}
+	open := func(t *testing.T, lastEventID string) (*bufio.Reader, func()) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/rooms/general/events", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer tok")
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		return bufio.NewReader(resp.Body), func() {
+			cancel()
+			resp.Body.Close()
+		}
+	}
+	// nextEvent returns the id and data lines of the next event, skipping
+	// the retry hint and heartbeats.
+	nextEvent := func(t *testing.T, reader *bufio.Reader) (string, string) {
+		var id, data string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && data != "":
+				return id, data
+			}
+		}
+	}
+
+	t.Run("new connections skip the replayed history", func(t *testing.T) {
+		stream(&entities.Message{ID: "new", RoomID: "general", Content: "new", Event: entities.MessageCreated, Timestamp: now.Add(time.Second)})
+		reader, closeFeed := open(t, "")
+		defer closeFeed()
+
+		id, data := nextEvent(t, reader)
+		assert.Contains(t, data, `"message_id":"new"`)
+		assert.True(t, strings.HasSuffix(id, "-new"))
+	})
+
+	t.Run("last event id resumes after that message", func(t *testing.T) {
+		stream()
+		reader, closeFeed := open(t, sseEventID(history[1]))
+		defer closeFeed()
+
+		_, data := nextEvent(t, reader)
+		assert.Contains(t, data, `"message_id":"missed"`)
+	})
+
+	t.Run("messages of the same second resume after the last one seen", func(t *testing.T) {
+		second := now.Add(-10 * time.Second).Truncate(time.Second)
+		burst := []*entities.Message{
+			{ID: "burst1", RoomID: "general", Content: "burst1", Event: entities.MessageCreated, Timestamp: second},
+			{ID: "burst2", RoomID: "general", Content: "burst2", Event: entities.MessageCreated, Timestamp: second.Add(100 * time.Millisecond)},
+			{ID: "burst3", RoomID: "general", Content: "burst3", Event: entities.MessageCreated, Timestamp: second.Add(200 * time.Millisecond)},
+		}
+		stream(burst...)
+		reader, closeFeed := open(t, sseEventID(burst[1]))
+		defer closeFeed()
+
+		id, data := nextEvent(t, reader)
+		assert.Contains(t, data, `"message_id":"burst3"`)
+		assert.Equal(t, sseEventID(burst[2]), id)
+	})
+
+	t.Run("heartbeats keep the feed open", func(t *testing.T) {
+		stream()
+		reader, closeFeed := open(t, "")
+		defer closeFeed()
+
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if line == ": heartbeat\n" {
+				break
+			}
+		}
+	})
+
+	t.Run("invalid last event id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/rooms/general/events?last_event_id=nope", nil)
+		req.Header.Set("Authorization", "Bearer tok")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func sseEventID(message *entities.Message) string {
+	cursor := &sseCursor{}
+	cursor.advance(&pb.MessageResponse{MessageId: message.ID, Timestamp: message.Timestamp.Format(time.RFC3339)})
+	return cursor.id()
+}
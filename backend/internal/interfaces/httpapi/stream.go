@@ -0,0 +1,26 @@
+package httpapi
+
+import (
+	"context"
+
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+
+	"google.golang.org/grpc"
+)
+
+// messageStream lets ChatHandler.StreamMessages write into another
+// transport, so WebSocket and SSE clients get exactly the events gRPC
+// clients get.
+type messageStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	send func(*pb.MessageResponse) error
+}
+
+func (s *messageStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *messageStream) Send(message *pb.MessageResponse) error {
+	return s.send(message)
+}
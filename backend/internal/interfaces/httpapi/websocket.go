@@ -0,0 +1,390 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "chat-app/backend/internal/interfaces/grpc/proto"
+
+	"github.com/gorilla/websocket"
+)
+
+type WebSocketConfig struct {
+	PingInterval time.Duration
+	// PongWait is how long the connection may stay silent, pongs included,
+	// before it is considered dead. It must be longer than PingInterval.
+	PongWait  time.Duration
+	WriteWait time.Duration
+	// SendBuffer is the number of frames queued per connection. Room
+	// messages, history replay included, wait for space in it; a client
+	// that falls this far behind on acks and errors is disconnected.
+	SendBuffer       int
+	MaxFrameSize     int64
+	MaxSubscriptions int
+}
+
+func DefaultWebSocketConfig() WebSocketConfig {
+	return WebSocketConfig{
+		PingInterval:     25 * time.Second,
+		PongWait:         60 * time.Second,
+		WriteWait:        10 * time.Second,
+		SendBuffer:       256,
+		MaxFrameSize:     64 * 1024,
+		MaxSubscriptions: 50,
+	}
+}
+
+// wsClientFrame is a frame sent by the client. Type is "subscribe",
+// "unsubscribe" or "send"; ID is echoed back in the matching ack or error.
+type wsClientFrame struct {
+	Type           string `json:"type"`
+	ID             string `json:"id"`
+	RoomID         string `json:"room_id"`
+	Content        string `json:"content"`
+	TTLSeconds     int32  `json:"ttl_seconds"`
+	IncludeUpdates bool   `json:"include_updates"`
+}
+
+// wsServerFrame is a frame sent by the server. Type is "message" for room
+// events, "ack" for completed requests or "error". Message holds a
+// MessageResponse in the same JSON form the REST gateway uses.
+type wsServerFrame struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	RoomID  string          `json:"room_id,omitempty"`
+	Message json.RawMessage `json:"message,omitempty"`
+	Error   *errorBody      `json:"error,omitempty"`
+}
+
+var errConnectionClosed = errors.New("connection closed")
+
+// WebSocketHandler serves GET /v1/ws. Clients authenticate with the same
+// session or bot tokens as the RPCs, passed as ?token= or a bearer header,
+// and multiplex any number of room subscriptions over the connection.
+type WebSocketHandler struct {
+	chat     pb.ChatServiceServer
+	config   WebSocketConfig
+	upgrader websocket.Upgrader
+}
+
+func NewWebSocketHandler(chat pb.ChatServiceServer, config WebSocketConfig) *WebSocketHandler {
+	return &WebSocketHandler{
+		chat:   chat,
+		config: config,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			// Requests carry a token rather than cookies, so any origin may
+			// connect, like the gRPC-Web wrapper allows.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func (h *WebSocketHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/ws", h.serve)
+}
+
+func (h *WebSocketHandler) serve(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = bearerToken(r)
+	}
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "unauthenticated", "token is required")
+		return
+	}
+	user, err := h.chat.ValidateToken(r.Context(), &pb.TokenRequest{Token: token})
+	if err != nil || !user.GetValid() {
+		writeError(w, http.StatusUnauthorized, "unauthenticated", "invalid token")
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	log.Printf("WebSocket connected for user %s", user.GetUserId())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &wsConnection{
+		chat:          h.chat,
+		config:        h.config,
+		conn:          conn,
+		token:         token,
+		ctx:           ctx,
+		cancel:        cancel,
+		out:           make(chan []byte, h.config.SendBuffer),
+		closeCode:     websocket.CloseNormalClosure,
+		subscriptions: make(map[string]*wsSubscription),
+	}
+	c.run()
+
+	log.Printf("WebSocket closed for user %s", user.GetUserId())
+}
+
+type wsConnection struct {
+	chat   pb.ChatServiceServer
+	config WebSocketConfig
+	conn   *websocket.Conn
+	token  string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	out    chan []byte
+
+	closeOnce   sync.Once
+	closeCode   int
+	closeReason string
+
+	mu            sync.Mutex
+	subscriptions map[string]*wsSubscription
+	streams       sync.WaitGroup
+}
+
+type wsSubscription struct {
+	cancel context.CancelFunc
+}
+
+func (c *wsConnection) run() {
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		c.writeLoop()
+	}()
+
+	c.readLoop()
+
+	c.close(websocket.CloseNormalClosure, "")
+	c.streams.Wait()
+	<-writerDone
+	c.conn.Close()
+}
+
+func (c *wsConnection) readLoop() {
+	c.conn.SetReadLimit(c.config.MaxFrameSize)
+	c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
+	})
+
+	for {
+		var frame wsClientFrame
+		if err := c.conn.ReadJSON(&frame); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				c.sendError("", "", errorBody{Code: "invalid_argument", Message: "frames must be JSON objects"})
+				continue
+			}
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("WebSocket read error: %v", err)
+			}
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
+
+		switch frame.Type {
+		case "subscribe":
+			c.subscribe(frame)
+		case "unsubscribe":
+			c.unsubscribe(frame)
+		case "send":
+			c.sendMessage(frame)
+		default:
+			c.sendError(frame.ID, frame.RoomID, errorBody{Code: "invalid_argument", Message: "unknown frame type " + frame.Type})
+		}
+
+		if c.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// writeLoop is the only writer on the connection. It sends queued frames
+// and pings, and writes the close frame once the connection shuts down.
+func (c *wsConnection) writeLoop() {
+	ticker := time.NewTicker(c.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			message := websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+			c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(c.config.WriteWait))
+			// Unblock the reader if the client never answers the close.
+			c.conn.SetReadDeadline(time.Now().Add(c.config.WriteWait))
+			return
+		case frame := <-c.out:
+			c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+				c.abort()
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.config.WriteWait)); err != nil {
+				c.abort()
+				return
+			}
+		}
+	}
+}
+
+// abort tears the connection down after a failed write, when a close frame
+// cannot be sent any more.
+func (c *wsConnection) abort() {
+	c.close(websocket.CloseAbnormalClosure, "")
+	c.conn.SetReadDeadline(time.Now())
+}
+
+func (c *wsConnection) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		c.cancel()
+	})
+}
+
+// enqueue never blocks: when the send buffer is full the client is too slow
+// and is disconnected with "try again later", so it can reconnect and
+// reload the history it missed.
+func (c *wsConnection) enqueue(frame wsServerFrame) error {
+	body, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+	if c.ctx.Err() != nil {
+		return errConnectionClosed
+	}
+
+	select {
+	case c.out <- body:
+		return nil
+	default:
+		log.Printf("WebSocket send buffer full, disconnecting slow client")
+		c.close(websocket.CloseTryAgainLater, "client is too slow")
+		return errConnectionClosed
+	}
+}
+
+// enqueueWait is enqueue for room messages: it waits for buffer space, so a
+// subscription replays the room's history as fast as the client reads it. A
+// client that stops reading is dropped by the write deadline instead.
+func (c *wsConnection) enqueueWait(ctx context.Context, frame wsServerFrame) error {
+	body, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case c.out <- body:
+		return nil
+	case <-ctx.Done():
+		return errConnectionClosed
+	}
+}
+
+func (c *wsConnection) sendError(id, roomID string, body errorBody) {
+	c.enqueue(wsServerFrame{Type: "error", ID: id, RoomID: roomID, Error: &body})
+}
+
+func (c *wsConnection) sendRPCError(id, roomID, rpc string, err error) {
+	_, body := gatewayError(rpc, err)
+	c.sendError(id, roomID, body)
+}
+
+func (c *wsConnection) subscribe(frame wsClientFrame) {
+	if frame.RoomID == "" {
+		c.sendError(frame.ID, "", errorBody{Code: "invalid_argument", Message: "room_id is required"})
+		return
+	}
+
+	c.mu.Lock()
+	if _, ok := c.subscriptions[frame.RoomID]; ok {
+		c.mu.Unlock()
+		c.enqueue(wsServerFrame{Type: "ack", ID: frame.ID, RoomID: frame.RoomID})
+		return
+	}
+	if len(c.subscriptions) >= c.config.MaxSubscriptions {
+		c.mu.Unlock()
+		c.sendError(frame.ID, frame.RoomID, errorBody{Code: "resource_exhausted", Message: "too many subscriptions"})
+		return
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	subscription := &wsSubscription{cancel: cancel}
+	c.subscriptions[frame.RoomID] = subscription
+	c.streams.Add(1)
+	c.mu.Unlock()
+
+	c.enqueue(wsServerFrame{Type: "ack", ID: frame.ID, RoomID: frame.RoomID})
+
+	go func() {
+		defer c.streams.Done()
+		defer c.removeSubscription(frame.RoomID, subscription)
+
+		stream := &messageStream{ctx: ctx, send: func(message *pb.MessageResponse) error {
+			body, err := gatewayMarshal.Marshal(message)
+			if err != nil {
+				return err
+			}
+			return c.enqueueWait(ctx, wsServerFrame{Type: "message", RoomID: frame.RoomID, Message: body})
+		}}
+		err := c.chat.StreamMessages(&pb.StreamRequest{
+			RoomId:         frame.RoomID,
+			Token:          c.token,
+			IncludeUpdates: frame.IncludeUpdates,
+		}, stream)
+		if err != nil && ctx.Err() == nil && !errors.Is(err, errConnectionClosed) {
+			c.sendRPCError(frame.ID, frame.RoomID, "StreamMessages", err)
+		}
+	}()
+}
+
+func (c *wsConnection) unsubscribe(frame wsClientFrame) {
+	c.mu.Lock()
+	subscription, ok := c.subscriptions[frame.RoomID]
+	delete(c.subscriptions, frame.RoomID)
+	c.mu.Unlock()
+
+	if ok {
+		subscription.cancel()
+	}
+	c.enqueue(wsServerFrame{Type: "ack", ID: frame.ID, RoomID: frame.RoomID})
+}
+
+func (c *wsConnection) removeSubscription(roomID string, subscription *wsSubscription) {
+	subscription.cancel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// A newer subscription to the same room may already have replaced this one.
+	if c.subscriptions[roomID] == subscription {
+		delete(c.subscriptions, roomID)
+	}
+}
+
+func (c *wsConnection) sendMessage(frame wsClientFrame) {
+	message, err := c.chat.SendMessage(c.ctx, &pb.MessageRequest{
+		RoomId:     frame.RoomID,
+		Content:    frame.Content,
+		TtlSeconds: frame.TTLSeconds,
+		Token:      c.token,
+	})
+	if err != nil {
+		c.sendRPCError(frame.ID, frame.RoomID, "SendMessage", err)
+		return
+	}
+
+	body, err := gatewayMarshal.Marshal(message)
+	if err != nil {
+		c.sendRPCError(frame.ID, frame.RoomID, "SendMessage", err)
+		return
+	}
+	c.enqueue(wsServerFrame{Type: "ack", ID: frame.ID, RoomID: frame.RoomID, Message: body})
+}
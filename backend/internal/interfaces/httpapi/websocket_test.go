@@ -0,0 +1,165 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/interfaces/grpc/handlers"
+	"chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgUC := mocks.NewMockMessageUseCase(ctrl)
+	mockAuthUC := mocks.NewMockAuthUseCase(ctrl)
+	config := DefaultWebSocketConfig()
+	config.PingInterval = 20 * time.Millisecond
+	config.SendBuffer = 8
+	mux := http.NewServeMux()
+	NewWebSocketHandler(handlers.NewChatHandler(mockMsgUC, mockAuthUC), config).Register(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/ws"
+	user := &entities.User{ID: "user123", Username: "mariem"}
+	mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "tok").Return(user, nil).AnyTimes()
+	mockAuthUC.EXPECT().ValidateToken(gomock.Any(), "bad").Return(nil, assert.AnError).AnyTimes()
+
+	t.Run("invalid token is rejected before the upgrade", func(t *testing.T) {
+		_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?token=bad", nil)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("subscribe, receive, send and unsubscribe", func(t *testing.T) {
+		messages := make(chan *entities.Message, 1)
+		streamCtx := make(chan context.Context, 1)
+		mockMsgUC.EXPECT().
+			StreamMessages(gomock.Any(), "general").
+			DoAndReturn(func(ctx context.Context, roomID string) (<-chan *entities.Message, error) {
+				streamCtx <- ctx
+				return messages, nil
+			})
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+				assert.Equal(t, "user123", params.UserID)
+				return &entities.Message{ID: "msg2", RoomID: params.RoomID, Content: params.Content, Timestamp: time.Now()}, nil
+			})
+
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Authorization": {"Bearer tok"}})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		read := func() wsServerFrame {
+			t.Helper()
+			var frame wsServerFrame
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			require.NoError(t, conn.ReadJSON(&frame))
+			return frame
+		}
+
+		require.NoError(t, conn.WriteJSON(wsClientFrame{Type: "subscribe", ID: "1", RoomID: "general"}))
+		assert.Equal(t, wsServerFrame{Type: "ack", ID: "1", RoomID: "general"}, read())
+
+		messages <- &entities.Message{ID: "msg1", RoomID: "general", Content: "hello", Event: entities.MessageCreated, Timestamp: time.Now()}
+		frame := read()
+		assert.Equal(t, "message", frame.Type)
+		assert.Equal(t, "general", frame.RoomID)
+		assert.Contains(t, string(frame.Message), `"message_id":"msg1"`)
+
+		require.NoError(t, conn.WriteJSON(wsClientFrame{Type: "send", ID: "2", RoomID: "general", Content: "hi"}))
+		frame = read()
+		assert.Equal(t, "ack", frame.Type)
+		assert.Equal(t, "2", frame.ID)
+		assert.Contains(t, string(frame.Message), `"message_id":"msg2"`)
+
+		require.NoError(t, conn.WriteJSON(wsClientFrame{Type: "unsubscribe", ID: "3", RoomID: "general"}))
+		assert.Equal(t, "ack", read().Type)
+		select {
+		case <-(<-streamCtx).Done():
+		case <-time.After(2 * time.Second):
+			t.Fatal("room stream was not cancelled")
+		}
+
+		require.NoError(t, conn.WriteJSON(wsClientFrame{Type: "shout"}))
+		frame = read()
+		assert.Equal(t, "error", frame.Type)
+		assert.Equal(t, "invalid_argument", frame.Error.Code)
+	})
+
+	t.Run("history longer than the send buffer is delivered", func(t *testing.T) {
+		const historySize = 100
+		mockMsgUC.EXPECT().
+			StreamMessages(gomock.Any(), "general").
+			DoAndReturn(func(ctx context.Context, roomID string) (<-chan *entities.Message, error) {
+				messages := make(chan *entities.Message, historySize)
+				for i := 0; i < historySize; i++ {
+					messages <- &entities.Message{ID: fmt.Sprintf("msg%d", i), RoomID: "general", Event: entities.MessageCreated, Timestamp: time.Now()}
+				}
+				return messages, nil
+			})
+
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?token=tok", nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		require.NoError(t, conn.WriteJSON(wsClientFrame{Type: "subscribe", ID: "1", RoomID: "general"}))
+		for i := -1; i < historySize; i++ {
+			var frame wsServerFrame
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			require.NoError(t, conn.ReadJSON(&frame))
+			if i < 0 {
+				assert.Equal(t, "ack", frame.Type)
+				continue
+			}
+			assert.Equal(t, "message", frame.Type)
+			assert.Contains(t, string(frame.Message), fmt.Sprintf(`"message_id":"msg%d"`, i))
+		}
+	})
+
+	t.Run("server pings idle connections", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?token=tok", nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		pinged := make(chan struct{}, 1)
+		conn.SetPingHandler(func(string) error {
+			select {
+			case pinged <- struct{}{}:
+			default:
+			}
+			return nil
+		})
+		go conn.ReadMessage()
+
+		select {
+		case <-pinged:
+		case <-time.After(2 * time.Second):
+			t.Fatal("no ping received")
+		}
+	})
+}
+
+func TestWebSocketConnection_SlowClient(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &wsConnection{ctx: ctx, cancel: cancel, out: make(chan []byte, 1)}
+
+	require.NoError(t, c.enqueue(wsServerFrame{Type: "message"}))
+	assert.ErrorIs(t, c.enqueue(wsServerFrame{Type: "message"}), errConnectionClosed)
+	assert.Error(t, ctx.Err())
+	assert.Equal(t, websocket.CloseTryAgainLater, c.closeCode)
+}
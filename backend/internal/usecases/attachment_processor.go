@@ -0,0 +1,280 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"log"
+	"sync"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type AttachmentProcessor interface {
+	Start(ctx context.Context)
+	Enqueue(messageID, attachmentID string)
+}
+
+type AttachmentProcessorConfig struct {
+	Workers         int
+	QueueSize       int
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	PollInterval    time.Duration
+	ThumbnailWidth  int
+	ThumbnailHeight int
+	// MaxImagePixels caps width times height of images that are decoded, so
+	// a small file claiming huge dimensions cannot exhaust memory.
+	MaxImagePixels int
+}
+
+func DefaultAttachmentProcessorConfig() AttachmentProcessorConfig {
+	return AttachmentProcessorConfig{
+		Workers:         4,
+		QueueSize:       100,
+		MaxAttempts:     3,
+		RetryBackoff:    time.Second,
+		PollInterval:    time.Minute,
+		ThumbnailWidth:  256,
+		ThumbnailHeight: 256,
+		MaxImagePixels:  40_000_000,
+	}
+}
+
+type attachmentJob struct {
+	messageID    string
+	attachmentID string
+}
+
+type attachmentProcessor struct {
+	messageRepo repositories.MessageRepository
+	blobRepo    repositories.BlobRepository
+	config      AttachmentProcessorConfig
+	jobs        chan attachmentJob
+
+	mu       sync.Mutex
+	inFlight map[attachmentJob]bool
+}
+
+func NewAttachmentProcessor(messageRepo repositories.MessageRepository, blobRepo repositories.BlobRepository, config AttachmentProcessorConfig) AttachmentProcessor {
+	return &attachmentProcessor{
+		messageRepo: messageRepo,
+		blobRepo:    blobRepo,
+		config:      config,
+		jobs:        make(chan attachmentJob, config.QueueSize),
+		inFlight:    make(map[attachmentJob]bool),
+	}
+}
+
+func (p *attachmentProcessor) Start(ctx context.Context) {
+	for i := 0; i < p.config.Workers; i++ {
+		go p.worker(ctx)
+	}
+	go p.poll(ctx)
+}
+
+// Enqueue never blocks; a job dropped because the queue is full is picked up
+// again by the next poll since its attachment is still pending.
+func (p *attachmentProcessor) Enqueue(messageID, attachmentID string) {
+	job := attachmentJob{messageID: messageID, attachmentID: attachmentID}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inFlight[job] {
+		return
+	}
+
+	select {
+	case p.jobs <- job:
+		p.inFlight[job] = true
+	default:
+		log.Printf("Attachment queue full, deferring %s", attachmentID)
+	}
+}
+
+func (p *attachmentProcessor) poll(ctx context.Context) {
+	ticker := time.NewTicker(p.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		p.enqueuePending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *attachmentProcessor) enqueuePending(ctx context.Context) {
+	messages, err := p.messageRepo.ListWithPendingAttachments(ctx, p.config.QueueSize)
+	if err != nil {
+		log.Printf("Error listing pending attachments: %v", err)
+		return
+	}
+
+	for _, message := range messages {
+		for _, attachment := range message.Attachments {
+			if attachment.Status == entities.AttachmentPending {
+				p.Enqueue(message.ID, attachment.ID)
+			}
+		}
+	}
+}
+
+func (p *attachmentProcessor) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-p.jobs:
+			p.handle(ctx, job)
+
+			p.mu.Lock()
+			delete(p.inFlight, job)
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *attachmentProcessor) handle(ctx context.Context, job attachmentJob) {
+	for {
+		attachment, err := p.process(ctx, job)
+		if err == nil || attachment == nil {
+			if err != nil {
+				log.Printf("Error processing attachment %s: %v", job.attachmentID, err)
+			}
+			return
+		}
+
+		attachment.Attempts++
+		attachment.Error = err.Error()
+		if attachment.Attempts >= p.config.MaxAttempts {
+			attachment.Status = entities.AttachmentFailed
+		}
+		if updateErr := p.messageRepo.UpdateAttachment(ctx, job.messageID, attachment); updateErr != nil {
+			log.Printf("Error recording attachment failure %s: %v", job.attachmentID, updateErr)
+			return
+		}
+		if attachment.Status == entities.AttachmentFailed {
+			log.Printf("Giving up on attachment %s after %d attempts: %v", job.attachmentID, attachment.Attempts, err)
+			return
+		}
+
+		backoff := p.config.RetryBackoff << (attachment.Attempts - 1)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// process returns the attachment it worked on alongside any error so the
+// caller can record the failed attempt; a nil attachment means there is
+// nothing to retry.
+func (p *attachmentProcessor) process(ctx context.Context, job attachmentJob) (*entities.Attachment, error) {
+	message, err := p.messageRepo.GetByID(ctx, job.messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachment *entities.Attachment
+	for _, a := range message.Attachments {
+		if a.ID == job.attachmentID {
+			attachment = a
+			break
+		}
+	}
+	if attachment == nil {
+		return nil, fmt.Errorf("attachment %s not found on message %s", job.attachmentID, job.messageID)
+	}
+	if attachment.Status != entities.AttachmentPending {
+		return nil, nil
+	}
+	if !attachment.IsImage() {
+		attachment.Status = entities.AttachmentProcessed
+		return nil, p.messageRepo.UpdateAttachment(ctx, job.messageID, attachment)
+	}
+
+	blob, err := p.blobRepo.Get(ctx, attachment.ID)
+	if err != nil {
+		return attachment, fmt.Errorf("failed to load attachment data: %v", err)
+	}
+
+	var stripped []byte
+	changed := false
+	switch attachment.ContentType {
+	case "image/jpeg":
+		stripped, changed = stripJPEGExif(blob.Data)
+	case "image/png":
+		stripped, changed = stripPNGMetadata(blob.Data)
+	}
+	if changed {
+		blob.Data = stripped
+		if err := p.blobRepo.Put(ctx, blob); err != nil {
+			return attachment, fmt.Errorf("failed to store stripped image: %v", err)
+		}
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(blob.Data))
+	if err != nil {
+		return attachment, fmt.Errorf("failed to decode image: %v", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > p.config.MaxImagePixels/config.Height {
+		// Retrying cannot help, so the attachment fails right away.
+		attachment.Status = entities.AttachmentFailed
+		attachment.Error = fmt.Sprintf("image dimensions %dx%d exceed the limit of %d pixels", config.Width, config.Height, p.config.MaxImagePixels)
+		if err := p.messageRepo.UpdateAttachment(ctx, job.messageID, attachment); err != nil {
+			return nil, err
+		}
+		return nil, errors.New(attachment.Error)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(blob.Data))
+	if err != nil {
+		return attachment, fmt.Errorf("failed to decode image: %v", err)
+	}
+
+	thumbnail := thumbnailImage(img, p.config.ThumbnailWidth, p.config.ThumbnailHeight)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 80}); err != nil {
+		return attachment, fmt.Errorf("failed to encode thumbnail: %v", err)
+	}
+
+	thumbnailID := attachment.ID + "_thumb"
+	if err := p.blobRepo.Put(ctx, &entities.Blob{
+		ID:          thumbnailID,
+		ContentType: "image/jpeg",
+		Data:        buf.Bytes(),
+	}); err != nil {
+		return attachment, fmt.Errorf("failed to store thumbnail: %v", err)
+	}
+
+	bounds := img.Bounds()
+	attachment.Width = bounds.Dx()
+	attachment.Height = bounds.Dy()
+	attachment.Size = int64(len(blob.Data))
+	attachment.ThumbnailID = thumbnailID
+	attachment.ThumbnailWidth = p.config.ThumbnailWidth
+	attachment.ThumbnailHeight = p.config.ThumbnailHeight
+	attachment.Status = entities.AttachmentProcessed
+	attachment.Error = ""
+
+	if err := p.messageRepo.UpdateAttachment(ctx, job.messageID, attachment); err != nil {
+		attachment.Status = entities.AttachmentPending
+		return attachment, err
+	}
+
+	log.Printf("Processed attachment %s (%dx%d)", attachment.ID, attachment.Width, attachment.Height)
+	return attachment, nil
+}
@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	repoMocks "chat-app/backend/internal/domain/repositories/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestAttachmentProcessor_Process(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgRepo := repoMocks.NewMockMessageRepository(ctrl)
+	mockBlobRepo := repoMocks.NewMockBlobRepository(ctrl)
+
+	config := DefaultAttachmentProcessorConfig()
+	config.ThumbnailWidth = 32
+	config.ThumbnailHeight = 32
+	processor := NewAttachmentProcessor(mockMsgRepo, mockBlobRepo, config).(*attachmentProcessor)
+
+	ctx := context.Background()
+	job := attachmentJob{messageID: "msg123", attachmentID: "att123"}
+
+	t.Run("generates thumbnail and records dimensions", func(t *testing.T) {
+		message := &entities.Message{
+			ID: "msg123",
+			Attachments: []*entities.Attachment{
+				{ID: "att123", ContentType: "image/png", Status: entities.AttachmentPending},
+			},
+		}
+
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(message, nil)
+		mockBlobRepo.EXPECT().
+			Get(ctx, "att123").
+			Return(&entities.Blob{ID: "att123", ContentType: "image/png", Data: encodeTestPNG(t, 120, 80)}, nil)
+		mockBlobRepo.EXPECT().
+			Put(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, blob *entities.Blob) error {
+				assert.Equal(t, "att123_thumb", blob.ID)
+				thumb, err := jpeg.Decode(bytes.NewReader(blob.Data))
+				require.NoError(t, err)
+				assert.Equal(t, 32, thumb.Bounds().Dx())
+				assert.Equal(t, 32, thumb.Bounds().Dy())
+				return nil
+			})
+		mockMsgRepo.EXPECT().
+			UpdateAttachment(ctx, "msg123", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, messageID string, attachment *entities.Attachment) error {
+				assert.Equal(t, entities.AttachmentProcessed, attachment.Status)
+				assert.Equal(t, 120, attachment.Width)
+				assert.Equal(t, 80, attachment.Height)
+				assert.Equal(t, "att123_thumb", attachment.ThumbnailID)
+				return nil
+			})
+
+		attachment, err := processor.process(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, entities.AttachmentProcessed, attachment.Status)
+	})
+
+	t.Run("skips attachments that are already processed", func(t *testing.T) {
+		message := &entities.Message{
+			ID: "msg123",
+			Attachments: []*entities.Attachment{
+				{ID: "att123", ContentType: "image/png", Status: entities.AttachmentProcessed},
+			},
+		}
+
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(message, nil)
+
+		attachment, err := processor.process(ctx, job)
+		require.NoError(t, err)
+		assert.Nil(t, attachment)
+	})
+
+	t.Run("refuses images too large to decode", func(t *testing.T) {
+		processor.config.MaxImagePixels = 1000
+		defer func() { processor.config.MaxImagePixels = config.MaxImagePixels }()
+
+		message := &entities.Message{
+			ID: "msg123",
+			Attachments: []*entities.Attachment{
+				{ID: "att123", ContentType: "image/png", Status: entities.AttachmentPending},
+			},
+		}
+
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(message, nil)
+		mockBlobRepo.EXPECT().
+			Get(ctx, "att123").
+			Return(&entities.Blob{ID: "att123", ContentType: "image/png", Data: encodeTestPNG(t, 120, 80)}, nil)
+		mockMsgRepo.EXPECT().
+			UpdateAttachment(ctx, "msg123", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, messageID string, attachment *entities.Attachment) error {
+				assert.Equal(t, entities.AttachmentFailed, attachment.Status)
+				assert.Contains(t, attachment.Error, "120x80")
+				return nil
+			})
+
+		attachment, err := processor.process(ctx, job)
+		require.Error(t, err)
+		assert.Nil(t, attachment)
+	})
+
+	t.Run("marks attachment failed after max attempts", func(t *testing.T) {
+		processor.config.MaxAttempts = 2
+		processor.config.RetryBackoff = time.Millisecond
+
+		stored := &entities.Attachment{ID: "att123", ContentType: "image/png", Status: entities.AttachmentPending}
+
+		mockMsgRepo.EXPECT().
+			GetByID(ctx, "msg123").
+			DoAndReturn(func(ctx context.Context, messageID string) (*entities.Message, error) {
+				attachment := *stored
+				return &entities.Message{ID: "msg123", Attachments: []*entities.Attachment{&attachment}}, nil
+			}).
+			Times(2)
+		mockBlobRepo.EXPECT().
+			Get(ctx, "att123").
+			Return(&entities.Blob{ID: "att123", Data: []byte("not an image")}, nil).
+			Times(2)
+
+		var statuses []entities.AttachmentStatus
+		mockMsgRepo.EXPECT().
+			UpdateAttachment(ctx, "msg123", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, messageID string, attachment *entities.Attachment) error {
+				statuses = append(statuses, attachment.Status)
+				*stored = *attachment
+				return nil
+			}).
+			Times(2)
+
+		processor.handle(ctx, job)
+		assert.Equal(t, []entities.AttachmentStatus{entities.AttachmentPending, entities.AttachmentFailed}, statuses)
+		assert.Equal(t, 2, stored.Attempts)
+		assert.NotEmpty(t, stored.Error)
+	})
+}
+
+func TestStripJPEGExif(t *testing.T) {
+	// Minimal little-endian TIFF: IFD0 with a single GPSInfo pointer, followed
+	// by a GPS IFD holding one out-of-line RATIONAL latitude value.
+	tiff := make([]byte, 64)
+	copy(tiff, "II")
+	binary.LittleEndian.PutUint16(tiff[2:], 42)
+	binary.LittleEndian.PutUint32(tiff[4:], 8)
+	binary.LittleEndian.PutUint16(tiff[8:], 1)
+	binary.LittleEndian.PutUint16(tiff[10:], gpsInfoTag)
+	binary.LittleEndian.PutUint16(tiff[12:], 4)
+	binary.LittleEndian.PutUint32(tiff[14:], 1)
+	binary.LittleEndian.PutUint32(tiff[18:], 26)
+	binary.LittleEndian.PutUint16(tiff[26:], 1)
+	binary.LittleEndian.PutUint16(tiff[28:], 2)
+	binary.LittleEndian.PutUint16(tiff[30:], 5)
+	binary.LittleEndian.PutUint32(tiff[32:], 1)
+	binary.LittleEndian.PutUint32(tiff[36:], 44)
+	binary.LittleEndian.PutUint32(tiff[44:], 4841)
+	binary.LittleEndian.PutUint32(tiff[48:], 100)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	var jpg bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	require.NoError(t, jpeg.Encode(&jpg, img, nil))
+	encoded := jpg.Bytes()
+	data := append(append(append([]byte{}, encoded[:2]...), segment...), encoded[2:]...)
+
+	stripped, changed := stripJPEGExif(data)
+	require.True(t, changed)
+	assert.Equal(t, len(data), len(stripped))
+
+	exif := stripped[2+4+6:]
+	assert.Equal(t, uint16(0), binary.LittleEndian.Uint16(exif[26:]))
+	assert.Equal(t, uint32(0), binary.LittleEndian.Uint32(exif[44:]))
+
+	_, err := jpeg.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+
+	t.Run("leaves images without exif untouched", func(t *testing.T) {
+		out, changed := stripJPEGExif(encoded)
+		assert.False(t, changed)
+		assert.Equal(t, encoded, out)
+	})
+}
+
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	encoded := encodeTestPNG(t, 4, 4)
+	// The signature and the IHDR chunk come first.
+	header := 8 + 12 + 13
+
+	var data []byte
+	data = append(data, encoded[:header]...)
+	data = append(data, pngChunk("eXIf", []byte("MM\x00\x2a\x00\x00\x00\x08"))...)
+	data = append(data, pngChunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta/>"))...)
+	data = append(data, pngChunk("tEXt", []byte("Comment\x00hello"))...)
+	data = append(data, encoded[header:]...)
+
+	stripped, changed := stripPNGMetadata(data)
+	require.True(t, changed)
+	assert.NotContains(t, string(stripped), "eXIf")
+	assert.NotContains(t, string(stripped), "xmpmeta")
+	assert.Contains(t, string(stripped), "hello")
+
+	_, err := png.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+
+	t.Run("leaves images without metadata untouched", func(t *testing.T) {
+		out, changed := stripPNGMetadata(encoded)
+		assert.False(t, changed)
+		assert.Equal(t, encoded, out)
+	})
+}
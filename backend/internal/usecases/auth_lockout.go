@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"context"
+	"log"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+func (uc *authUseCase) UnlockAccount(ctx context.Context, admin *entities.User, username string) error {
+	if uc.guard == nil {
+		return failedPrecondition("LOGIN_GUARD_DISABLED", "accounts are never locked on this server")
+	}
+	return uc.guard.Unlock(ctx, admin, username)
+}
+
+// loginFailed counts a wrong username or password and returns the error
+// for it.
+func (uc *authUseCase) loginFailed(ctx context.Context, username string, session entities.SessionMetadata) error {
+	if uc.guard != nil {
+		if err := uc.guard.LoginFailed(ctx, username, session.IPAddress); err != nil {
+			return err
+		}
+	}
+	return unauthenticated("INVALID_CREDENTIALS", "invalid credentials")
+}
+
+// loginSucceeded forgets the failed logins of user.
+func (uc *authUseCase) loginSucceeded(ctx context.Context, user *entities.User) {
+	if uc.guard == nil {
+		return
+	}
+	if err := uc.guard.LoginSucceeded(ctx, user.Username); err != nil {
+		log.Printf("Error resetting failed logins of user %s: %v", user.ID, err)
+	}
+}
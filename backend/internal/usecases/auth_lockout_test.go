@@ -0,0 +1,149 @@
+package usecases_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthUseCase_LoginGuard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockGuard := ucMocks.NewMockLoginGuard(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithLoginGuard(mockGuard))
+
+	ctx := context.Background()
+	session := entities.SessionMetadata{IPAddress: "10.0.0.1"}
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	user := &entities.User{ID: "user123", Username: "mariem", PasswordHash: string(hashedPassword)}
+
+	t.Run("throttled logins do not check the password", func(t *testing.T) {
+		throttled := &usecases.Error{Kind: usecases.KindRateLimited, Reason: "ACCOUNT_LOCKED", RetryAfter: time.Minute}
+		mockGuard.EXPECT().CheckLogin(ctx, "mariem", "10.0.0.1").Return(throttled)
+
+		_, err := authUC.Login(ctx, "mariem", "secret", session)
+		assert.ErrorIs(t, err, usecases.ErrRateLimited)
+	})
+
+	t.Run("wrong password is counted", func(t *testing.T) {
+		mockGuard.EXPECT().CheckLogin(ctx, "mariem", "10.0.0.1").Return(nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockGuard.EXPECT().LoginFailed(ctx, "mariem", "10.0.0.1").Return(nil)
+
+		_, err := authUC.Login(ctx, "mariem", "wrong", session)
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("unknown username is counted", func(t *testing.T) {
+		mockGuard.EXPECT().CheckLogin(ctx, "nobody", "10.0.0.1").Return(nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "nobody").Return(nil, fmt.Errorf("user %w", repositories.ErrNotFound))
+		mockGuard.EXPECT().LoginFailed(ctx, "nobody", "10.0.0.1").Return(nil)
+
+		_, err := authUC.Login(ctx, "nobody", "secret", session)
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("success clears the failures", func(t *testing.T) {
+		mockGuard.EXPECT().CheckLogin(ctx, "mariem", "10.0.0.1").Return(nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockGuard.EXPECT().LoginSucceeded(ctx, "mariem").Return(nil)
+		mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+
+		token, err := authUC.Login(ctx, "mariem", "secret", session)
+		require.NoError(t, err)
+		assert.Equal(t, "user123", token.UserID)
+	})
+
+	t.Run("registration limit", func(t *testing.T) {
+		mockGuard.EXPECT().CheckRegistration(ctx, "10.0.0.1").Return(&usecases.Error{Kind: usecases.KindRateLimited, Reason: "TOO_MANY_REGISTRATIONS"})
+
+		_, err := authUC.Register(ctx, "newuser", "correct-horse-battery", session)
+		assert.ErrorIs(t, err, usecases.ErrRateLimited)
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		admin := &entities.User{ID: "admin1", Username: "admin"}
+		mockGuard.EXPECT().Unlock(ctx, admin, "mariem").Return(nil)
+
+		assert.NoError(t, authUC.UnlockAccount(ctx, admin, "mariem"))
+	})
+}
+
+func TestAuthUseCase_LoginGuardTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockChallengeRepo := mocks.NewMockLoginChallengeRepository(ctrl)
+	mockGuard := ucMocks.NewMockLoginGuard(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithLoginGuard(mockGuard), usecases.WithTwoFactor(mockChallengeRepo))
+
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	user := &entities.User{
+		ID:           "user123",
+		Username:     "mariem",
+		PasswordHash: string(hashedPassword),
+		TwoFactor:    &entities.TwoFactor{Secret: "JBSWY3DPEHPK3PXP", Enabled: true},
+	}
+	challenge := &entities.LoginChallenge{UserID: "user123", IPAddress: "10.0.0.1", ExpiresAt: time.Now().Add(time.Minute)}
+
+	t.Run("the password alone does not clear the failures", func(t *testing.T) {
+		mockGuard.EXPECT().CheckLogin(ctx, "mariem", "10.0.0.1").Return(nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockChallengeRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		token, err := authUC.Login(ctx, "mariem", "secret", entities.SessionMetadata{IPAddress: "10.0.0.1"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.Challenge)
+	})
+
+	t.Run("wrong codes are counted", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockGuard.EXPECT().CheckLogin(ctx, "mariem", "10.0.0.1").Return(nil)
+		mockGuard.EXPECT().LoginFailed(ctx, "mariem", "10.0.0.1").Return(nil)
+		mockChallengeRepo.EXPECT().RecordAttempt(ctx, gomock.Any()).Return(1, nil)
+
+		_, err := authUC.CompleteLogin(ctx, "chal", "000000")
+		assert.ErrorIs(t, err, usecases.ErrInvalidArgument)
+	})
+
+	t.Run("locked accounts cannot complete a login", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockGuard.EXPECT().CheckLogin(ctx, "mariem", "10.0.0.1").
+			Return(&usecases.Error{Kind: usecases.KindRateLimited, Reason: "ACCOUNT_LOCKED", RetryAfter: time.Minute})
+
+		_, err := authUC.CompleteLogin(ctx, "chal", "ABCDE-FGHIJ")
+		assert.ErrorIs(t, err, usecases.ErrRateLimited)
+	})
+
+	t.Run("a valid code clears the failures", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockGuard.EXPECT().CheckLogin(ctx, "mariem", "10.0.0.1").Return(nil)
+		mockUserRepo.EXPECT().UseRecoveryCode(ctx, "user123", gomock.Any()).Return(nil)
+		mockChallengeRepo.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
+		mockGuard.EXPECT().LoginSucceeded(ctx, "mariem").Return(nil)
+		mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+
+		token, err := authUC.CompleteLogin(ctx, "chal", "ABCDE-FGHIJ")
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.Token)
+	})
+}
@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+func (uc *authUseCase) ChangePassword(ctx context.Context, userID, currentToken, currentPassword, newPassword string) (int, error) {
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if user.IsBot {
+		return 0, failedPrecondition("BOT_PASSWORD_LOGIN", "bot accounts authenticate with API keys")
+	}
+	if user.SSOSubject != "" {
+		return 0, errSSOPassword
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return 0, invalidArgument("current_password", "WRONG_PASSWORD", "current password is incorrect")
+	}
+	if err := uc.checkPassword(ctx, "new_password", user.Username, newPassword); err != nil {
+		return 0, err
+	}
+
+	if err := uc.setPassword(ctx, userID, newPassword); err != nil {
+		return 0, err
+	}
+	return uc.RevokeOtherSessions(ctx, userID, currentToken)
+}
+
+func (uc *authUseCase) SetEmail(ctx context.Context, userID, email string) error {
+	if email != "" {
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Address != email {
+			return invalidArgument("email", "INVALID_EMAIL", "invalid email address")
+		}
+	}
+	return uc.userRepo.UpdateEmail(ctx, userID, email)
+}
+
+func (uc *authUseCase) RequestPasswordReset(ctx context.Context, username string, session entities.SessionMetadata) error {
+	if uc.resetRepo == nil {
+		return failedPrecondition("PASSWORD_RESET_DISABLED", "password reset is not available")
+	}
+	if uc.guard != nil {
+		if err := uc.guard.CheckPasswordReset(ctx, username, session.IPAddress); err != nil {
+			return err
+		}
+	}
+
+	user, err := uc.userRepo.GetUserByUsername(ctx, username)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.IsBot || user.Email == "" {
+		log.Printf("Password reset requested for user %s, who has no email", user.ID)
+		return nil
+	}
+	if user.SSOSubject != "" {
+		log.Printf("Password reset requested for user %s, who signs in with single sign-on", user.ID)
+		return nil
+	}
+
+	secret := generateToken()
+	now := time.Now()
+	token := &entities.PasswordResetToken{
+		TokenHash: hashSecret(secret),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.config.PasswordResetTTL),
+	}
+	if err := uc.resetRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	if err := uc.notifier.SendPasswordReset(ctx, user, secret, token.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to send password reset: %v", err)
+	}
+	return nil
+}
+
+func (uc *authUseCase) ResetPassword(ctx context.Context, resetToken, newPassword string) (int, error) {
+	if uc.resetRepo == nil {
+		return 0, failedPrecondition("PASSWORD_RESET_DISABLED", "password reset is not available")
+	}
+	errInvalidToken := invalidArgument("reset_token", "INVALID_RESET_TOKEN", "invalid or expired reset token")
+
+	// The token is only used once the new password is accepted, so a
+	// rejected password leaves the user their token.
+	tokenHash := hashSecret(resetToken)
+	now := time.Now()
+	token, err := uc.resetRepo.GetByHash(ctx, tokenHash)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return 0, errInvalidToken
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !token.UsedAt.IsZero() || now.After(token.ExpiresAt) {
+		return 0, errInvalidToken
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if user.SSOSubject != "" {
+		return 0, errSSOPassword
+	}
+	if err := uc.checkPassword(ctx, "new_password", user.Username, newPassword); err != nil {
+		return 0, err
+	}
+
+	if _, err := uc.resetRepo.Consume(ctx, tokenHash, now); errors.Is(err, repositories.ErrNotFound) || errors.Is(err, repositories.ErrConflict) {
+		return 0, errInvalidToken
+	} else if err != nil {
+		return 0, err
+	}
+
+	if err := uc.setPassword(ctx, token.UserID, newPassword); err != nil {
+		return 0, err
+	}
+	if err := uc.resetRepo.DeleteForUser(ctx, token.UserID); err != nil {
+		log.Printf("Error deleting password reset tokens of user %s: %v", token.UserID, err)
+	}
+
+	log.Printf("Password of user %s was reset", token.UserID)
+	return uc.RevokeOtherSessions(ctx, token.UserID, "")
+}
+
+func (uc *authUseCase) setPassword(ctx context.Context, userID, password string) error {
+	hashedPassword, err := uc.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return uc.userRepo.UpdatePassword(ctx, userID, hashedPassword)
+}
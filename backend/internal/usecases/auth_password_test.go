@@ -0,0 +1,186 @@
+package usecases_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthUseCase_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo)
+
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old_secret"), bcrypt.MinCost)
+	user := &entities.User{ID: "user123", PasswordHash: string(hashedPassword)}
+	now := time.Now()
+
+	t.Run("changes the password and ends other sessions", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockUserRepo.EXPECT().
+			UpdatePassword(ctx, "user123", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, userID, passwordHash string) error {
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte("new_secret")))
+				return nil
+			})
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return([]*entities.AuthToken{
+			{Token: "current", ID: "s1", ExpiresAt: now.Add(time.Hour)},
+			{Token: "other", ID: "s2", ExpiresAt: now.Add(time.Hour)},
+		}, nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "other").Return(nil)
+
+		revoked, err := authUC.ChangePassword(ctx, "user123", "current", "old_secret", "new_secret")
+		require.NoError(t, err)
+		assert.Equal(t, 1, revoked)
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+
+		_, err := authUC.ChangePassword(ctx, "user123", "current", "wrong", "new_secret")
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "current_password", domainErr.Field)
+	})
+
+	t.Run("new password too short", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+
+		_, err := authUC.ChangePassword(ctx, "user123", "current", "old_secret", "abc")
+		assert.ErrorIs(t, err, usecases.ErrInvalidArgument)
+	})
+}
+
+func TestAuthUseCase_PasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockResetRepo := mocks.NewMockPasswordResetRepository(ctrl)
+	mockNotifier := ucMocks.NewMockNotifier(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithPasswordReset(mockResetRepo, mockNotifier))
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem", Email: "mariem@example.com"}
+
+	t.Run("request sends a token", func(t *testing.T) {
+		var stored *entities.PasswordResetToken
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockResetRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *entities.PasswordResetToken) error {
+				stored = token
+				return nil
+			})
+		mockNotifier.EXPECT().
+			SendPasswordReset(ctx, user, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entities.User, token string, expiresAt time.Time) error {
+				assert.NotEqual(t, token, stored.TokenHash)
+				assert.Equal(t, stored.ExpiresAt, expiresAt)
+				return nil
+			})
+
+		require.NoError(t, authUC.RequestPasswordReset(ctx, "mariem", entities.SessionMetadata{}))
+	})
+
+	t.Run("request for unknown user succeeds quietly", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "nobody").Return(nil, fmt.Errorf("user %w", repositories.ErrNotFound))
+
+		require.NoError(t, authUC.RequestPasswordReset(ctx, "nobody", entities.SessionMetadata{}))
+	})
+
+	token := &entities.PasswordResetToken{UserID: "user123", ExpiresAt: time.Now().Add(time.Minute)}
+
+	t.Run("reset sets the password and ends every session", func(t *testing.T) {
+		gomock.InOrder(
+			mockResetRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(token, nil),
+			mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil),
+			mockResetRepo.EXPECT().Consume(ctx, gomock.Any(), gomock.Any()).Return(token, nil),
+		)
+		mockUserRepo.EXPECT().UpdatePassword(ctx, "user123", gomock.Any()).Return(nil)
+		mockResetRepo.EXPECT().DeleteForUser(ctx, "user123").Return(nil)
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return([]*entities.AuthToken{
+			{Token: "tok1", ID: "s1", ExpiresAt: time.Now().Add(time.Hour)},
+		}, nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "tok1").Return(nil)
+
+		revoked, err := authUC.ResetPassword(ctx, "reset", "new_secret")
+		require.NoError(t, err)
+		assert.Equal(t, 1, revoked)
+	})
+
+	t.Run("rejected password keeps the token", func(t *testing.T) {
+		mockResetRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(token, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+
+		_, err := authUC.ResetPassword(ctx, "reset", "mariem_new_secret")
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "PASSWORD_CONTAINS_USERNAME", domainErr.Reason)
+	})
+
+	t.Run("used token", func(t *testing.T) {
+		mockResetRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(&entities.PasswordResetToken{
+			UserID:    "user123",
+			ExpiresAt: time.Now().Add(time.Minute),
+			UsedAt:    time.Now(),
+		}, nil)
+
+		_, err := authUC.ResetPassword(ctx, "reset", "new_secret")
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "INVALID_RESET_TOKEN", domainErr.Reason)
+	})
+
+	t.Run("token used meanwhile", func(t *testing.T) {
+		mockResetRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(token, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockResetRepo.EXPECT().Consume(ctx, gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("%w: password reset token was already used", repositories.ErrConflict))
+
+		_, err := authUC.ResetPassword(ctx, "reset", "new_secret")
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "INVALID_RESET_TOKEN", domainErr.Reason)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		mockResetRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(&entities.PasswordResetToken{
+			UserID:    "user123",
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		_, err := authUC.ResetPassword(ctx, "reset", "new_secret")
+		assert.ErrorIs(t, err, usecases.ErrInvalidArgument)
+	})
+}
+
+func TestAuthUseCase_SetEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo)
+	ctx := context.Background()
+
+	mockUserRepo.EXPECT().UpdateEmail(ctx, "user123", "mariem@example.com").Return(nil)
+	require.NoError(t, authUC.SetEmail(ctx, "user123", "mariem@example.com"))
+
+	err := authUC.SetEmail(ctx, "user123", "Mariem <mariem@example.com>")
+	assert.ErrorIs(t, err, usecases.ErrInvalidArgument)
+}
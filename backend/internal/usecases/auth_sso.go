@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+// errSSOPassword refuses password logins and changes for users who sign in
+// with single sign-on, whose password is up to their identity provider.
+var errSSOPassword = failedPrecondition("SSO_PASSWORD_LOGIN", "this account signs in with single sign-on")
+
+func (uc *authUseCase) LoginExternal(ctx context.Context, identity entities.ExternalIdentity, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	user, err := uc.userRepo.GetUserBySSOIdentity(ctx, identity.Issuer, identity.Subject)
+	if errors.Is(err, repositories.ErrNotFound) {
+		user, err = uc.provisionUser(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBot {
+		return nil, failedPrecondition("BOT_PASSWORD_LOGIN", "bot accounts authenticate with API keys")
+	}
+
+	// The identity provider has already asked for whatever second factor
+	// it requires.
+	return uc.generateToken(ctx, user, session)
+}
+
+// provisionUser creates the account of a user who signs in with their
+// identity provider for the first time.
+func (uc *authUseCase) provisionUser(ctx context.Context, identity entities.ExternalIdentity) (*entities.User, error) {
+	username, err := uc.config.UsernamePolicy.normalize("username", identity.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &entities.User{
+		ID:         generateID(),
+		Username:   username,
+		Email:      identity.Email,
+		CreatedAt:  time.Now(),
+		SSOIssuer:  identity.Issuer,
+		SSOSubject: identity.Subject,
+	}
+	err = uc.userRepo.CreateUser(ctx, user)
+	if errors.Is(err, repositories.ErrAlreadyExists) {
+		return nil, alreadyExists("USERNAME_TAKEN", "username %q is taken by another account", username)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Created user %s for %s at %s", user.ID, identity.Subject, identity.Issuer)
+	return user, nil
+}
@@ -0,0 +1,231 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func TestAuthUseCase_LoginExternal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo)
+
+	ctx := context.Background()
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "sub-1", Username: "mariem", Email: "mariem@example.com"}
+	notLinked := fmt.Errorf("user %w", repositories.ErrNotFound)
+
+	t.Run("existing user", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserBySSOIdentity(ctx, testIssuer, "sub-1").Return(&entities.User{ID: "user123", Username: "mariem"}, nil)
+		mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+
+		token, err := authUC.LoginExternal(ctx, identity, entities.SessionMetadata{})
+		require.NoError(t, err)
+		assert.Equal(t, "user123", token.UserID)
+		assert.NotEmpty(t, token.Token)
+	})
+
+	t.Run("first sign-in creates the user", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserBySSOIdentity(ctx, testIssuer, "sub-1").Return(nil, notLinked)
+		mockUserRepo.EXPECT().
+			CreateUser(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entities.User) error {
+				assert.Equal(t, "mariem", user.Username)
+				assert.Equal(t, "mariem@example.com", user.Email)
+				assert.Equal(t, testIssuer, user.SSOIssuer)
+				assert.Equal(t, "sub-1", user.SSOSubject)
+				assert.Empty(t, user.PasswordHash)
+				return nil
+			})
+		mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+
+		token, err := authUC.LoginExternal(ctx, identity, entities.SessionMetadata{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.UserID)
+	})
+
+	t.Run("username taken", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserBySSOIdentity(ctx, testIssuer, "sub-1").Return(nil, notLinked)
+		mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(fmt.Errorf("username %w", repositories.ErrAlreadyExists))
+
+		_, err := authUC.LoginExternal(ctx, identity, entities.SessionMetadata{})
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "USERNAME_TAKEN", domainErr.Reason)
+	})
+
+	t.Run("no usable username", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserBySSOIdentity(ctx, testIssuer, "sub-2").Return(nil, notLinked)
+
+		_, err := authUC.LoginExternal(ctx, entities.ExternalIdentity{Issuer: testIssuer, Subject: "sub-2"}, entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrInvalidArgument)
+	})
+}
+
+func TestAuthUseCase_SSOUsersHaveNoPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockResetRepo := mocks.NewMockPasswordResetRepository(ctrl)
+	mockNotifier := ucMocks.NewMockNotifier(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithPasswordReset(mockResetRepo, mockNotifier))
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem", Email: "mariem@example.com", SSOSubject: "sub-1"}
+
+	t.Run("password login", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+
+		_, err := authUC.Login(ctx, "mariem", "correct-horse-battery", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("password login with the old password", func(t *testing.T) {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correct-horse-battery"), bcrypt.MinCost)
+		linked := *user
+		linked.PasswordHash = string(hashedPassword)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(&linked, nil)
+
+		_, err := authUC.Login(ctx, "mariem", "wrong-horse-battery", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(&linked, nil)
+
+		_, err = authUC.Login(ctx, "mariem", "correct-horse-battery", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrFailedPrecondition)
+	})
+
+	t.Run("change password", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+
+		_, err := authUC.ChangePassword(ctx, "user123", "tok", "", "correct-horse-battery")
+		assert.ErrorIs(t, err, usecases.ErrFailedPrecondition)
+	})
+
+	t.Run("reset is not sent", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+
+		assert.NoError(t, authUC.RequestPasswordReset(ctx, "mariem", entities.SessionMetadata{}))
+	})
+
+	t.Run("reset token", func(t *testing.T) {
+		mockResetRepo.EXPECT().GetByHash(ctx, gomock.Any()).
+			Return(&entities.PasswordResetToken{UserID: "user123", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+
+		_, err := authUC.ResetPassword(ctx, "reset", "correct-horse-battery")
+		assert.ErrorIs(t, err, usecases.ErrFailedPrecondition)
+	})
+}
+
+func TestSSOUseCase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvider := ucMocks.NewMockIdentityProvider(ctrl)
+	mockAuthUC := ucMocks.NewMockAuthUseCase(ctrl)
+	ssoUC := usecases.NewSSOUseCase(mockProvider, mockAuthUC, usecases.DefaultSSOConfig())
+
+	ctx := context.Background()
+	mockProvider.EXPECT().Issuer().Return(testIssuer).AnyTimes()
+
+	begin := func(t *testing.T) *entities.SSORequest {
+		mockProvider.EXPECT().
+			AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(state, nonce, challenge string) string {
+				return testIssuer + "/authorize?" + url.Values{"state": {state}, "code_challenge": {challenge}}.Encode()
+			})
+		request, err := ssoUC.BeginLogin("laptop")
+		require.NoError(t, err)
+		return request
+	}
+
+	t.Run("begin login", func(t *testing.T) {
+		request := begin(t)
+		other := begin(t)
+
+		assert.NotEqual(t, request.State, other.State)
+		assert.NotEqual(t, request.CodeVerifier, other.CodeVerifier)
+		assert.NotContains(t, request.AuthURL, request.CodeVerifier)
+		assert.Equal(t, "laptop", request.DeviceLabel)
+	})
+
+	tests := []struct {
+		name         string
+		claims       map[string]interface{}
+		wantUsername string
+		wantEmail    string
+	}{
+		{
+			name:         "preferred username",
+			claims:       map[string]interface{}{"sub": "sub-1", "preferred_username": "mariem", "email": "m@example.com", "email_verified": true},
+			wantUsername: "mariem",
+			wantEmail:    "m@example.com",
+		},
+		{
+			name:         "falls back to the email",
+			claims:       map[string]interface{}{"sub": "sub-1", "email": "mariem@example.com"},
+			wantUsername: "mariem",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := begin(t)
+			mockProvider.EXPECT().Exchange(ctx, "code-1", request.CodeVerifier, request.Nonce).Return(tt.claims, nil)
+			mockAuthUC.EXPECT().
+				LoginExternal(ctx, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, identity entities.ExternalIdentity, session entities.SessionMetadata) (*entities.AuthToken, error) {
+					assert.Equal(t, entities.ExternalIdentity{Issuer: testIssuer, Subject: "sub-1", Username: tt.wantUsername, Email: tt.wantEmail}, identity)
+					assert.Equal(t, "laptop", session.DeviceLabel)
+					return &entities.AuthToken{Token: "token"}, nil
+				})
+
+			token, err := ssoUC.CompleteLogin(ctx, request, request.State, "code-1", entities.SessionMetadata{})
+			require.NoError(t, err)
+			assert.Equal(t, "token", token.Token)
+		})
+	}
+
+	t.Run("state mismatch", func(t *testing.T) {
+		request := begin(t)
+
+		_, err := ssoUC.CompleteLogin(ctx, request, "forged", "code-1", entities.SessionMetadata{})
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "SSO_STATE_MISMATCH", domainErr.Reason)
+
+		_, err = ssoUC.CompleteLogin(ctx, nil, request.State, "code-1", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("exchange fails", func(t *testing.T) {
+		request := begin(t)
+		mockProvider.EXPECT().Exchange(ctx, "code-1", request.CodeVerifier, request.Nonce).Return(nil, errors.New("invalid_grant"))
+
+		_, err := ssoUC.CompleteLogin(ctx, request, request.State, "code-1", entities.SessionMetadata{})
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "SSO_FAILED", domainErr.Reason)
+		assert.NotContains(t, domainErr.Message, "invalid_grant")
+	})
+}
@@ -0,0 +1,229 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+const recoveryCodeCount = 10
+
+func (uc *authUseCase) EnrollTwoFactor(ctx context.Context, userID string) (*entities.TwoFactorEnrollment, error) {
+	if uc.challengeRepo == nil {
+		return nil, failedPrecondition("TWO_FACTOR_UNAVAILABLE", "two-factor authentication is not available")
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBot {
+		return nil, failedPrecondition("BOT_TWO_FACTOR", "bot accounts authenticate with API keys")
+	}
+	if user.SSOSubject != "" {
+		return nil, failedPrecondition("SSO_TWO_FACTOR", "two-factor authentication is up to your identity provider")
+	}
+	if user.TwoFactorEnabled() {
+		return nil, failedPrecondition("TWO_FACTOR_ENABLED", "two-factor authentication is already on")
+	}
+
+	enrollment := &entities.TwoFactorEnrollment{Secret: generateTOTPSecret()}
+	enrollment.URI = totpURI(uc.config.TwoFactorIssuer, user.Username, enrollment.Secret)
+	twoFactor := &entities.TwoFactor{Secret: enrollment.Secret}
+	for i := 0; i < recoveryCodeCount; i++ {
+		code := generateRecoveryCode()
+		enrollment.RecoveryCodes = append(enrollment.RecoveryCodes, code)
+		twoFactor.RecoveryCodes = append(twoFactor.RecoveryCodes, hashSecret(normalizeRecoveryCode(code)))
+	}
+
+	if err := uc.userRepo.UpdateTwoFactor(ctx, userID, twoFactor); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+func (uc *authUseCase) ConfirmTwoFactor(ctx context.Context, userID, code string) error {
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.TwoFactor == nil {
+		return failedPrecondition("TWO_FACTOR_NOT_ENROLLED", "two-factor authentication has not been set up")
+	}
+	if user.TwoFactor.Enabled {
+		return failedPrecondition("TWO_FACTOR_ENABLED", "two-factor authentication is already on")
+	}
+
+	now := time.Now()
+	counter, ok := verifyTOTP(user.TwoFactor.Secret, strings.TrimSpace(code), now)
+	if !ok {
+		return invalidArgument("code", "INVALID_CODE", "invalid two-factor code")
+	}
+
+	user.TwoFactor.Enabled = true
+	user.TwoFactor.LastCounter = counter
+	user.TwoFactor.EnabledAt = now
+	return uc.userRepo.UpdateTwoFactor(ctx, userID, user.TwoFactor)
+}
+
+func (uc *authUseCase) DisableTwoFactor(ctx context.Context, userID, password, code string) error {
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.TwoFactor == nil {
+		return nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return invalidArgument("password", "WRONG_PASSWORD", "password is incorrect")
+	}
+	if user.TwoFactor.Enabled {
+		if err := uc.checkTwoFactorCode(ctx, user, code); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("User %s turned two-factor authentication off", userID)
+	return uc.userRepo.UpdateTwoFactor(ctx, userID, nil)
+}
+
+func (uc *authUseCase) CompleteLogin(ctx context.Context, challengeToken, code string) (*entities.AuthToken, error) {
+	if uc.challengeRepo == nil {
+		return nil, unauthenticated("INVALID_CHALLENGE", "invalid or expired login challenge")
+	}
+
+	tokenHash := hashSecret(challengeToken)
+	challenge, err := uc.challengeRepo.GetByHash(ctx, tokenHash)
+	if errors.Is(err, repositories.ErrNotFound) || err == nil && time.Now().After(challenge.ExpiresAt) {
+		return nil, unauthenticated("INVALID_CHALLENGE", "invalid or expired login challenge")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled() {
+		// Wrong codes count as failed logins of the username, so that the
+		// backoff and lockout of passwords apply across challenges too.
+		if uc.guard != nil {
+			if err := uc.guard.CheckLogin(ctx, user.Username, challenge.IPAddress); err != nil {
+				return nil, err
+			}
+		}
+		if err := uc.checkTwoFactorCode(ctx, user, code); err != nil {
+			if !errors.Is(err, ErrInvalidArgument) {
+				return nil, err
+			}
+			if uc.guard != nil {
+				if guardErr := uc.guard.LoginFailed(ctx, user.Username, challenge.IPAddress); guardErr != nil {
+					return nil, guardErr
+				}
+			}
+			attempts, attemptErr := uc.challengeRepo.RecordAttempt(ctx, tokenHash)
+			if attemptErr != nil {
+				return nil, attemptErr
+			}
+			if attempts >= uc.config.MaxChallengeAttempts {
+				if err := uc.challengeRepo.Delete(ctx, tokenHash); err != nil {
+					return nil, err
+				}
+				log.Printf("Login challenge of user %s ended after %d wrong codes", user.ID, attempts)
+				return nil, unauthenticated("TOO_MANY_ATTEMPTS", "too many wrong codes, log in again")
+			}
+			return nil, err
+		}
+	}
+
+	if err := uc.challengeRepo.Delete(ctx, tokenHash); err != nil {
+		return nil, err
+	}
+	uc.loginSucceeded(ctx, user)
+	return uc.generateToken(ctx, user, entities.SessionMetadata{
+		DeviceLabel: challenge.DeviceLabel,
+		IPAddress:   challenge.IPAddress,
+		UserAgent:   challenge.UserAgent,
+	})
+}
+
+// loginChallenge stands in for the session of a user with two-factor
+// authentication until CompleteLogin.
+func (uc *authUseCase) loginChallenge(ctx context.Context, user *entities.User, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	if uc.challengeRepo == nil {
+		return nil, failedPrecondition("TWO_FACTOR_UNAVAILABLE", "two-factor authentication is not available")
+	}
+
+	secret := generateToken()
+	now := time.Now()
+	challenge := &entities.LoginChallenge{
+		TokenHash:   hashSecret(secret),
+		UserID:      user.ID,
+		DeviceLabel: session.DeviceLabel,
+		IPAddress:   session.IPAddress,
+		UserAgent:   session.UserAgent,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(uc.config.LoginChallengeTTL),
+	}
+	if err := uc.challengeRepo.Create(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return &entities.AuthToken{
+		UserID:    user.ID,
+		ExpiresAt: challenge.ExpiresAt,
+		Challenge: secret,
+	}, nil
+}
+
+// checkTwoFactorCode accepts a current TOTP code or an unused recovery
+// code, and uses it up.
+func (uc *authUseCase) checkTwoFactorCode(ctx context.Context, user *entities.User, code string) error {
+	code = strings.TrimSpace(code)
+
+	if counter, ok := verifyTOTP(user.TwoFactor.Secret, code, time.Now()); ok {
+		err := uc.userRepo.UseTOTPCounter(ctx, user.ID, counter)
+		if errors.Is(err, repositories.ErrConflict) {
+			return invalidArgument("code", "INVALID_CODE", "invalid two-factor code")
+		}
+		return err
+	}
+
+	if len(code) == totpDigits {
+		return invalidArgument("code", "INVALID_CODE", "invalid two-factor code")
+	}
+	err := uc.userRepo.UseRecoveryCode(ctx, user.ID, hashSecret(normalizeRecoveryCode(code)))
+	if errors.Is(err, repositories.ErrNotFound) || errors.Is(err, repositories.ErrConflict) {
+		return invalidArgument("code", "INVALID_CODE", "invalid two-factor code")
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("User %s used a recovery code, %d left", user.ID, len(user.TwoFactor.RecoveryCodes)-1)
+	return nil
+}
+
+// generateRecoveryCode returns a code like "k3j9d-x7q2m".
+func generateRecoveryCode() string {
+	bytes := make([]byte, 7)
+	rand.Read(bytes)
+	code := strings.ToLower(totpEncoding.EncodeToString(bytes))[:10]
+	return code[:5] + "-" + code[5:]
+}
+
+// normalizeRecoveryCode lets codes be typed without the dash and in any
+// case.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
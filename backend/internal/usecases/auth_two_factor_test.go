@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+	repoMocks "chat-app/backend/internal/domain/repositories/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthUseCase_TwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := repoMocks.NewMockUserRepository(ctrl)
+	mockChallengeRepo := repoMocks.NewMockLoginChallengeRepository(ctrl)
+	authUC := NewAuthUseCase(mockUserRepo, WithTwoFactor(mockChallengeRepo))
+
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	secret := generateTOTPSecret()
+	counter := totpCounter(time.Now())
+	code, err := totpCode(secret, counter)
+	require.NoError(t, err)
+
+	t.Run("enroll returns the secret and recovery codes", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(&entities.User{ID: "user123", Username: "mariem"}, nil)
+		mockUserRepo.EXPECT().
+			UpdateTwoFactor(ctx, "user123", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, userID string, twoFactor *entities.TwoFactor) error {
+				assert.False(t, twoFactor.Enabled)
+				assert.Len(t, twoFactor.RecoveryCodes, recoveryCodeCount)
+				return nil
+			})
+
+		enrollment, err := authUC.EnrollTwoFactor(ctx, "user123")
+		require.NoError(t, err)
+		assert.NotEmpty(t, enrollment.Secret)
+		assert.Contains(t, enrollment.URI, "otpauth://totp/")
+		assert.Len(t, enrollment.RecoveryCodes, recoveryCodeCount)
+	})
+
+	t.Run("confirm turns it on", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(&entities.User{
+			ID:        "user123",
+			TwoFactor: &entities.TwoFactor{Secret: secret},
+		}, nil)
+		mockUserRepo.EXPECT().
+			UpdateTwoFactor(ctx, "user123", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, userID string, twoFactor *entities.TwoFactor) error {
+				assert.True(t, twoFactor.Enabled)
+				assert.Equal(t, counter, twoFactor.LastCounter)
+				return nil
+			})
+
+		require.NoError(t, authUC.ConfirmTwoFactor(ctx, "user123", code))
+	})
+
+	user := &entities.User{
+		ID:           "user123",
+		Username:     "mariem",
+		PasswordHash: string(hashedPassword),
+		TwoFactor: &entities.TwoFactor{
+			Secret:        secret,
+			Enabled:       true,
+			RecoveryCodes: []string{hashSecret("abcdefghij")},
+		},
+	}
+
+	t.Run("login returns a challenge", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockChallengeRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, challenge *entities.LoginChallenge) error {
+				assert.Equal(t, "laptop", challenge.DeviceLabel)
+				return nil
+			})
+
+		token, err := authUC.Login(ctx, "mariem", "secret", entities.SessionMetadata{DeviceLabel: "laptop"})
+		require.NoError(t, err)
+		assert.Empty(t, token.Token)
+		assert.NotEmpty(t, token.Challenge)
+	})
+
+	challenge := &entities.LoginChallenge{UserID: "user123", DeviceLabel: "laptop", ExpiresAt: time.Now().Add(time.Minute)}
+
+	t.Run("complete login with a code", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, hashSecret("chal")).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockUserRepo.EXPECT().UseTOTPCounter(ctx, "user123", counter).Return(nil)
+		mockChallengeRepo.EXPECT().Delete(ctx, hashSecret("chal")).Return(nil)
+		mockUserRepo.EXPECT().
+			StoreToken(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *entities.AuthToken) error {
+				assert.Equal(t, "laptop", token.DeviceLabel)
+				return nil
+			})
+
+		token, err := authUC.CompleteLogin(ctx, "chal", code)
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.Token)
+	})
+
+	t.Run("replayed code", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, hashSecret("chal")).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockUserRepo.EXPECT().UseTOTPCounter(ctx, "user123", gomock.Any()).
+			Return(fmt.Errorf("%w: code was already used", repositories.ErrConflict))
+		mockChallengeRepo.EXPECT().RecordAttempt(ctx, hashSecret("chal")).Return(1, nil)
+
+		_, err := authUC.CompleteLogin(ctx, "chal", code)
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+
+	t.Run("recovery code", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, hashSecret("chal")).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockUserRepo.EXPECT().UseRecoveryCode(ctx, "user123", hashSecret("abcdefghij")).Return(nil)
+		mockChallengeRepo.EXPECT().Delete(ctx, hashSecret("chal")).Return(nil)
+		mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+
+		_, err := authUC.CompleteLogin(ctx, "chal", "ABCDE-FGHIJ")
+		require.NoError(t, err)
+	})
+
+	t.Run("too many wrong codes end the challenge", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, hashSecret("chal")).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(user, nil)
+		mockChallengeRepo.EXPECT().RecordAttempt(ctx, hashSecret("chal")).Return(5, nil)
+		mockChallengeRepo.EXPECT().Delete(ctx, hashSecret("chal")).Return(nil)
+
+		_, err := authUC.CompleteLogin(ctx, "chal", "000000")
+		assert.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("expired challenge", func(t *testing.T) {
+		mockChallengeRepo.EXPECT().GetByHash(ctx, hashSecret("old")).
+			Return(&entities.LoginChallenge{UserID: "user123", ExpiresAt: time.Now().Add(-time.Second)}, nil)
+
+		_, err := authUC.CompleteLogin(ctx, "old", code)
+		assert.ErrorIs(t, err, ErrUnauthenticated)
+	})
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
-	"fmt"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -14,37 +18,188 @@ import (
 )
 
 type AuthUseCase interface {
-	Register(ctx context.Context, username, password string) (*entities.AuthToken, error)
-	Login(ctx context.Context, username, password string) (*entities.AuthToken, error)
+	Register(ctx context.Context, username, password string, session entities.SessionMetadata) (*entities.AuthToken, error)
+	Login(ctx context.Context, username, password string, session entities.SessionMetadata) (*entities.AuthToken, error)
 	ValidateToken(ctx context.Context, token string) (*entities.User, error)
 	Logout(ctx context.Context, token string) error
+	ListSessions(ctx context.Context, userID string) ([]*entities.AuthToken, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	// RevokeOtherSessions ends every session of the user except the one of
+	// currentToken and returns how many were ended.
+	RevokeOtherSessions(ctx context.Context, userID, currentToken string) (int, error)
+	// RefreshToken exchanges a refresh token for a new access token and a
+	// new refresh token of the same session.
+	RefreshToken(ctx context.Context, refreshToken string, session entities.SessionMetadata) (*entities.AuthToken, error)
+	// ChangePassword ends every other session of the user and returns how
+	// many were ended.
+	ChangePassword(ctx context.Context, userID, currentToken, currentPassword, newPassword string) (int, error)
+	SetEmail(ctx context.Context, userID, email string) error
+	// RequestPasswordReset sends a reset token to the user's email. It
+	// succeeds whether or not the user exists, so that it cannot be used to
+	// find accounts. Requests are throttled per username and address.
+	RequestPasswordReset(ctx context.Context, username string, session entities.SessionMetadata) error
+	// ResetPassword ends every session of the user and returns how many
+	// were ended.
+	ResetPassword(ctx context.Context, resetToken, newPassword string) (int, error)
+	EnrollTwoFactor(ctx context.Context, userID string) (*entities.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID, code string) error
+	DisableTwoFactor(ctx context.Context, userID, password, code string) error
+	// CompleteLogin exchanges the challenge Login returned to a user with
+	// two-factor authentication, and a TOTP or recovery code, for a session.
+	CompleteLogin(ctx context.Context, challenge, code string) (*entities.AuthToken, error)
+	// LoginExternal starts a session for a user an identity provider has
+	// signed in, creating their account the first time.
+	LoginExternal(ctx context.Context, identity entities.ExternalIdentity, session entities.SessionMetadata) (*entities.AuthToken, error)
+	// UnlockAccount lets an admin end the lockout of a username that had
+	// too many failed logins.
+	UnlockAccount(ctx context.Context, admin *entities.User, username string) error
+}
+
+// unrefreshableTokenTTL is how long access tokens last when there is no
+// refresh token repository to renew them with.
+const unrefreshableTokenTTL = 24 * time.Hour
+
+type AuthConfig struct {
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL is how long a session may go unused before it has to
+	// log in again. Every refresh starts it over.
+	RefreshTokenTTL      time.Duration
+	PasswordResetTTL     time.Duration
+	LoginChallengeTTL    time.Duration
+	MaxChallengeAttempts int
+	// TwoFactorIssuer names the service in authenticator apps.
+	TwoFactorIssuer string
+	UsernamePolicy  UsernamePolicy
+	PasswordPolicy  PasswordPolicy
+}
+
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		AccessTokenTTL:       15 * time.Minute,
+		RefreshTokenTTL:      30 * 24 * time.Hour,
+		PasswordResetTTL:     time.Hour,
+		LoginChallengeTTL:    5 * time.Minute,
+		MaxChallengeAttempts: 5,
+		TwoFactorIssuer:      "chat-app",
+		UsernamePolicy:       DefaultUsernamePolicy(),
+		PasswordPolicy:       DefaultPasswordPolicy(),
+	}
 }
 
 type authUseCase struct {
-	userRepo repositories.UserRepository
+	userRepo      repositories.UserRepository
+	apiKeyRepo    repositories.APIKeyRepository
+	refreshRepo   repositories.RefreshTokenRepository
+	signer        *TokenSigner
+	revocations   RevocationList
+	resetRepo     repositories.PasswordResetRepository
+	notifier      Notifier
+	challengeRepo repositories.LoginChallengeRepository
+	guard         LoginGuard
+	breached      BreachedPasswords
+	config        AuthConfig
+
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+}
+
+type AuthUseCaseOption func(*authUseCase)
+
+func WithAuthConfig(config AuthConfig) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.config = config
+	}
 }
 
-func NewAuthUseCase(userRepo repositories.UserRepository) AuthUseCase {
-	return &authUseCase{userRepo: userRepo}
+// WithRefreshTokenRepository issues a refresh token with every session, and
+// access tokens then last AuthConfig.AccessTokenTTL.
+func WithRefreshTokenRepository(refreshRepo repositories.RefreshTokenRepository) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.refreshRepo = refreshRepo
+	}
 }
 
-func (uc *authUseCase) Register(ctx context.Context, username, password string) (*entities.AuthToken, error) {
-	if len(username) < 3 {
-		return nil, fmt.Errorf("username must be at least 3 characters")
+// WithAPIKeyRepository lets bot accounts authenticate with their API keys
+// wherever a session token is accepted.
+func WithAPIKeyRepository(apiKeyRepo repositories.APIKeyRepository) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.apiKeyRepo = apiKeyRepo
 	}
-	if len(password) < 6 {
-		return nil, fmt.Errorf("password must be at least 6 characters")
+}
+
+// WithTokenSigner issues signed access tokens, which are validated without
+// reading the store. Revoked sessions are checked against revocations, and
+// a deleted user's tokens stay valid until they expire.
+func WithTokenSigner(signer *TokenSigner, revocations RevocationList) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.signer = signer
+		uc.revocations = revocations
 	}
+}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// WithPasswordReset lets users who forgot their password set a new one
+// with a token sent by notifier.
+func WithPasswordReset(resetRepo repositories.PasswordResetRepository, notifier Notifier) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.resetRepo = resetRepo
+		uc.notifier = notifier
+	}
+}
+
+// WithTwoFactor lets users turn on TOTP two-factor authentication, storing
+// the logins that wait for a code in challengeRepo.
+func WithTwoFactor(challengeRepo repositories.LoginChallengeRepository) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.challengeRepo = challengeRepo
+	}
+}
+
+// WithLoginGuard limits how fast passwords can be guessed and accounts
+// registered.
+func WithLoginGuard(guard LoginGuard) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.guard = guard
+	}
+}
+
+// WithBreachedPasswords refuses new passwords that appear in breached.
+func WithBreachedPasswords(breached BreachedPasswords) AuthUseCaseOption {
+	return func(uc *authUseCase) {
+		uc.breached = breached
+	}
+}
+
+func NewAuthUseCase(userRepo repositories.UserRepository, opts ...AuthUseCaseOption) AuthUseCase {
+	uc := &authUseCase{userRepo: userRepo, config: DefaultAuthConfig()}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+func (uc *authUseCase) Register(ctx context.Context, username, password string, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	username, err := uc.config.UsernamePolicy.normalize("username", username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %v", err)
+		return nil, err
+	}
+	if err := uc.checkPassword(ctx, "password", username, password); err != nil {
+		return nil, err
+	}
+	if uc.guard != nil {
+		if err := uc.guard.CheckRegistration(ctx, session.IPAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	hashedPassword, err := uc.hashPassword(password)
+	if err != nil {
+		return nil, err
 	}
 
 	user := &entities.User{
 		ID:           generateID(),
 		Username:     username,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		CreatedAt:    time.Now(),
 	}
 
@@ -52,61 +207,464 @@ func (uc *authUseCase) Register(ctx context.Context, username, password string)
 		return nil, err
 	}
 
-	return uc.generateToken(ctx, user.ID)
+	return uc.generateToken(ctx, user, session)
 }
 
-func (uc *authUseCase) Login(ctx context.Context, username, password string) (*entities.AuthToken, error) {
+func (uc *authUseCase) Login(ctx context.Context, username, password string, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	if uc.guard != nil {
+		if err := uc.guard.CheckLogin(ctx, username, session.IPAddress); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := uc.userRepo.GetUserByUsername(ctx, username)
+	if errors.Is(err, repositories.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(uc.dummyPasswordHash(), []byte(password))
+		return nil, uc.loginFailed(ctx, username, session)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+	// Bots and single sign-on users have no password hash, so theirs is
+	// checked against the dummy one and fails like any wrong password. Why
+	// an account cannot log in with a password is only told to whoever
+	// knows it.
+	hash := []byte(user.PasswordHash)
+	if len(hash) == 0 {
+		hash = uc.dummyPasswordHash()
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil {
+		return nil, uc.loginFailed(ctx, username, session)
 	}
+	if user.IsBot {
+		return nil, failedPrecondition("BOT_PASSWORD_LOGIN", "bot accounts authenticate with API keys")
+	}
+	if user.SSOSubject != "" {
+		return nil, errSSOPassword
+	}
+	if uc.needsRehash(user.PasswordHash) {
+		if err := uc.setPassword(ctx, user.ID, password); err != nil {
+			log.Printf("Error upgrading password hash of user %s: %v", user.ID, err)
+		}
+	}
+	// With two-factor authentication the failed logins are only forgotten
+	// once CompleteLogin accepts a code.
+	if user.TwoFactorEnabled() {
+		return uc.loginChallenge(ctx, user, session)
+	}
+	uc.loginSucceeded(ctx, user)
 
-	return uc.generateToken(ctx, user.ID)
+	return uc.generateToken(ctx, user, session)
 }
 
 func (uc *authUseCase) ValidateToken(ctx context.Context, token string) (*entities.User, error) {
+	if strings.HasPrefix(token, apiKeyPrefix) && uc.apiKeyRepo != nil {
+		return uc.validateAPIKey(ctx, token)
+	}
+	if uc.signer != nil && isSignedToken(token) {
+		return uc.validateSignedToken(token)
+	}
+
 	authToken, err := uc.userRepo.ValidateToken(ctx, token)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, unauthenticated("INVALID_TOKEN", "invalid or expired token")
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	user, err := uc.userRepo.GetUserByID(ctx, authToken.UserID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, unauthenticated("INVALID_TOKEN", "invalid or expired token")
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
+	if now.Sub(authToken.LastUsedAt) > time.Minute {
+		if err := uc.userRepo.TouchToken(ctx, token, now); err != nil {
+			log.Printf("Error recording session use for user %s: %v", user.ID, err)
+		}
+	}
+
+	return user, nil
+}
+
+// validateSignedToken trusts the token's claims, so the user is not read
+// and the session's last use is not recorded.
+func (uc *authUseCase) validateSignedToken(token string) (*entities.User, error) {
+	now := time.Now()
+	claims, err := uc.signer.Verify(token, now)
+	if err != nil || uc.revocations.IsRevoked(claims.SessionID, now) {
+		return nil, unauthenticated("INVALID_TOKEN", "invalid or expired token")
+	}
+	return &entities.User{ID: claims.Subject, Username: claims.Username}, nil
+}
+
+func (uc *authUseCase) validateAPIKey(ctx context.Context, secret string) (*entities.User, error) {
+	keyHash := hashSecret(secret)
+	key, err := uc.apiKeyRepo.GetByHash(ctx, keyHash)
+	if errors.Is(err, repositories.ErrNotFound) || err == nil && key.Revoked {
+		return nil, unauthenticated("INVALID_API_KEY", "invalid api key")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsBot {
+		return nil, unauthenticated("INVALID_API_KEY", "invalid api key")
+	}
+
+	now := time.Now()
+	if now.Sub(key.LastUsedAt) > time.Minute {
+		if err := uc.apiKeyRepo.TouchLastUsed(ctx, keyHash, now); err != nil {
+			log.Printf("Error recording api key use for %s: %v", key.ID, err)
+		}
+	}
+
 	return user, nil
 }
 
 func (uc *authUseCase) Logout(ctx context.Context, token string) error {
-	return uc.userRepo.DeleteToken(ctx, token)
+	if strings.HasPrefix(token, apiKeyPrefix) {
+		return invalidArgument("token", "API_KEY_LOGOUT", "api keys are revoked with RevokeBotKey")
+	}
+
+	authToken, err := uc.userRepo.ValidateToken(ctx, token)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return uc.userRepo.DeleteToken(ctx, token)
+	}
+	if err != nil {
+		return err
+	}
+
+	tokens, err := uc.sessionTokens(ctx, authToken.UserID)
+	if err != nil {
+		return err
+	}
+	return uc.endSession(ctx, authToken.UserID, tokens, withSessionID(authToken).ID)
+}
+
+// ListSessions returns the user's unexpired sessions, most recently used
+// first. A refreshed session is listed once, with its newest access token.
+func (uc *authUseCase) ListSessions(ctx context.Context, userID string) ([]*entities.AuthToken, error) {
+	tokens, err := uc.sessionTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]*entities.AuthToken, 0, len(tokens))
+	index := make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		if now.After(token.SessionExpiry()) {
+			continue
+		}
+		if i, ok := index[token.ID]; ok {
+			if lastActive(token).After(lastActive(sessions[i])) {
+				sessions[i] = token
+			}
+			continue
+		}
+		index[token.ID] = len(sessions)
+		sessions = append(sessions, token)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return lastActive(sessions[i]).After(lastActive(sessions[j]))
+	})
+	return sessions, nil
+}
+
+func (uc *authUseCase) RevokeSession(ctx context.Context, userID, id string) error {
+	tokens, err := uc.sessionTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, token := range tokens {
+		if token.ID == id && !now.After(token.SessionExpiry()) {
+			return uc.endSession(ctx, userID, tokens, id)
+		}
+	}
+	return notFound("SESSION_NOT_FOUND", "session not found")
+}
+
+func (uc *authUseCase) RevokeOtherSessions(ctx context.Context, userID, currentToken string) (int, error) {
+	tokens, err := uc.sessionTokens(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	current := ""
+	for _, token := range tokens {
+		if token.Token == currentToken {
+			current = token.ID
+		}
+	}
+
+	now := time.Now()
+	ended := map[string]bool{current: true}
+	for _, token := range tokens {
+		if ended[token.ID] || now.After(token.SessionExpiry()) {
+			continue
+		}
+		if err := uc.endSession(ctx, userID, tokens, token.ID); err != nil {
+			return len(ended) - 1, err
+		}
+		ended[token.ID] = true
+	}
+	return len(ended) - 1, nil
+}
+
+func (uc *authUseCase) RefreshToken(ctx context.Context, refreshToken string, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	if uc.refreshRepo == nil || refreshToken == "" {
+		return nil, unauthenticated("INVALID_REFRESH_TOKEN", "invalid refresh token")
+	}
+
+	tokenHash := hashSecret(refreshToken)
+	current, err := uc.refreshRepo.GetByHash(ctx, tokenHash)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, unauthenticated("INVALID_REFRESH_TOKEN", "invalid refresh token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !current.UsedAt.IsZero() {
+		return nil, uc.refreshTokenReused(ctx, current)
+	}
+
+	now := time.Now()
+	if now.After(current.ExpiresAt) {
+		return nil, unauthenticated("REFRESH_TOKEN_EXPIRED", "refresh token expired")
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, current.UserID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, unauthenticated("INVALID_REFRESH_TOKEN", "invalid refresh token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	secret := generateToken()
+	next := &entities.RefreshToken{
+		TokenHash:        hashSecret(secret),
+		SessionID:        current.SessionID,
+		UserID:           current.UserID,
+		DeviceLabel:      current.DeviceLabel,
+		SessionCreatedAt: current.SessionCreatedAt,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(uc.config.RefreshTokenTTL),
+	}
+	err = uc.refreshRepo.Rotate(ctx, tokenHash, next)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, uc.refreshTokenReused(ctx, current)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	authToken := &entities.AuthToken{
+		ID:               current.SessionID,
+		UserID:           current.UserID,
+		ExpiresAt:        now.Add(uc.config.AccessTokenTTL),
+		SessionExpiresAt: next.ExpiresAt,
+		CreatedAt:        current.SessionCreatedAt,
+		LastUsedAt:       now,
+		DeviceLabel:      current.DeviceLabel,
+		IPAddress:        session.IPAddress,
+		UserAgent:        session.UserAgent,
+		RefreshToken:     secret,
+	}
+	if err := uc.storeToken(ctx, user, authToken); err != nil {
+		return nil, err
+	}
+
+	uc.deleteExpiredTokens(ctx, authToken)
+	return authToken, nil
+}
+
+// refreshTokenReused ends the session of a refresh token that was presented
+// after it had been exchanged. Either the client or whoever copied the token
+// is an attacker, and there is no telling which.
+func (uc *authUseCase) refreshTokenReused(ctx context.Context, token *entities.RefreshToken) error {
+	log.Printf("Refresh token of session %s of user %s was reused, revoking the session", token.SessionID, token.UserID)
+
+	tokens, err := uc.sessionTokens(ctx, token.UserID)
+	if err != nil {
+		return err
+	}
+	if err := uc.endSession(ctx, token.UserID, tokens, token.SessionID); err != nil {
+		return err
+	}
+	return unauthenticated("REFRESH_TOKEN_REUSED", "refresh token was already used, the session has been revoked")
+}
+
+// deleteExpiredTokens removes the access tokens a refresh replaced. Expired
+// tokens of a session that is never refreshed again are removed when the
+// session ends.
+func (uc *authUseCase) deleteExpiredTokens(ctx context.Context, current *entities.AuthToken) {
+	tokens, err := uc.sessionTokens(ctx, current.UserID)
+	if err != nil {
+		log.Printf("Error listing tokens of session %s: %v", current.ID, err)
+		return
+	}
+
+	now := time.Now()
+	for _, token := range tokens {
+		if token.ID != current.ID || !now.After(token.ExpiresAt) {
+			continue
+		}
+		if err := uc.userRepo.DeleteToken(ctx, token.Token); err != nil {
+			log.Printf("Error deleting expired token of session %s: %v", current.ID, err)
+		}
+	}
 }
 
-func (uc *authUseCase) generateToken(ctx context.Context, userID string) (*entities.AuthToken, error) {
-	token := generateToken()
+// sessionTokens returns every access token of the user, expired ones too.
+func (uc *authUseCase) sessionTokens(ctx context.Context, userID string) ([]*entities.AuthToken, error) {
+	tokens, err := uc.userRepo.ListTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		withSessionID(token)
+	}
+	return tokens, nil
+}
+
+// endSession deletes the session's access tokens among tokens and its
+// refresh tokens. Signed access tokens cannot be deleted, so the session is
+// revoked until the last of them expires.
+func (uc *authUseCase) endSession(ctx context.Context, userID string, tokens []*entities.AuthToken, id string) error {
+	var expiresAt time.Time
+	for _, token := range tokens {
+		if token.ID != id {
+			continue
+		}
+		if token.ExpiresAt.After(expiresAt) {
+			expiresAt = token.ExpiresAt
+		}
+		if err := uc.userRepo.DeleteToken(ctx, token.Token); err != nil {
+			return err
+		}
+	}
+
+	if uc.signer != nil && time.Now().Before(expiresAt) {
+		err := uc.revocations.Revoke(ctx, &entities.RevokedSession{
+			SessionID: id,
+			UserID:    userID,
+			RevokedAt: time.Now(),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if uc.refreshRepo == nil {
+		return nil
+	}
+	return uc.refreshRepo.DeleteSession(ctx, id)
+}
+
+func (uc *authUseCase) generateToken(ctx context.Context, user *entities.User, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	now := time.Now()
 	authToken := &entities.AuthToken{
-		Token:     token,
-		UserID:    userID,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
+		ID:          generateID(),
+		UserID:      user.ID,
+		ExpiresAt:   now.Add(unrefreshableTokenTTL),
+		CreatedAt:   now,
+		LastUsedAt:  now,
+		DeviceLabel: session.DeviceLabel,
+		IPAddress:   session.IPAddress,
+		UserAgent:   session.UserAgent,
+	}
+	if uc.refreshRepo == nil {
+		if err := uc.storeToken(ctx, user, authToken); err != nil {
+			return nil, err
+		}
+		return authToken, nil
 	}
 
-	if err := uc.userRepo.StoreToken(ctx, authToken); err != nil {
+	secret := generateToken()
+	refreshToken := &entities.RefreshToken{
+		TokenHash:        hashSecret(secret),
+		SessionID:        authToken.ID,
+		UserID:           user.ID,
+		DeviceLabel:      session.DeviceLabel,
+		SessionCreatedAt: now,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(uc.config.RefreshTokenTTL),
+	}
+	authToken.ExpiresAt = now.Add(uc.config.AccessTokenTTL)
+	authToken.SessionExpiresAt = refreshToken.ExpiresAt
+	authToken.RefreshToken = secret
+
+	if err := uc.storeToken(ctx, user, authToken); err != nil {
+		return nil, err
+	}
+	if err := uc.refreshRepo.Create(ctx, refreshToken); err != nil {
 		return nil, err
 	}
 
 	return authToken, nil
 }
 
+// storeToken fills in the access token and stores it. Signed tokens are
+// stored too, so that their sessions can be listed and revoked.
+func (uc *authUseCase) storeToken(ctx context.Context, user *entities.User, authToken *entities.AuthToken) error {
+	authToken.Token = generateToken()
+	if uc.signer != nil {
+		token, err := uc.signer.Sign(TokenClaims{
+			Subject:   user.ID,
+			Username:  user.Username,
+			SessionID: authToken.ID,
+			IssuedAt:  authToken.LastUsedAt.Unix(),
+			ExpiresAt: authToken.ExpiresAt.Unix(),
+		})
+		if err != nil {
+			return err
+		}
+		authToken.Token = token
+	}
+	return uc.userRepo.StoreToken(ctx, authToken)
+}
+
 func generateToken() string {
 	bytes := make([]byte, 32)
 	rand.Read(bytes)
 	return hex.EncodeToString(bytes)
 }
 
+// sessionID is derived from the token so that sessions stored before IDs
+// existed can be addressed too.
+func sessionID(token string) string {
+	return hashSecret(token)[:32]
+}
+
+// withSessionID gives sessions created before IDs were stored their ID.
+func withSessionID(token *entities.AuthToken) *entities.AuthToken {
+	if token.ID == "" {
+		token.ID = sessionID(token.Token)
+	}
+	return token
+}
+
+func lastActive(token *entities.AuthToken) time.Time {
+	if token.LastUsedAt.After(token.CreatedAt) {
+		return token.LastUsedAt
+	}
+	return token.CreatedAt
+}
+
 func generateID() string {
 	bytes := make([]byte, 16)
 	rand.Read(bytes)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
 	"chat-app/backend/internal/domain/repositories/mocks"
 	"chat-app/backend/internal/usecases"
 
@@ -25,7 +26,7 @@ func TestAuthUseCase_Register(t *testing.T) {
 
 	ctx := context.Background()
 	username := "mariem"
-	password := "password_mariem"
+	password := "correct-horse-battery"
 
 	t.Run("successful registration", func(t *testing.T) {
 		mockUserRepo.EXPECT().
@@ -46,7 +47,7 @@ func TestAuthUseCase_Register(t *testing.T) {
 				return nil
 			})
 
-		token, err := authUC.Register(ctx, username, password)
+		token, err := authUC.Register(ctx, username, password, entities.SessionMetadata{})
 		require.NoError(t, err)
 		require.NotNil(t, token)
 		assert.NotEmpty(t, token.Token)
@@ -55,21 +56,31 @@ func TestAuthUseCase_Register(t *testing.T) {
 	t.Run("username already exists", func(t *testing.T) {
 		mockUserRepo.EXPECT().
 			CreateUser(ctx, gomock.Any()).
-			Return(fmt.Errorf("username already exists"))
+			Return(fmt.Errorf("username %w", repositories.ErrAlreadyExists))
 
-		token, err := authUC.Register(ctx, username, password)
+		token, err := authUC.Register(ctx, username, password, entities.SessionMetadata{})
 		require.Error(t, err)
 		assert.Nil(t, token)
+
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, usecases.KindAlreadyExists, domainErr.Kind)
+		assert.Equal(t, "username already exists", domainErr.Message)
 	})
 
 	t.Run("invalid username", func(t *testing.T) {
-		token, err := authUC.Register(ctx, "ab", "password_mariem")
+		token, err := authUC.Register(ctx, "ab", "correct-horse-battery", entities.SessionMetadata{})
 		require.Error(t, err)
 		assert.Nil(t, token)
+
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, usecases.KindInvalidArgument, domainErr.Kind)
+		assert.Equal(t, "username", domainErr.Field)
 	})
 
 	t.Run("invalid password", func(t *testing.T) {
-		token, err := authUC.Register(ctx, username, "123")
+		token, err := authUC.Register(ctx, username, "123", entities.SessionMetadata{})
 		require.Error(t, err)
 		assert.Nil(t, token)
 	})
@@ -102,9 +113,14 @@ func TestAuthUseCase_Login(t *testing.T) {
 			StoreToken(ctx, gomock.Any()).
 			Return(nil)
 
-		token, err := authUC.Login(ctx, username, password)
+		session := entities.SessionMetadata{DeviceLabel: "laptop", IPAddress: "203.0.113.7", UserAgent: "curl/8.0"}
+		token, err := authUC.Login(ctx, username, password, session)
 		require.NoError(t, err)
-		assert.NotNil(t, token)
+		require.NotNil(t, token)
+		assert.Equal(t, "laptop", token.DeviceLabel)
+		assert.Equal(t, "203.0.113.7", token.IPAddress)
+		assert.Equal(t, "curl/8.0", token.UserAgent)
+		assert.NotEmpty(t, token.ID)
 	})
 
 	t.Run("invalid credentials", func(t *testing.T) {
@@ -112,20 +128,31 @@ func TestAuthUseCase_Login(t *testing.T) {
 			GetUserByUsername(ctx, username).
 			Return(user, nil)
 
-		token, err := authUC.Login(ctx, username, "wrongpassword")
-		require.Error(t, err)
+		token, err := authUC.Login(ctx, username, "wrongpassword", entities.SessionMetadata{})
+		require.ErrorIs(t, err, usecases.ErrUnauthenticated)
 		assert.Nil(t, token)
 	})
 
 	t.Run("user not found", func(t *testing.T) {
 		mockUserRepo.EXPECT().
 			GetUserByUsername(ctx, username).
-			Return(nil, fmt.Errorf("user not found"))
+			Return(nil, fmt.Errorf("user %w", repositories.ErrNotFound))
 
-		token, err := authUC.Login(ctx, username, password)
-		require.Error(t, err)
+		token, err := authUC.Login(ctx, username, password, entities.SessionMetadata{})
+		require.ErrorIs(t, err, usecases.ErrUnauthenticated)
 		assert.Nil(t, token)
 	})
+
+	t.Run("storage failure is not reported as bad credentials", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetUserByUsername(ctx, username).
+			Return(nil, fmt.Errorf("deadline exceeded"))
+
+		_, err := authUC.Login(ctx, username, password, entities.SessionMetadata{})
+		require.Error(t, err)
+		_, ok := usecases.AsError(err)
+		assert.False(t, ok)
+	})
 }
 
 func TestAuthUseCase_ValidateToken(t *testing.T) {
@@ -157,18 +184,288 @@ func TestAuthUseCase_ValidateToken(t *testing.T) {
 			GetUserByID(ctx, userID).
 			Return(user, nil)
 
+		mockUserRepo.EXPECT().
+			TouchToken(ctx, tokenStr, gomock.Any()).
+			Return(nil)
+
 		result, err := authUC.ValidateToken(ctx, tokenStr)
 		require.NoError(t, err)
 		assert.Equal(t, user, result)
 	})
 
+	t.Run("recently used token is not touched", func(t *testing.T) {
+		recent := *authToken
+		recent.LastUsedAt = time.Now().Add(-10 * time.Second)
+		mockUserRepo.EXPECT().
+			ValidateToken(ctx, tokenStr).
+			Return(&recent, nil)
+
+		mockUserRepo.EXPECT().
+			GetUserByID(ctx, userID).
+			Return(user, nil)
+
+		_, err := authUC.ValidateToken(ctx, tokenStr)
+		require.NoError(t, err)
+	})
+
 	t.Run("invalid token", func(t *testing.T) {
 		mockUserRepo.EXPECT().
 			ValidateToken(ctx, "invalid-token").
-			Return(nil, fmt.Errorf("token not found"))
+			Return(nil, fmt.Errorf("token %w", repositories.ErrNotFound))
 
 		result, err := authUC.ValidateToken(ctx, "invalid-token")
-		require.Error(t, err)
+		require.ErrorIs(t, err, usecases.ErrUnauthenticated)
 		assert.Nil(t, result)
 	})
 }
+
+func TestAuthUseCase_Sessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo)
+
+	ctx := context.Background()
+	now := time.Now()
+	tokens := func() []*entities.AuthToken {
+		return []*entities.AuthToken{
+			{Token: "old", ID: "s1", UserID: "user123", CreatedAt: now.Add(-2 * time.Hour), LastUsedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+			{Token: "current", ID: "s2", UserID: "user123", CreatedAt: now.Add(-time.Hour), LastUsedAt: now, ExpiresAt: now.Add(time.Hour)},
+			{Token: "expired", ID: "s3", UserID: "user123", ExpiresAt: now.Add(-time.Minute)},
+			{Token: "legacy", UserID: "user123", ExpiresAt: now.Add(time.Hour)},
+		}
+	}
+
+	t.Run("list skips expired sessions and sorts by last use", func(t *testing.T) {
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return(tokens(), nil)
+
+		sessions, err := authUC.ListSessions(ctx, "user123")
+		require.NoError(t, err)
+		require.Len(t, sessions, 3)
+		assert.Equal(t, "s2", sessions[0].ID)
+		assert.Equal(t, "s1", sessions[1].ID)
+		assert.Equal(t, "legacy", sessions[2].Token)
+		assert.NotEmpty(t, sessions[2].ID)
+	})
+
+	t.Run("revoke session", func(t *testing.T) {
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return(tokens(), nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "old").Return(nil)
+
+		require.NoError(t, authUC.RevokeSession(ctx, "user123", "s1"))
+	})
+
+	t.Run("revoke unknown session", func(t *testing.T) {
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return(tokens(), nil)
+
+		err := authUC.RevokeSession(ctx, "user123", "s3")
+		assert.ErrorIs(t, err, usecases.ErrNotFound)
+	})
+
+	t.Run("revoke other sessions keeps the current one", func(t *testing.T) {
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return(tokens(), nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "old").Return(nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "legacy").Return(nil)
+
+		revoked, err := authUC.RevokeOtherSessions(ctx, "user123", "current")
+		require.NoError(t, err)
+		assert.Equal(t, 2, revoked)
+	})
+
+	t.Run("api keys cannot log out", func(t *testing.T) {
+		err := authUC.Logout(ctx, "bot_abc")
+		assert.ErrorIs(t, err, usecases.ErrInvalidArgument)
+	})
+}
+
+func TestAuthUseCase_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockRefreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	authUC := usecases.NewAuthUseCase(mockUserRepo,
+		usecases.WithRefreshTokenRepository(mockRefreshRepo),
+		usecases.WithAuthConfig(usecases.AuthConfig{AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour}),
+	)
+
+	ctx := context.Background()
+	now := time.Now()
+	current := func() *entities.RefreshToken {
+		return &entities.RefreshToken{
+			TokenHash:        "hash",
+			SessionID:        "s1",
+			UserID:           "user123",
+			DeviceLabel:      "laptop",
+			SessionCreatedAt: now.Add(-24 * time.Hour),
+			ExpiresAt:        now.Add(time.Hour),
+		}
+	}
+
+	t.Run("login issues a refresh token", func(t *testing.T) {
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(&entities.User{ID: "user123", PasswordHash: string(hashedPassword)}, nil)
+		mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+		mockRefreshRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *entities.RefreshToken) error {
+				assert.Equal(t, "user123", token.UserID)
+				assert.NotEmpty(t, token.SessionID)
+				return nil
+			})
+
+		token, err := authUC.Login(ctx, "mariem", "secret", entities.SessionMetadata{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), token.ExpiresAt, 5*time.Second)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), token.SessionExpiresAt, 5*time.Second)
+	})
+
+	t.Run("refresh rotates the token within the session", func(t *testing.T) {
+		mockRefreshRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(current(), nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(&entities.User{ID: "user123"}, nil)
+		mockRefreshRepo.EXPECT().
+			Rotate(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tokenHash string, next *entities.RefreshToken) error {
+				assert.NotEqual(t, tokenHash, next.TokenHash)
+				assert.Equal(t, "s1", next.SessionID)
+				return nil
+			})
+		mockUserRepo.EXPECT().
+			StoreToken(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *entities.AuthToken) error {
+				assert.Equal(t, "s1", token.ID)
+				assert.Equal(t, "laptop", token.DeviceLabel)
+				assert.Equal(t, "10.0.0.2", token.IPAddress)
+				return nil
+			})
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return([]*entities.AuthToken{
+			{Token: "stale", ID: "s1", ExpiresAt: now.Add(-time.Minute)},
+			{Token: "other", ID: "s2", ExpiresAt: now.Add(-time.Minute)},
+		}, nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "stale").Return(nil)
+
+		token, err := authUC.RefreshToken(ctx, "refresh", entities.SessionMetadata{IPAddress: "10.0.0.2"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.Token)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.NotEqual(t, "refresh", token.RefreshToken)
+	})
+
+	t.Run("reused refresh token revokes the session", func(t *testing.T) {
+		used := current()
+		used.UsedAt = now.Add(-time.Minute)
+		mockRefreshRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(used, nil)
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return([]*entities.AuthToken{
+			{Token: "tok1", ID: "s1", ExpiresAt: now.Add(time.Minute)},
+			{Token: "tok2", ID: "s2", ExpiresAt: now.Add(time.Minute)},
+		}, nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "tok1").Return(nil)
+		mockRefreshRepo.EXPECT().DeleteSession(ctx, "s1").Return(nil)
+
+		_, err := authUC.RefreshToken(ctx, "refresh", entities.SessionMetadata{})
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "REFRESH_TOKEN_REUSED", domainErr.Reason)
+	})
+
+	t.Run("concurrent reuse is caught by the rotation", func(t *testing.T) {
+		mockRefreshRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(current(), nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, "user123").Return(&entities.User{ID: "user123"}, nil)
+		mockRefreshRepo.EXPECT().Rotate(ctx, gomock.Any(), gomock.Any()).Return(repositories.ErrConflict)
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return(nil, nil)
+		mockRefreshRepo.EXPECT().DeleteSession(ctx, "s1").Return(nil)
+
+		_, err := authUC.RefreshToken(ctx, "refresh", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("expired refresh token", func(t *testing.T) {
+		expired := current()
+		expired.ExpiresAt = now.Add(-time.Minute)
+		mockRefreshRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(expired, nil)
+
+		_, err := authUC.RefreshToken(ctx, "refresh", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("unknown refresh token", func(t *testing.T) {
+		mockRefreshRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(nil, fmt.Errorf("refresh token %w", repositories.ErrNotFound))
+
+		_, err := authUC.RefreshToken(ctx, "refresh", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("logout ends the whole session", func(t *testing.T) {
+		mockUserRepo.EXPECT().ValidateToken(ctx, "tok2").Return(&entities.AuthToken{Token: "tok2", ID: "s1", UserID: "user123"}, nil)
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return([]*entities.AuthToken{
+			{Token: "tok1", ID: "s1"},
+			{Token: "tok2", ID: "s1"},
+			{Token: "tok3", ID: "s2"},
+		}, nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "tok1").Return(nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, "tok2").Return(nil)
+		mockRefreshRepo.EXPECT().DeleteSession(ctx, "s1").Return(nil)
+
+		require.NoError(t, authUC.Logout(ctx, "tok2"))
+	})
+}
+
+func TestAuthUseCase_SignedTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockRevokedRepo := mocks.NewMockRevokedSessionRepository(ctrl)
+	signer, err := usecases.NewTokenSigner(usecases.SigningKey{ID: "k1", Secret: []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+	revocations := usecases.NewRevocationList(mockRevokedRepo, usecases.DefaultRevocationListConfig())
+	authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithTokenSigner(signer, revocations))
+
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	user := &entities.User{ID: "user123", Username: "mariem", PasswordHash: string(hashedPassword)}
+
+	var stored *entities.AuthToken
+	mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+	mockUserRepo.EXPECT().
+		StoreToken(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, token *entities.AuthToken) error {
+			stored = token
+			return nil
+		})
+
+	token, err := authUC.Login(ctx, "mariem", "secret", entities.SessionMetadata{})
+	require.NoError(t, err)
+
+	t.Run("validation does not read the store", func(t *testing.T) {
+		validated, err := authUC.ValidateToken(ctx, token.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "user123", validated.ID)
+		assert.Equal(t, "mariem", validated.Username)
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		_, err := authUC.ValidateToken(ctx, token.Token+"x")
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+
+	t.Run("logout revokes the session", func(t *testing.T) {
+		mockUserRepo.EXPECT().ValidateToken(ctx, token.Token).Return(stored, nil)
+		mockUserRepo.EXPECT().ListTokens(ctx, "user123").Return([]*entities.AuthToken{stored}, nil)
+		mockUserRepo.EXPECT().DeleteToken(ctx, token.Token).Return(nil)
+		mockRevokedRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, session *entities.RevokedSession) error {
+				assert.Equal(t, stored.ID, session.SessionID)
+				assert.Equal(t, stored.ExpiresAt, session.ExpiresAt)
+				return nil
+			})
+
+		require.NoError(t, authUC.Logout(ctx, token.Token))
+
+		_, err := authUC.ValidateToken(ctx, token.Token)
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+	})
+}
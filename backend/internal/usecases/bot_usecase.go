@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+const apiKeyPrefix = "bot_"
+
+type BotUseCase interface {
+	CreateBot(ctx context.Context, admin *entities.User, username string, scopes *entities.BotScopes) (*entities.User, *entities.IssuedAPIKey, error)
+	UpdateBotScopes(ctx context.Context, admin *entities.User, botID string, scopes *entities.BotScopes) (*entities.User, error)
+	CreateBotKey(ctx context.Context, admin *entities.User, botID string) (*entities.IssuedAPIKey, error)
+	RevokeBotKey(ctx context.Context, admin *entities.User, keyID string) error
+}
+
+type BotConfig struct {
+	AdminUsernames []string
+	UsernamePolicy UsernamePolicy
+}
+
+func DefaultBotConfig() BotConfig {
+	return BotConfig{UsernamePolicy: DefaultUsernamePolicy()}
+}
+
+type botUseCase struct {
+	userRepo   repositories.UserRepository
+	apiKeyRepo repositories.APIKeyRepository
+	config     BotConfig
+}
+
+func NewBotUseCase(userRepo repositories.UserRepository, apiKeyRepo repositories.APIKeyRepository, config BotConfig) BotUseCase {
+	return &botUseCase{
+		userRepo:   userRepo,
+		apiKeyRepo: apiKeyRepo,
+		config:     config,
+	}
+}
+
+func (uc *botUseCase) CreateBot(ctx context.Context, admin *entities.User, username string, scopes *entities.BotScopes) (*entities.User, *entities.IssuedAPIKey, error) {
+	if err := uc.requireAdmin(admin); err != nil {
+		return nil, nil, err
+	}
+	username, err := uc.config.UsernamePolicy.normalize("username", username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bot := &entities.User{
+		ID:        generateID(),
+		Username:  username,
+		CreatedAt: time.Now(),
+		IsBot:     true,
+		BotScopes: scopes,
+		CreatedBy: admin.ID,
+	}
+	if err := uc.userRepo.CreateUser(ctx, bot); err != nil {
+		return nil, nil, err
+	}
+
+	key, err := uc.issueKey(ctx, admin, bot.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bot, key, nil
+}
+
+func (uc *botUseCase) UpdateBotScopes(ctx context.Context, admin *entities.User, botID string, scopes *entities.BotScopes) (*entities.User, error) {
+	if err := uc.requireAdmin(admin); err != nil {
+		return nil, err
+	}
+
+	bot, err := uc.getBot(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.userRepo.UpdateBotScopes(ctx, bot.ID, scopes); err != nil {
+		return nil, err
+	}
+
+	bot.BotScopes = scopes
+	return bot, nil
+}
+
+// CreateBotKey issues an additional key so that a bot's key can be rotated
+// without downtime: create the new key, deploy it, then revoke the old one.
+func (uc *botUseCase) CreateBotKey(ctx context.Context, admin *entities.User, botID string) (*entities.IssuedAPIKey, error) {
+	if err := uc.requireAdmin(admin); err != nil {
+		return nil, err
+	}
+	if _, err := uc.getBot(ctx, botID); err != nil {
+		return nil, err
+	}
+
+	return uc.issueKey(ctx, admin, botID)
+}
+
+func (uc *botUseCase) RevokeBotKey(ctx context.Context, admin *entities.User, keyID string) error {
+	if err := uc.requireAdmin(admin); err != nil {
+		return err
+	}
+	if _, err := uc.apiKeyRepo.GetByID(ctx, keyID); err != nil {
+		return notFound("API_KEY_NOT_FOUND", "api key not found")
+	}
+
+	return uc.apiKeyRepo.Revoke(ctx, keyID)
+}
+
+func (uc *botUseCase) issueKey(ctx context.Context, admin *entities.User, botID string) (*entities.IssuedAPIKey, error) {
+	secret := apiKeyPrefix + generateToken()
+	key := &entities.APIKey{
+		ID:        generateID(),
+		UserID:    botID,
+		KeyHash:   hashSecret(secret),
+		CreatedBy: admin.ID,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &entities.IssuedAPIKey{Key: key, Secret: secret}, nil
+}
+
+func (uc *botUseCase) getBot(ctx context.Context, botID string) (*entities.User, error) {
+	bot, err := uc.userRepo.GetUserByID(ctx, botID)
+	if err != nil || !bot.IsBot {
+		return nil, notFound("BOT_NOT_FOUND", "bot not found")
+	}
+	return bot, nil
+}
+
+func (uc *botUseCase) requireAdmin(user *entities.User) error {
+	if !isAdmin(uc.config.AdminUsernames, user) {
+		return permissionDenied("ADMIN_REQUIRED", "only admins can manage bots")
+	}
+	return nil
+}
+
+func isAdmin(adminUsernames []string, user *entities.User) bool {
+	if user.IsBot {
+		return false
+	}
+	key := entities.UsernameKey(user.Username)
+	for _, username := range adminUsernames {
+		if entities.UsernameKey(username) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// hashSecret is all that is stored for API keys and webhook tokens; the
+// secret itself is only shown once when it is issued.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,109 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotUseCase_CreateBot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	botUC := usecases.NewBotUseCase(mockUserRepo, mockKeyRepo, usecases.BotConfig{AdminUsernames: []string{"mariem"}})
+	authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithAPIKeyRepository(mockKeyRepo))
+
+	ctx := context.Background()
+	admin := &entities.User{ID: "admin1", Username: "mariem"}
+	scopes := &entities.BotScopes{RoomIDs: []string{"deploys"}}
+
+	t.Run("admin creates a bot that can authenticate with its key", func(t *testing.T) {
+		var created *entities.User
+		var stored *entities.APIKey
+		mockUserRepo.EXPECT().
+			CreateUser(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entities.User) error {
+				assert.True(t, user.IsBot)
+				assert.Empty(t, user.PasswordHash)
+				assert.Equal(t, "admin1", user.CreatedBy)
+				created = user
+				return nil
+			})
+		mockKeyRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key *entities.APIKey) error {
+				stored = key
+				return nil
+			})
+
+		bot, key, err := botUC.CreateBot(ctx, admin, "deploybot", scopes)
+		require.NoError(t, err)
+		assert.Equal(t, scopes, bot.BotScopes)
+		assert.True(t, strings.HasPrefix(key.Secret, "bot_"))
+		assert.NotEqual(t, key.Secret, stored.KeyHash)
+
+		mockKeyRepo.EXPECT().GetByHash(ctx, stored.KeyHash).Return(stored, nil)
+		mockUserRepo.EXPECT().GetUserByID(ctx, created.ID).Return(created, nil)
+		mockKeyRepo.EXPECT().TouchLastUsed(ctx, stored.KeyHash, gomock.Any()).Return(nil)
+
+		user, err := authUC.ValidateToken(ctx, key.Secret)
+		require.NoError(t, err)
+		assert.Equal(t, "deploybot", user.Username)
+	})
+
+	t.Run("admin usernames are compared like usernames", func(t *testing.T) {
+		botUC := usecases.NewBotUseCase(mockUserRepo, mockKeyRepo, usecases.BotConfig{AdminUsernames: []string{"MARIEM"}})
+		mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(assert.AnError)
+
+		_, _, err := botUC.CreateBot(ctx, admin, "deploybot", scopes)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("non admins cannot create bots", func(t *testing.T) {
+		bot, key, err := botUC.CreateBot(ctx, &entities.User{ID: "user2", Username: "bob"}, "spambot", nil)
+		require.Error(t, err)
+		assert.Nil(t, bot)
+		assert.Nil(t, key)
+	})
+
+	t.Run("revoked keys are rejected", func(t *testing.T) {
+		mockKeyRepo.EXPECT().
+			GetByHash(ctx, gomock.Any()).
+			Return(&entities.APIKey{ID: "key1", UserID: "bot1", Revoked: true}, nil)
+
+		user, err := authUC.ValidateToken(ctx, "bot_revoked")
+		require.Error(t, err)
+		assert.Nil(t, user)
+	})
+
+	t.Run("bots cannot log in with a password", func(t *testing.T) {
+		mockUserRepo.EXPECT().
+			GetUserByUsername(ctx, "deploybot").
+			Return(&entities.User{ID: "bot1", Username: "deploybot", IsBot: true}, nil)
+
+		token, err := authUC.Login(ctx, "deploybot", "anything", entities.SessionMetadata{})
+		assert.ErrorIs(t, err, usecases.ErrUnauthenticated)
+		assert.Nil(t, token)
+	})
+}
+
+func TestBotScopes_Allows(t *testing.T) {
+	scopes := &entities.BotScopes{RoomIDs: []string{"deploys"}, RPCs: []string{"SendMessage", "StreamMessages"}}
+
+	assert.True(t, scopes.Allows("SendMessage", "deploys"))
+	assert.False(t, scopes.Allows("SendMessage", "general"))
+	assert.False(t, scopes.Allows("PinMessage", "deploys"))
+
+	var unrestricted *entities.BotScopes
+	assert.True(t, unrestricted.Allows("PinMessage", "general"))
+}
@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+type CommandPermission int
+
+const (
+	CommandAnyone CommandPermission = iota
+	CommandModerator
+)
+
+type CommandInvocation struct {
+	UserID   string
+	Username string
+	RoomID   string
+	Name     string
+	Args     []string
+	// Text is everything after the command name, unparsed.
+	Text string
+}
+
+func (inv *CommandInvocation) User() *entities.User {
+	return &entities.User{ID: inv.UserID, Username: inv.Username}
+}
+
+// Reply builds a response that is returned to the invoking user only and
+// never stored or streamed to the room.
+func (inv *CommandInvocation) Reply(format string, args ...interface{}) *entities.Message {
+	return &entities.Message{
+		ID:        "ephemeral-" + generateID(),
+		Username:  "system",
+		Content:   fmt.Sprintf(format, args...),
+		RoomID:    inv.RoomID,
+		Timestamp: time.Now(),
+		Ephemeral: true,
+	}
+}
+
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	MinArgs     int
+	// MaxArgs of -1 accepts any number of arguments.
+	MaxArgs    int
+	Permission CommandPermission
+	Run        func(ctx context.Context, inv *CommandInvocation) (*entities.Message, error)
+}
+
+type CommandRegistry struct {
+	roomUseCase RoomUseCase
+
+	mu       sync.RWMutex
+	commands map[string]*Command
+}
+
+func NewCommandRegistry(roomUseCase RoomUseCase) *CommandRegistry {
+	r := &CommandRegistry{
+		roomUseCase: roomUseCase,
+		commands:    make(map[string]*Command),
+	}
+	r.Register(&Command{
+		Name:        "help",
+		Usage:       "/help [command]",
+		Description: "List the available commands",
+		MaxArgs:     1,
+		Run:         r.help,
+	})
+	return r
+}
+
+func (r *CommandRegistry) Register(cmd *Command) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands[strings.ToLower(cmd.Name)] = cmd
+}
+
+func (r *CommandRegistry) Lookup(name string) (*Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmd, ok := r.commands[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// Execute runs the command in params.Content. Mistakes by the user, such as
+// an unknown command, bad arguments or a missing permission, are answered
+// with an ephemeral reply rather than an error.
+func (r *CommandRegistry) Execute(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+	line := strings.TrimPrefix(strings.TrimSpace(params.Content), "/")
+	name, text := line, ""
+	if i := strings.IndexAny(line, " \t\n"); i >= 0 {
+		name, text = line[:i], line[i+1:]
+	}
+	inv := &CommandInvocation{
+		UserID:   params.UserID,
+		Username: params.Username,
+		RoomID:   params.RoomID,
+		Name:     strings.ToLower(name),
+		Text:     strings.TrimSpace(text),
+	}
+
+	cmd, ok := r.Lookup(inv.Name)
+	if !ok {
+		return inv.Reply("Unknown command /%s. Type /help to see the available commands.", inv.Name), nil
+	}
+
+	args, err := parseCommandArgs(inv.Text)
+	if err != nil {
+		return inv.Reply("%s. Usage: %s", err.Error(), cmd.Usage), nil
+	}
+	inv.Args = args
+	if len(args) < cmd.MinArgs || (cmd.MaxArgs >= 0 && len(args) > cmd.MaxArgs) {
+		return inv.Reply("Usage: %s", cmd.Usage), nil
+	}
+
+	if cmd.Permission == CommandModerator {
+		room, err := r.roomUseCase.GetRoom(ctx, inv.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if !room.IsModerator(inv.UserID) {
+			return inv.Reply("Only room moderators can use /%s.", cmd.Name), nil
+		}
+	}
+
+	message, err := cmd.Run(ctx, inv)
+	if err != nil {
+		log.Printf("Command /%s failed for user %s: %v", cmd.Name, inv.UserID, err)
+		// Only domain errors are meant for users; anything else may carry
+		// details of the backend.
+		var domainErr *Error
+		if errors.As(err, &domainErr) {
+			return inv.Reply("/%s failed: %s", cmd.Name, domainErr.Message), nil
+		}
+		return inv.Reply("/%s failed.", cmd.Name), nil
+	}
+	if message == nil {
+		message = inv.Reply("Done.")
+	}
+
+	return message, nil
+}
+
+func (r *CommandRegistry) help(ctx context.Context, inv *CommandInvocation) (*entities.Message, error) {
+	if len(inv.Args) == 1 {
+		cmd, ok := r.Lookup(strings.TrimPrefix(inv.Args[0], "/"))
+		if !ok {
+			return inv.Reply("Unknown command %s.", inv.Args[0]), nil
+		}
+		return inv.Reply("%s\n%s", cmd.Usage, cmd.Description), nil
+	}
+
+	r.mu.RLock()
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	lines := []string{"Available commands:"}
+	for _, name := range names {
+		cmd, _ := r.Lookup(name)
+		line := fmt.Sprintf("%s - %s", cmd.Usage, cmd.Description)
+		if cmd.Permission == CommandModerator {
+			line += " (moderators)"
+		}
+		lines = append(lines, line)
+	}
+	return inv.Reply("%s", strings.Join(lines, "\n")), nil
+}
+
+// parseCommandArgs splits on whitespace, keeping double-quoted strings
+// together so `/remind 1h "call the bank"` yields two arguments.
+func parseCommandArgs(text string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inQuotes, hasArg := false, false
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// maxCommandDuration bounds the durations commands accept.
+const maxCommandDuration = 366 * 24 * time.Hour
+
+// parseCommandDuration accepts Go durations plus a "d" suffix for days, up to
+// maxCommandDuration.
+func parseCommandDuration(value string) (time.Duration, error) {
+	scale := time.Duration(1)
+	text := value
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		scale, text = 24, days+"h"
+	}
+
+	d, err := time.ParseDuration(text)
+	if err != nil || d <= 0 {
+		return 0, invalidArgument("duration", "INVALID_DURATION", "invalid duration %q", value)
+	}
+	// Checked before scaling, so that days cannot overflow.
+	if d > maxCommandDuration/scale {
+		return 0, invalidArgument("duration", "DURATION_TOO_LONG", "duration %q is longer than %d days", value, maxCommandDuration/(24*time.Hour))
+	}
+	return d * scale, nil
+}
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	repoMocks "chat-app/backend/internal/domain/repositories/mocks"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandArgs(t *testing.T) {
+	args, err := parseCommandArgs(`1h "call the bank"  today`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1h", "call the bank", "today"}, args)
+
+	args, err = parseCommandArgs(`""`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, args)
+
+	_, err = parseCommandArgs(`"open`)
+	require.Error(t, err)
+}
+
+func TestParseCommandDuration(t *testing.T) {
+	d, err := parseCommandDuration("90m")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	d, err = parseCommandDuration("2d")
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, d)
+
+	_, err = parseCommandDuration("-5m")
+	require.Error(t, err)
+	_, err = parseCommandDuration("soon")
+	require.Error(t, err)
+
+	_, err = parseCommandDuration("366d")
+	require.NoError(t, err)
+	for _, value := range []string{"367d", "2562047h", "106751d"} {
+		_, err = parseCommandDuration(value)
+		assert.ErrorIs(t, err, ErrInvalidArgument, value)
+	}
+}
+
+func TestCommandRegistry_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoomUC := ucMocks.NewMockRoomUseCase(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	mockUserRepo := repoMocks.NewMockUserRepository(ctrl)
+	registry := NewCommandRegistry(mockRoomUC)
+	registry.Register(NewTopicCommand(mockRoomUC, mockMsgUC))
+	registry.Register(NewMuteCommand(mockRoomUC, mockUserRepo, time.Hour))
+
+	ctx := context.Background()
+	params := entities.MessageCreateParams{UserID: "mod1", Username: "mariem", RoomID: "general"}
+	room := &entities.Room{ID: "general", ModeratorIDs: []string{"mod1"}}
+
+	t.Run("help lists commands", func(t *testing.T) {
+		params := params
+		params.Content = "/help"
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.True(t, message.Ephemeral)
+		assert.Contains(t, message.Content, "/topic [new topic]")
+		assert.Contains(t, message.Content, "/mute @username [duration]")
+	})
+
+	t.Run("wrong argument count shows usage", func(t *testing.T) {
+		params := params
+		params.Content = "/mute"
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.True(t, message.Ephemeral)
+		assert.Equal(t, "Usage: /mute @username [duration]", message.Content)
+	})
+
+	t.Run("moderator only commands are refused", func(t *testing.T) {
+		params := params
+		params.UserID = "user2"
+		params.Content = "/topic new topic"
+		mockRoomUC.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.True(t, message.Ephemeral)
+		assert.Contains(t, message.Content, "Only room moderators")
+	})
+
+	t.Run("anyone can view the topic", func(t *testing.T) {
+		params := params
+		params.UserID = "user2"
+		params.Content = "/topic"
+		mockRoomUC.EXPECT().GetRoom(ctx, "general").Return(&entities.Room{ID: "general", Topic: "Launch", ModeratorIDs: []string{"mod1"}}, nil)
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.True(t, message.Ephemeral)
+		assert.Equal(t, "Topic: Launch", message.Content)
+	})
+
+	t.Run("topic change is announced", func(t *testing.T) {
+		params := params
+		params.Content = "/topic Release day"
+		mockRoomUC.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+		mockRoomUC.EXPECT().
+			UpdateRoomSettings(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entities.User, settings entities.RoomSettings) (*entities.Room, error) {
+				require.NotNil(t, settings.Topic)
+				assert.Equal(t, "Release day", *settings.Topic)
+				return &entities.Room{ID: "general", Topic: *settings.Topic}, nil
+			})
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+				return &entities.Message{ID: "msg1", Content: params.Content}, nil
+			})
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.False(t, message.Ephemeral)
+		assert.Equal(t, "* mariem changed the topic to: Release day", message.Content)
+	})
+
+	t.Run("mute resolves the username", func(t *testing.T) {
+		params := params
+		params.Content = "/mute @bob 30m"
+		mockRoomUC.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "bob").Return(&entities.User{ID: "user2", Username: "bob"}, nil)
+		mockRoomUC.EXPECT().
+			MuteUser(ctx, gomock.Any(), "general", "user2", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entities.User, roomID, targetUserID string, until time.Time) (*entities.Room, error) {
+				assert.WithinDuration(t, time.Now().Add(30*time.Minute), until, 5*time.Second)
+				return room, nil
+			})
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.True(t, message.Ephemeral)
+		assert.Contains(t, message.Content, "@bob is muted")
+	})
+
+	t.Run("command failures reply ephemerally", func(t *testing.T) {
+		params := params
+		params.Content = "/mute @bob"
+		mockRoomUC.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "bob").Return(&entities.User{ID: "user2", Username: "bob"}, nil)
+		mockRoomUC.EXPECT().MuteUser(ctx, gomock.Any(), "general", "user2", gomock.Any()).Return(nil, assert.AnError)
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.True(t, message.Ephemeral)
+		assert.Equal(t, "/mute failed.", message.Content)
+	})
+
+	t.Run("domain errors are shown to the user", func(t *testing.T) {
+		params := params
+		params.Content = "/mute @bob"
+		mockRoomUC.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "bob").Return(&entities.User{ID: "user2", Username: "bob"}, nil)
+		mockRoomUC.EXPECT().MuteUser(ctx, gomock.Any(), "general", "user2", gomock.Any()).
+			Return(nil, failedPrecondition("MODERATOR_NOT_MUTABLE", "moderators cannot be muted"))
+
+		message, err := registry.Execute(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, "/mute failed: moderators cannot be muted", message.Content)
+	})
+}
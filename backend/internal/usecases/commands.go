@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+func NewMeCommand(messageUseCase MessageUseCase) *Command {
+	return &Command{
+		Name:        "me",
+		Usage:       "/me <action>",
+		Description: "Post an action, e.g. /me waves",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run: func(ctx context.Context, inv *CommandInvocation) (*entities.Message, error) {
+			return messageUseCase.SendMessageWithParams(ctx, entities.MessageCreateParams{
+				UserID:   inv.UserID,
+				Username: inv.Username,
+				Content:  fmt.Sprintf("* %s %s", inv.Username, inv.Text),
+				RoomID:   inv.RoomID,
+			})
+		},
+	}
+}
+
+func NewTopicCommand(roomUseCase RoomUseCase, messageUseCase MessageUseCase) *Command {
+	return &Command{
+		Name:        "topic",
+		Usage:       "/topic [new topic]",
+		Description: "Show the room topic, or change it (moderators)",
+		MaxArgs:     -1,
+		Run: func(ctx context.Context, inv *CommandInvocation) (*entities.Message, error) {
+			room, err := roomUseCase.GetRoom(ctx, inv.RoomID)
+			if err != nil {
+				return nil, err
+			}
+			if inv.Text == "" {
+				if room.Topic == "" {
+					return inv.Reply("This room has no topic."), nil
+				}
+				return inv.Reply("Topic: %s", room.Topic), nil
+			}
+			if !room.IsModerator(inv.UserID) {
+				return inv.Reply("Only room moderators can change the topic."), nil
+			}
+
+			room, err = roomUseCase.UpdateRoomSettings(ctx, inv.User(), entities.RoomSettings{
+				RoomID: inv.RoomID,
+				Topic:  &inv.Text,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return messageUseCase.SendMessageWithParams(ctx, entities.MessageCreateParams{
+				UserID:   inv.UserID,
+				Username: inv.Username,
+				Content:  fmt.Sprintf("* %s changed the topic to: %s", inv.Username, room.Topic),
+				RoomID:   inv.RoomID,
+			})
+		},
+	}
+}
+
+// NewInviteCommand announces an invitation in the room. Rooms are open to
+// every signed-in user, so there is no membership to grant.
+func NewInviteCommand(userRepo repositories.UserRepository, messageUseCase MessageUseCase) *Command {
+	return &Command{
+		Name:        "invite",
+		Usage:       "/invite @username",
+		Description: "Invite a user to the room",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run: func(ctx context.Context, inv *CommandInvocation) (*entities.Message, error) {
+			target, err := userRepo.GetUserByUsername(ctx, strings.TrimPrefix(inv.Args[0], "@"))
+			if err != nil {
+				return inv.Reply("User %s not found.", inv.Args[0]), nil
+			}
+
+			return messageUseCase.SendMessageWithParams(ctx, entities.MessageCreateParams{
+				UserID:   inv.UserID,
+				Username: inv.Username,
+				Content:  fmt.Sprintf("* %s invited @%s to the room", inv.Username, target.Username),
+				RoomID:   inv.RoomID,
+			})
+		},
+	}
+}
+
+func NewMuteCommand(roomUseCase RoomUseCase, userRepo repositories.UserRepository, defaultDuration time.Duration) *Command {
+	return &Command{
+		Name:        "mute",
+		Usage:       "/mute @username [duration]",
+		Description: fmt.Sprintf("Stop a user from posting in the room (default %s)", defaultDuration),
+		MinArgs:     1,
+		MaxArgs:     2,
+		Permission:  CommandModerator,
+		Run: func(ctx context.Context, inv *CommandInvocation) (*entities.Message, error) {
+			duration := defaultDuration
+			if len(inv.Args) == 2 {
+				d, err := parseCommandDuration(inv.Args[1])
+				if err != nil {
+					return inv.Reply("%s. Usage: /mute @username [duration]", err.Error()), nil
+				}
+				duration = d
+			}
+
+			target, err := userRepo.GetUserByUsername(ctx, strings.TrimPrefix(inv.Args[0], "@"))
+			if err != nil {
+				return inv.Reply("User %s not found.", inv.Args[0]), nil
+			}
+
+			until := time.Now().Add(duration)
+			if _, err := roomUseCase.MuteUser(ctx, inv.User(), inv.RoomID, target.ID, until); err != nil {
+				return nil, err
+			}
+
+			return inv.Reply("@%s is muted until %s.", target.Username, until.Format(time.RFC3339)), nil
+		},
+	}
+}
+
+func NewUnmuteCommand(roomUseCase RoomUseCase, userRepo repositories.UserRepository) *Command {
+	return &Command{
+		Name:        "unmute",
+		Usage:       "/unmute @username",
+		Description: "Let a muted user post again",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Permission:  CommandModerator,
+		Run: func(ctx context.Context, inv *CommandInvocation) (*entities.Message, error) {
+			target, err := userRepo.GetUserByUsername(ctx, strings.TrimPrefix(inv.Args[0], "@"))
+			if err != nil {
+				return inv.Reply("User %s not found.", inv.Args[0]), nil
+			}
+
+			if _, err := roomUseCase.MuteUser(ctx, inv.User(), inv.RoomID, target.ID, time.Time{}); err != nil {
+				return nil, err
+			}
+
+			return inv.Reply("@%s can post again.", target.Username), nil
+		},
+	}
+}
+
+func NewRemindCommand(scheduledUseCase ScheduledMessageUseCase) *Command {
+	return &Command{
+		Name:        "remind",
+		Usage:       "/remind <duration> <message>",
+		Description: "Post a reminder in this room later, e.g. /remind 30m stand-up",
+		MinArgs:     2,
+		MaxArgs:     -1,
+		Run: func(ctx context.Context, inv *CommandInvocation) (*entities.Message, error) {
+			duration, err := parseCommandDuration(inv.Args[0])
+			if err != nil {
+				return inv.Reply("%s. Usage: /remind <duration> <message>", err.Error()), nil
+			}
+
+			content := fmt.Sprintf("Reminder from @%s: %s", inv.Username, strings.Join(inv.Args[1:], " "))
+			scheduled, err := scheduledUseCase.ScheduleMessage(ctx, inv.User(), inv.RoomID, content, time.Now().Add(duration), 0)
+			if err != nil {
+				return nil, err
+			}
+
+			return inv.Reply("I will post your reminder at %s.", scheduled.SendAt.Format(time.RFC3339)), nil
+		},
+	}
+}
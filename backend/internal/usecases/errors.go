@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type ErrorKind int
+
+const (
+	KindInvalidArgument ErrorKind = iota + 1
+	KindNotFound
+	KindAlreadyExists
+	KindUnauthenticated
+	KindPermissionDenied
+	KindFailedPrecondition
+	KindRateLimited
+)
+
+// Error is a failure caused by the request rather than by the server. Its
+// message is safe to show to users; transports map Kind onto their own
+// status codes and pass Reason, Field and RetryAfter on as details.
+// Any other error returned by a usecase is an internal error.
+type Error struct {
+	Kind ErrorKind
+	// Reason is a stable UPPER_SNAKE_CASE identifier clients can match on.
+	Reason  string
+	Message string
+	// Field names the offending request field of an invalid argument.
+	Field string
+	// RetryAfter is set on rate limited errors.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is makes every error of a kind match the kind's sentinel, so callers can
+// write errors.Is(err, usecases.ErrNotFound).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == "" && t.Kind == e.Kind
+}
+
+var (
+	ErrInvalidArgument    = &Error{Kind: KindInvalidArgument, Message: "invalid argument"}
+	ErrNotFound           = &Error{Kind: KindNotFound, Message: "not found"}
+	ErrAlreadyExists      = &Error{Kind: KindAlreadyExists, Message: "already exists"}
+	ErrUnauthenticated    = &Error{Kind: KindUnauthenticated, Message: "unauthenticated"}
+	ErrPermissionDenied   = &Error{Kind: KindPermissionDenied, Message: "permission denied"}
+	ErrFailedPrecondition = &Error{Kind: KindFailedPrecondition, Message: "failed precondition"}
+	ErrRateLimited        = &Error{Kind: KindRateLimited, Message: "rate limited"}
+)
+
+// AsError returns the domain error in err's chain. Repository errors for
+// missing or conflicting records are reported with their own message.
+func AsError(err error) (*Error, bool) {
+	var domainErr *Error
+	switch {
+	case err == nil:
+		return nil, false
+	case errors.As(err, &domainErr):
+		if domainErr.Message == err.Error() {
+			return domainErr, true
+		}
+		// Keep the context added by wrapping, e.g. "%w: text is required".
+		wrapped := *domainErr
+		wrapped.Message = err.Error()
+		return &wrapped, true
+	case errors.Is(err, repositories.ErrNotFound):
+		return &Error{Kind: KindNotFound, Reason: "NOT_FOUND", Message: err.Error()}, true
+	case errors.Is(err, repositories.ErrAlreadyExists):
+		return &Error{Kind: KindAlreadyExists, Reason: "ALREADY_EXISTS", Message: err.Error()}, true
+	case errors.Is(err, repositories.ErrConflict):
+		return &Error{Kind: KindFailedPrecondition, Reason: "CONFLICT", Message: err.Error()}, true
+	}
+	return nil, false
+}
+
+func invalidArgument(field, reason, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindInvalidArgument, Reason: reason, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(reason, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindNotFound, Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+func alreadyExists(reason, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindAlreadyExists, Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+func unauthenticated(reason, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindUnauthenticated, Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+func permissionDenied(reason, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindPermissionDenied, Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+func failedPrecondition(reason, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindFailedPrecondition, Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+func rateLimited(retryAfter time.Duration, reason, format string, args ...interface{}) *Error {
+	return &Error{Kind: KindRateLimited, Reason: reason, RetryAfter: retryAfter, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,43 @@
+package usecases_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"chat-app/backend/internal/domain/repositories"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsError(t *testing.T) {
+	t.Run("keeps the message of wrapped domain errors", func(t *testing.T) {
+		err := fmt.Errorf("%w: text is required", usecases.ErrInvalidWebhookPayload)
+
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, usecases.KindInvalidArgument, domainErr.Kind)
+		assert.Equal(t, "INVALID_WEBHOOK_PAYLOAD", domainErr.Reason)
+		assert.Equal(t, "invalid webhook payload: text is required", domainErr.Message)
+		assert.True(t, errors.Is(err, usecases.ErrInvalidArgument))
+		assert.False(t, errors.Is(err, usecases.ErrInvalidWebhookToken))
+	})
+
+	t.Run("maps repository errors", func(t *testing.T) {
+		domainErr, ok := usecases.AsError(fmt.Errorf("%w: poll is closed", repositories.ErrConflict))
+		require.True(t, ok)
+		assert.Equal(t, usecases.KindFailedPrecondition, domainErr.Kind)
+		assert.Equal(t, "conflict: poll is closed", domainErr.Message)
+
+		domainErr, ok = usecases.AsError(fmt.Errorf("webhook %w", repositories.ErrNotFound))
+		require.True(t, ok)
+		assert.Equal(t, usecases.KindNotFound, domainErr.Kind)
+	})
+
+	t.Run("other errors are internal", func(t *testing.T) {
+		_, ok := usecases.AsError(errors.New("connection reset"))
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,243 @@
+package usecases
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+)
+
+// thumbnailImage center-crops img to the aspect ratio of width x height and
+// scales the result to exactly that size using a box filter.
+func thumbnailImage(img image.Image, width, height int) image.Image {
+	src := img.Bounds()
+	cropW, cropH := src.Dx(), src.Dy()
+	if cropW*height > cropH*width {
+		cropW = cropH * width / height
+	} else {
+		cropH = cropW * height / width
+	}
+	if cropW < 1 {
+		cropW = 1
+	}
+	if cropH < 1 {
+		cropH = 1
+	}
+	offX := src.Min.X + (src.Dx()-cropW)/2
+	offY := src.Min.Y + (src.Dy()-cropH)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := offY + y*cropH/height
+		y1 := offY + (y+1)*cropH/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := offX + x*cropW/width
+			x1 := offX + (x+1)*cropW/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+var (
+	exifHeader   = []byte("Exif\x00\x00")
+	xmpHeader    = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	pngXMPKey    = []byte("XML:com.adobe.xmp\x00")
+)
+
+// stripJPEGExif removes location data from a JPEG: the GPS IFD inside the EXIF
+// segment is blanked (keeping orientation and other tags intact) and XMP
+// segments, which may repeat the location, are dropped. The second return
+// value reports whether anything was changed.
+func stripJPEGExif(data []byte) ([]byte, bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return data, false
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:2])
+	changed := false
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return data, false
+		}
+		marker := data[i+1]
+
+		// Markers without a length field.
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD8) {
+			out.Write(data[i : i+2])
+			i += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return data, false
+		}
+		segment := data[i:end]
+		payload := data[i+4 : end]
+
+		// Start of scan: the rest is entropy-coded image data.
+		if marker == 0xDA {
+			out.Write(data[i:])
+			return out.Bytes(), changed
+		}
+
+		if marker == 0xE1 && bytes.HasPrefix(payload, xmpHeader) {
+			changed = true
+			i = end
+			continue
+		}
+
+		if marker == 0xE1 && bytes.HasPrefix(payload, exifHeader) {
+			cleaned := append([]byte(nil), segment...)
+			if stripGPSFromTIFF(cleaned[4+len(exifHeader):]) {
+				changed = true
+			}
+			out.Write(cleaned)
+			i = end
+			continue
+		}
+
+		out.Write(segment)
+		i = end
+	}
+
+	return data, false
+}
+
+const gpsInfoTag = 0x8825
+
+var tiffTypeSizes = map[uint16]int{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+// stripGPSFromTIFF zeroes the GPS IFD of a TIFF structure in place, including
+// out-of-line values, and reports whether a GPS IFD was found.
+func stripGPSFromTIFF(tiff []byte) bool {
+	if len(tiff) < 8 {
+		return false
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return false
+	}
+
+	ifd0 := int(order.Uint32(tiff[4:8]))
+	if ifd0+2 > len(tiff) {
+		return false
+	}
+
+	count := int(order.Uint16(tiff[ifd0 : ifd0+2]))
+	gpsOffset := -1
+	for n := 0; n < count; n++ {
+		entry := ifd0 + 2 + n*12
+		if entry+12 > len(tiff) {
+			return false
+		}
+		if order.Uint16(tiff[entry:entry+2]) == gpsInfoTag {
+			gpsOffset = int(order.Uint32(tiff[entry+8 : entry+12]))
+			break
+		}
+	}
+	if gpsOffset < 0 || gpsOffset+2 > len(tiff) {
+		return false
+	}
+
+	gpsCount := int(order.Uint16(tiff[gpsOffset : gpsOffset+2]))
+	for n := 0; n < gpsCount; n++ {
+		entry := gpsOffset + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		size := tiffTypeSizes[order.Uint16(tiff[entry+2:entry+4])] * int(order.Uint32(tiff[entry+4:entry+8]))
+		if size > 4 {
+			valueOffset := int(order.Uint32(tiff[entry+8 : entry+12]))
+			if valueOffset >= 0 && valueOffset+size <= len(tiff) {
+				clear(tiff[valueOffset : valueOffset+size])
+			}
+		}
+		clear(tiff[entry : entry+12])
+	}
+	order.PutUint16(tiff[gpsOffset:gpsOffset+2], 0)
+
+	return true
+}
+
+// stripPNGMetadata drops the chunks of a PNG that may carry a location: eXIf
+// chunks and XMP packets stored in iTXt chunks. The second return value
+// reports whether anything was changed.
+func stripPNGMetadata(data []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return data, false
+	}
+
+	var out bytes.Buffer
+	out.Write(pngSignature)
+	changed := false
+
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return data, false
+		}
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return data, false
+		}
+		chunkType := string(data[i+4 : i+8])
+		payload := data[i+8 : i+8+length]
+
+		if chunkType == "eXIf" || (chunkType == "iTXt" && bytes.HasPrefix(payload, pngXMPKey)) {
+			changed = true
+			i = end
+			continue
+		}
+
+		out.Write(data[i:end])
+		i = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	if !changed {
+		return data, false
+	}
+	return out.Bytes(), true
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+var (
+	// ErrInvalidWebhookToken is returned for unknown webhooks as well as wrong
+	// tokens, so callers cannot probe which webhook IDs exist.
+	ErrInvalidWebhookToken   = unauthenticated("INVALID_WEBHOOK_TOKEN", "invalid webhook token")
+	ErrInvalidWebhookPayload = invalidArgument("payload", "INVALID_WEBHOOK_PAYLOAD", "invalid webhook payload")
+)
+
+type IncomingWebhookUseCase interface {
+	CreateIncomingWebhook(ctx context.Context, user *entities.User, roomID, displayName string) (*entities.IncomingWebhook, string, error)
+	ListIncomingWebhooks(ctx context.Context, user *entities.User, roomID string) ([]*entities.IncomingWebhook, error)
+	DeleteIncomingWebhook(ctx context.Context, user *entities.User, webhookID string) error
+	// GetIncomingWebhook returns an incoming webhook of a room that user
+	// moderates.
+	GetIncomingWebhook(ctx context.Context, user *entities.User, webhookID string) (*entities.IncomingWebhook, error)
+	Post(ctx context.Context, webhookID, token string, payload *entities.IncomingWebhookPayload) (*entities.Message, error)
+}
+
+type IncomingWebhookConfig struct {
+	DefaultDisplayName string
+	// AllowUsernameOverride lets the payload's "username" replace the
+	// webhook's display name, as Slack does.
+	AllowUsernameOverride bool
+	MaxDisplayNameLength  int
+	MaxContentLength      int
+}
+
+func DefaultIncomingWebhookConfig() IncomingWebhookConfig {
+	return IncomingWebhookConfig{
+		DefaultDisplayName:    "Webhook",
+		AllowUsernameOverride: true,
+		MaxDisplayNameLength:  50,
+		MaxContentLength:      4000,
+	}
+}
+
+type incomingWebhookUseCase struct {
+	webhookRepo    repositories.IncomingWebhookRepository
+	messageUseCase MessageUseCase
+	roomUseCase    RoomUseCase
+	config         IncomingWebhookConfig
+}
+
+func NewIncomingWebhookUseCase(webhookRepo repositories.IncomingWebhookRepository, messageUseCase MessageUseCase, roomUseCase RoomUseCase, config IncomingWebhookConfig) IncomingWebhookUseCase {
+	return &incomingWebhookUseCase{
+		webhookRepo:    webhookRepo,
+		messageUseCase: messageUseCase,
+		roomUseCase:    roomUseCase,
+		config:         config,
+	}
+}
+
+// CreateIncomingWebhook returns the webhook and its token. The token is only
+// returned here; afterwards just its hash is known.
+func (uc *incomingWebhookUseCase) CreateIncomingWebhook(ctx context.Context, user *entities.User, roomID, displayName string) (*entities.IncomingWebhook, string, error) {
+	if err := uc.requireModerator(ctx, user, roomID); err != nil {
+		return nil, "", err
+	}
+
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		displayName = uc.config.DefaultDisplayName
+	}
+	if len(displayName) > uc.config.MaxDisplayNameLength {
+		return nil, "", invalidArgument("display_name", "DISPLAY_NAME_TOO_LONG", "display name cannot be longer than %d characters", uc.config.MaxDisplayNameLength)
+	}
+
+	token := generateToken()
+	webhook := &entities.IncomingWebhook{
+		ID:          generateID(),
+		RoomID:      roomID,
+		TokenHash:   hashSecret(token),
+		DisplayName: displayName,
+		CreatedBy:   user.ID,
+		CreatedAt:   time.Now(),
+	}
+	if err := uc.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, "", err
+	}
+
+	return webhook, token, nil
+}
+
+func (uc *incomingWebhookUseCase) ListIncomingWebhooks(ctx context.Context, user *entities.User, roomID string) ([]*entities.IncomingWebhook, error) {
+	if err := uc.requireModerator(ctx, user, roomID); err != nil {
+		return nil, err
+	}
+	return uc.webhookRepo.ListByRoom(ctx, roomID)
+}
+
+func (uc *incomingWebhookUseCase) DeleteIncomingWebhook(ctx context.Context, user *entities.User, webhookID string) error {
+	webhook, err := uc.GetIncomingWebhook(ctx, user, webhookID)
+	if err != nil {
+		return err
+	}
+	return uc.webhookRepo.Delete(ctx, webhook.ID)
+}
+
+func (uc *incomingWebhookUseCase) GetIncomingWebhook(ctx context.Context, user *entities.User, webhookID string) (*entities.IncomingWebhook, error) {
+	webhook, err := uc.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, notFound("WEBHOOK_NOT_FOUND", "webhook not found")
+	}
+	if err := uc.requireModerator(ctx, user, webhook.RoomID); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Post checks the token and sends the payload as a message in the webhook's
+// room. The sender is "webhook:<id>", so mutes and history work as for users.
+func (uc *incomingWebhookUseCase) Post(ctx context.Context, webhookID, token string, payload *entities.IncomingWebhookPayload) (*entities.Message, error) {
+	webhook, err := uc.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, ErrInvalidWebhookToken
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(token)), []byte(webhook.TokenHash)) != 1 {
+		return nil, ErrInvalidWebhookToken
+	}
+
+	content := RenderIncomingWebhookPayload(payload)
+	if content == "" {
+		return nil, fmt.Errorf("%w: text or attachments are required", ErrInvalidWebhookPayload)
+	}
+	if len(content) > uc.config.MaxContentLength {
+		return nil, fmt.Errorf("%w: message cannot be longer than %d characters", ErrInvalidWebhookPayload, uc.config.MaxContentLength)
+	}
+	// Escape a leading slash so webhooks never run slash commands.
+	if strings.HasPrefix(content, "/") {
+		content = "/" + content
+	}
+
+	username := webhook.DisplayName
+	if override := strings.TrimSpace(payload.Username); uc.config.AllowUsernameOverride && override != "" {
+		if len(override) > uc.config.MaxDisplayNameLength {
+			return nil, fmt.Errorf("%w: username cannot be longer than %d characters", ErrInvalidWebhookPayload, uc.config.MaxDisplayNameLength)
+		}
+		username = override
+	}
+
+	return uc.messageUseCase.SendMessage(ctx, "webhook:"+webhook.ID, username, content, webhook.RoomID)
+}
+
+// RenderIncomingWebhookPayload flattens the text and attachments into plain
+// message content. An attachment's fallback is only used when it has nothing
+// else to show.
+func RenderIncomingWebhookPayload(payload *entities.IncomingWebhookPayload) string {
+	var parts []string
+	if text := strings.TrimSpace(payload.Text); text != "" {
+		parts = append(parts, text)
+	}
+
+	for _, attachment := range payload.Attachments {
+		var lines []string
+		if attachment.Pretext != "" {
+			lines = append(lines, attachment.Pretext)
+		}
+		switch {
+		case attachment.Title != "" && attachment.TitleLink != "":
+			lines = append(lines, fmt.Sprintf("%s (%s)", attachment.Title, attachment.TitleLink))
+		case attachment.Title != "":
+			lines = append(lines, attachment.Title)
+		}
+		if attachment.Text != "" {
+			lines = append(lines, attachment.Text)
+		}
+		for _, field := range attachment.Fields {
+			if field.Title == "" {
+				lines = append(lines, field.Value)
+			} else {
+				lines = append(lines, fmt.Sprintf("%s: %s", field.Title, field.Value))
+			}
+		}
+		if len(lines) == 0 && attachment.Fallback != "" {
+			lines = append(lines, attachment.Fallback)
+		}
+		if block := strings.TrimSpace(strings.Join(lines, "\n")); block != "" {
+			parts = append(parts, block)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func (uc *incomingWebhookUseCase) requireModerator(ctx context.Context, user *entities.User, roomID string) error {
+	room, err := uc.roomUseCase.GetRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if !room.IsModerator(user.ID) {
+		return permissionDenied("MODERATOR_REQUIRED", "only room moderators can manage webhooks")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncomingWebhookUseCase_Post(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockIncomingWebhookRepository(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	mockRoomUC := ucMocks.NewMockRoomUseCase(ctrl)
+	incomingUC := usecases.NewIncomingWebhookUseCase(mockWebhookRepo, mockMsgUC, mockRoomUC, usecases.DefaultIncomingWebhookConfig())
+
+	ctx := context.Background()
+	room := &entities.Room{ID: "room123", ModeratorIDs: []string{"mod1"}}
+
+	mockRoomUC.EXPECT().GetRoom(ctx, "room123").Return(room, nil)
+	var stored *entities.IncomingWebhook
+	mockWebhookRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, webhook *entities.IncomingWebhook) error {
+			stored = webhook
+			return nil
+		})
+
+	webhook, token, err := incomingUC.CreateIncomingWebhook(ctx, &entities.User{ID: "mod1"}, "room123", "CI")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	assert.NotEqual(t, token, webhook.TokenHash)
+	mockWebhookRepo.EXPECT().GetByID(ctx, webhook.ID).Return(stored, nil).AnyTimes()
+
+	t.Run("posts as the webhook display name", func(t *testing.T) {
+		mockMsgUC.EXPECT().
+			SendMessage(ctx, "webhook:"+webhook.ID, "CI", "Build #42 passed", "room123").
+			Return(&entities.Message{ID: "msg1"}, nil)
+
+		message, err := incomingUC.Post(ctx, webhook.ID, token, &entities.IncomingWebhookPayload{Text: "Build #42 passed"})
+		require.NoError(t, err)
+		assert.Equal(t, "msg1", message.ID)
+	})
+
+	t.Run("payload username overrides the display name", func(t *testing.T) {
+		mockMsgUC.EXPECT().
+			SendMessage(ctx, "webhook:"+webhook.ID, "alertmanager", "disk full", "room123").
+			Return(&entities.Message{ID: "msg2"}, nil)
+
+		_, err := incomingUC.Post(ctx, webhook.ID, token, &entities.IncomingWebhookPayload{Text: "disk full", Username: "alertmanager"})
+		require.NoError(t, err)
+	})
+
+	t.Run("slash commands are escaped", func(t *testing.T) {
+		mockMsgUC.EXPECT().
+			SendMessage(ctx, gomock.Any(), gomock.Any(), "//topic hacked", "room123").
+			Return(&entities.Message{ID: "msg3"}, nil)
+
+		_, err := incomingUC.Post(ctx, webhook.ID, token, &entities.IncomingWebhookPayload{Text: "/topic hacked"})
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		_, err := incomingUC.Post(ctx, webhook.ID, "wrong", &entities.IncomingWebhookPayload{Text: "hi"})
+		assert.True(t, errors.Is(err, usecases.ErrInvalidWebhookToken))
+	})
+
+	t.Run("unknown webhook", func(t *testing.T) {
+		mockWebhookRepo.EXPECT().GetByID(ctx, "missing").Return(nil, assert.AnError)
+
+		_, err := incomingUC.Post(ctx, "missing", token, &entities.IncomingWebhookPayload{Text: "hi"})
+		assert.True(t, errors.Is(err, usecases.ErrInvalidWebhookToken))
+	})
+
+	t.Run("empty payload", func(t *testing.T) {
+		_, err := incomingUC.Post(ctx, webhook.ID, token, &entities.IncomingWebhookPayload{})
+		assert.True(t, errors.Is(err, usecases.ErrInvalidWebhookPayload))
+	})
+}
+
+func TestRenderIncomingWebhookPayload(t *testing.T) {
+	content := usecases.RenderIncomingWebhookPayload(&entities.IncomingWebhookPayload{
+		Text: "Deploy finished",
+		Attachments: []entities.IncomingWebhookAttachment{
+			{
+				Fallback:  "ignored",
+				Pretext:   "production",
+				Title:     "Release 1.2",
+				TitleLink: "https://ci.example.com/1.2",
+				Fields:    []entities.IncomingWebhookField{{Title: "Duration", Value: "3m"}},
+			},
+			{Fallback: "Only a fallback"},
+		},
+	})
+
+	assert.Equal(t, "Deploy finished\n\nproduction\nRelease 1.2 (https://ci.example.com/1.2)\nDuration: 3m\n\nOnly a fallback", content)
+}
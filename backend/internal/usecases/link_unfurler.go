@@ -0,0 +1,285 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type LinkUnfurler interface {
+	Unfurl(message *entities.Message)
+}
+
+type LinkUnfurlerConfig struct {
+	Timeout           time.Duration
+	MaxBodyBytes      int64
+	MaxURLsPerMessage int
+	MaxRedirects      int
+	Concurrency       int
+	CacheTTL          time.Duration
+	CacheSize         int
+}
+
+func DefaultLinkUnfurlerConfig() LinkUnfurlerConfig {
+	return LinkUnfurlerConfig{
+		Timeout:           5 * time.Second,
+		MaxBodyBytes:      512 * 1024,
+		MaxURLsPerMessage: 3,
+		MaxRedirects:      3,
+		Concurrency:       8,
+		CacheTTL:          time.Hour,
+		CacheSize:         1000,
+	}
+}
+
+type cachedPreview struct {
+	preview   *entities.LinkPreview
+	expiresAt time.Time
+}
+
+type linkUnfurler struct {
+	messageRepo repositories.MessageRepository
+	config      LinkUnfurlerConfig
+	client      *http.Client
+	slots       chan struct{}
+
+	// allowPrivateNetworks disables the SSRF guard so tests can fetch from
+	// a local server.
+	allowPrivateNetworks bool
+
+	mu    sync.Mutex
+	cache map[string]cachedPreview
+}
+
+func NewLinkUnfurler(messageRepo repositories.MessageRepository, config LinkUnfurlerConfig) LinkUnfurler {
+	u := &linkUnfurler{
+		messageRepo: messageRepo,
+		config:      config,
+		slots:       make(chan struct{}, config.Concurrency),
+		cache:       make(map[string]cachedPreview),
+	}
+
+	u.client = newPublicHTTPClient(config.Timeout, config.MaxRedirects, func() bool {
+		return u.allowPrivateNetworks
+	})
+
+	return u
+}
+
+// Unfurl fetches previews for the URLs in message in the background and
+// stores them on the message, which surfaces as an update on the stream.
+func (u *linkUnfurler) Unfurl(message *entities.Message) {
+	urls := extractURLs(message.Content, u.config.MaxURLsPerMessage)
+	if len(urls) == 0 {
+		return
+	}
+
+	go func() {
+		u.slots <- struct{}{}
+		defer func() { <-u.slots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), u.config.Timeout*time.Duration(len(urls)+1))
+		defer cancel()
+
+		var previews []*entities.LinkPreview
+		for _, rawURL := range urls {
+			preview, err := u.preview(ctx, rawURL)
+			if err != nil {
+				log.Printf("Link preview failed for %s: %v", rawURL, err)
+				continue
+			}
+			if preview != nil {
+				previews = append(previews, preview)
+			}
+		}
+		if len(previews) == 0 {
+			return
+		}
+
+		if err := u.messageRepo.UpdateLinkPreviews(ctx, message.ID, previews); err != nil {
+			log.Printf("Error storing link previews for message %s: %v", message.ID, err)
+		}
+	}()
+}
+
+func (u *linkUnfurler) preview(ctx context.Context, rawURL string) (*entities.LinkPreview, error) {
+	u.mu.Lock()
+	cached, ok := u.cache[rawURL]
+	u.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.preview, nil
+	}
+
+	preview, err := u.fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	if len(u.cache) >= u.config.CacheSize {
+		u.evictLocked()
+	}
+	u.cache[rawURL] = cachedPreview{preview: preview, expiresAt: time.Now().Add(u.config.CacheTTL)}
+	u.mu.Unlock()
+
+	return preview, nil
+}
+
+func (u *linkUnfurler) evictLocked() {
+	now := time.Now()
+	for key, entry := range u.cache {
+		if now.After(entry.expiresAt) {
+			delete(u.cache, key)
+		}
+	}
+	for key := range u.cache {
+		if len(u.cache) < u.config.CacheSize {
+			break
+		}
+		delete(u.cache, key)
+	}
+}
+
+// fetch returns a nil preview without error for pages that have no usable
+// metadata so the negative result is cached as well.
+func (u *linkUnfurler) fetch(ctx context.Context, rawURL string) (*entities.LinkPreview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ChatAppLinkPreview/1.0")
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, nil
+	}
+
+	preview := parseLinkPreview(io.LimitReader(resp.Body, u.config.MaxBodyBytes), resp.Request.URL)
+	if preview == nil {
+		return nil, nil
+	}
+	preview.URL = rawURL
+	preview.FetchedAt = time.Now()
+	return preview, nil
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+func extractURLs(content string, max int) []string {
+	seen := make(map[string]bool)
+	var urls []string
+
+	for _, match := range urlPattern.FindAllString(content, -1) {
+		match = strings.TrimRight(match, ".,;:!?)]}")
+		parsed, err := url.Parse(match)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		if seen[match] {
+			continue
+		}
+		seen[match] = true
+		urls = append(urls, match)
+		if len(urls) == max {
+			break
+		}
+	}
+
+	return urls
+}
+
+func parseLinkPreview(body io.Reader, base *url.URL) *entities.LinkPreview {
+	preview := &entities.LinkPreview{}
+	var title, description string
+	inTitle := false
+
+	tokenizer := html.NewTokenizer(body)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return finishLinkPreview(preview, title, description, base)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = true
+			case "meta":
+				key, content := "", ""
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "property", "name":
+						key = strings.ToLower(attr.Val)
+					case "content":
+						content = strings.TrimSpace(attr.Val)
+					}
+				}
+				switch key {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image":
+					preview.ImageURL = content
+				case "og:site_name":
+					preview.SiteName = content
+				case "description":
+					description = content
+				}
+			case "body":
+				return finishLinkPreview(preview, title, description, base)
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = strings.TrimSpace(string(tokenizer.Text()))
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "title" {
+				inTitle = false
+			}
+		}
+	}
+}
+
+func finishLinkPreview(preview *entities.LinkPreview, title, description string, base *url.URL) *entities.LinkPreview {
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+	if preview.ImageURL != "" {
+		if ref, err := url.Parse(preview.ImageURL); err == nil && base != nil {
+			preview.ImageURL = base.ResolveReference(ref).String()
+		}
+	}
+	if preview.SiteName == "" && base != nil {
+		preview.SiteName = base.Hostname()
+	}
+	if preview.Title == "" && preview.Description == "" {
+		return nil
+	}
+	return preview
+}
@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	repoMocks "chat-app/backend/internal/domain/repositories/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPreviewPage = `<!DOCTYPE html>
+<html><head>
+<title>Fallback title</title>
+<meta property="og:title" content="Release notes">
+<meta property="og:description" content="Everything new this week">
+<meta property="og:image" content="/cover.png">
+<meta property="og:site_name" content="Example Blog">
+</head><body>ignored</body></html>`
+
+func TestExtractURLs(t *testing.T) {
+	content := "see https://example.com/a, and (http://example.org/b) or https://example.com/a again https://three.example https://four.example"
+
+	urls := extractURLs(content, 3)
+	assert.Equal(t, []string{"https://example.com/a", "http://example.org/b", "https://three.example"}, urls)
+	assert.Empty(t, extractURLs("no links here", 3))
+}
+
+func TestLinkUnfurler_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, testPreviewPage)
+		case "/plain":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><head><title>Just a title</title></head></html>")
+		default:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte{0, 1, 2})
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("blocks private addresses by default", func(t *testing.T) {
+		unfurler := NewLinkUnfurler(nil, DefaultLinkUnfurlerConfig()).(*linkUnfurler)
+
+		preview, err := unfurler.fetch(ctx, server.URL+"/page")
+		require.Error(t, err)
+		assert.Nil(t, preview)
+	})
+
+	unfurler := NewLinkUnfurler(nil, DefaultLinkUnfurlerConfig()).(*linkUnfurler)
+	unfurler.allowPrivateNetworks = true
+
+	t.Run("reads open graph tags", func(t *testing.T) {
+		preview, err := unfurler.fetch(ctx, server.URL+"/page")
+		require.NoError(t, err)
+		require.NotNil(t, preview)
+		assert.Equal(t, "Release notes", preview.Title)
+		assert.Equal(t, "Everything new this week", preview.Description)
+		assert.Equal(t, server.URL+"/cover.png", preview.ImageURL)
+		assert.Equal(t, "Example Blog", preview.SiteName)
+	})
+
+	t.Run("falls back to the title tag", func(t *testing.T) {
+		preview, err := unfurler.fetch(ctx, server.URL+"/plain")
+		require.NoError(t, err)
+		require.NotNil(t, preview)
+		assert.Equal(t, "Just a title", preview.Title)
+	})
+
+	t.Run("ignores non html responses", func(t *testing.T) {
+		preview, err := unfurler.fetch(ctx, server.URL+"/file.bin")
+		require.NoError(t, err)
+		assert.Nil(t, preview)
+	})
+}
+
+func TestLinkUnfurler_Unfurl(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, testPreviewPage)
+	}))
+	defer server.Close()
+
+	mockMsgRepo := repoMocks.NewMockMessageRepository(ctrl)
+	unfurler := NewLinkUnfurler(mockMsgRepo, DefaultLinkUnfurlerConfig()).(*linkUnfurler)
+	unfurler.allowPrivateNetworks = true
+
+	stored := make(chan []*entities.LinkPreview, 2)
+	mockMsgRepo.EXPECT().
+		UpdateLinkPreviews(gomock.Any(), "msg123", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, messageID string, previews []*entities.LinkPreview) error {
+			stored <- previews
+			return nil
+		}).
+		Times(2)
+
+	message := &entities.Message{ID: "msg123", Content: "look at " + server.URL + "/page"}
+
+	for i := 0; i < 2; i++ {
+		unfurler.Unfurl(message)
+
+		select {
+		case previews := <-stored:
+			require.Len(t, previews, 1)
+			assert.Equal(t, server.URL+"/page", previews[0].URL)
+			assert.Equal(t, "Release notes", previews[0].Title)
+		case <-time.After(5 * time.Second):
+			t.Fatal("link preview was not stored")
+		}
+	}
+
+	assert.Equal(t, int32(1), requests.Load(), "second unfurl should be served from cache")
+}
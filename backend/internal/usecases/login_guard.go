@@ -0,0 +1,279 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+// LoginGuard slows down password guessing. Failed logins are counted per
+// username, whether or not it exists, and per client address; past a few
+// failures each further one doubles the wait before the next try, and
+// past a threshold the username or address is locked out for a while.
+type LoginGuard interface {
+	// CheckLogin refuses a login while the username or the address has to
+	// wait.
+	CheckLogin(ctx context.Context, username, ipAddress string) error
+	LoginFailed(ctx context.Context, username, ipAddress string) error
+	LoginSucceeded(ctx context.Context, username string) error
+	// CheckRegistration counts a registration from ipAddress and refuses
+	// it once the address has registered too many accounts recently.
+	CheckRegistration(ctx context.Context, ipAddress string) error
+	// CheckPasswordReset refuses a reset request while the username or the
+	// address has to wait for a login, and otherwise counts it, refusing
+	// it once either has asked for too many resets recently.
+	CheckPasswordReset(ctx context.Context, username, ipAddress string) error
+	// Unlock lets an admin end the lockout of a username early.
+	Unlock(ctx context.Context, admin *entities.User, username string) error
+}
+
+type LoginGuardConfig struct {
+	// FreeAttempts wrong passwords for a username cost nothing. Each
+	// further one doubles the wait, from BaseDelay up to MaxDelay.
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	// LockoutThreshold wrong passwords for a username lock it for
+	// LockoutDuration, and its owner is notified.
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	// AddressLockoutThreshold wrong passwords from one address lock the
+	// address out of every username for LockoutDuration.
+	AddressLockoutThreshold int
+	// Window is how long failures are remembered for, counted from the
+	// first of them.
+	Window                  time.Duration
+	RegistrationsPerAddress int
+	RegistrationWindow      time.Duration
+	// PasswordResetsPerUsername and PasswordResetsPerAddress bound the
+	// reset emails sent within PasswordResetWindow.
+	PasswordResetsPerUsername int
+	PasswordResetsPerAddress  int
+	PasswordResetWindow       time.Duration
+	AdminUsernames            []string
+}
+
+func DefaultLoginGuardConfig() LoginGuardConfig {
+	return LoginGuardConfig{
+		FreeAttempts:              3,
+		BaseDelay:                 time.Second,
+		MaxDelay:                  5 * time.Minute,
+		LockoutThreshold:          10,
+		LockoutDuration:           15 * time.Minute,
+		AddressLockoutThreshold:   100,
+		Window:                    time.Hour,
+		RegistrationsPerAddress:   10,
+		RegistrationWindow:        time.Hour,
+		PasswordResetsPerUsername: 3,
+		PasswordResetsPerAddress:  20,
+		PasswordResetWindow:       time.Hour,
+	}
+}
+
+type loginGuard struct {
+	attemptRepo repositories.LoginAttemptRepository
+	userRepo    repositories.UserRepository
+	notifier    Notifier
+	config      LoginGuardConfig
+}
+
+// NewLoginGuard returns a guard that tells users about lockouts through
+// notifier, which may be nil.
+func NewLoginGuard(attemptRepo repositories.LoginAttemptRepository, userRepo repositories.UserRepository, notifier Notifier, config LoginGuardConfig) LoginGuard {
+	return &loginGuard{
+		attemptRepo: attemptRepo,
+		userRepo:    userRepo,
+		notifier:    notifier,
+		config:      config,
+	}
+}
+
+func (g *loginGuard) CheckLogin(ctx context.Context, username, ipAddress string) error {
+	now := time.Now()
+
+	attempts, err := g.attempts(ctx, usernameKey(username))
+	if err != nil {
+		return err
+	}
+	if until, locked := g.usernameBlockedUntil(attempts); now.Before(until) {
+		retryAfter := until.Sub(now)
+		if locked {
+			return rateLimited(retryAfter, "ACCOUNT_LOCKED", "too many failed logins, account is locked for %s", retryAfter.Round(time.Second))
+		}
+		return rateLimited(retryAfter, "LOGIN_THROTTLED", "too many failed logins, try again in %s", retryAfter.Round(time.Second))
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+	attempts, err = g.attempts(ctx, addressKey(ipAddress))
+	if err != nil {
+		return err
+	}
+	if until := g.addressBlockedUntil(attempts); now.Before(until) {
+		retryAfter := until.Sub(now)
+		return rateLimited(retryAfter, "ADDRESS_LOCKED", "too many failed logins from your address, try again in %s", retryAfter.Round(time.Second))
+	}
+	return nil
+}
+
+func (g *loginGuard) LoginFailed(ctx context.Context, username, ipAddress string) error {
+	now := time.Now()
+
+	attempts, err := g.attemptRepo.RecordFailure(ctx, usernameKey(username), now, g.config.Window)
+	if err != nil {
+		return err
+	}
+	if attempts.Failures == g.config.LockoutThreshold {
+		log.Printf("Username %q is locked after %d failed logins", username, attempts.Failures)
+		g.notifyLockout(ctx, username, now.Add(g.config.LockoutDuration))
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+	attempts, err = g.attemptRepo.RecordFailure(ctx, addressKey(ipAddress), now, g.config.Window)
+	if err != nil {
+		return err
+	}
+	if attempts.Failures == g.config.AddressLockoutThreshold {
+		log.Printf("Address %s is locked after %d failed logins", ipAddress, attempts.Failures)
+	}
+	return nil
+}
+
+func (g *loginGuard) LoginSucceeded(ctx context.Context, username string) error {
+	return g.attemptRepo.Reset(ctx, usernameKey(username))
+}
+
+func (g *loginGuard) CheckRegistration(ctx context.Context, ipAddress string) error {
+	if ipAddress == "" {
+		return nil
+	}
+
+	now := time.Now()
+	attempts, err := g.attemptRepo.RecordFailure(ctx, registrationKey(ipAddress), now, g.config.RegistrationWindow)
+	if err != nil {
+		return err
+	}
+	if attempts.Failures > g.config.RegistrationsPerAddress {
+		retryAfter := attempts.FirstFailureAt.Add(g.config.RegistrationWindow).Sub(now)
+		return rateLimited(retryAfter, "TOO_MANY_REGISTRATIONS", "too many accounts were registered from your address, try again in %s", retryAfter.Round(time.Second))
+	}
+	return nil
+}
+
+func (g *loginGuard) CheckPasswordReset(ctx context.Context, username, ipAddress string) error {
+	if err := g.CheckLogin(ctx, username, ipAddress); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	attempts, err := g.attemptRepo.RecordFailure(ctx, passwordResetKey(username), now, g.config.PasswordResetWindow)
+	if err != nil {
+		return err
+	}
+	if attempts.Failures > g.config.PasswordResetsPerUsername {
+		retryAfter := attempts.FirstFailureAt.Add(g.config.PasswordResetWindow).Sub(now)
+		return rateLimited(retryAfter, "TOO_MANY_PASSWORD_RESETS", "too many password resets were requested for this account, try again in %s", retryAfter.Round(time.Second))
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+	attempts, err = g.attemptRepo.RecordFailure(ctx, passwordResetAddressKey(ipAddress), now, g.config.PasswordResetWindow)
+	if err != nil {
+		return err
+	}
+	if attempts.Failures > g.config.PasswordResetsPerAddress {
+		retryAfter := attempts.FirstFailureAt.Add(g.config.PasswordResetWindow).Sub(now)
+		return rateLimited(retryAfter, "TOO_MANY_PASSWORD_RESETS", "too many password resets were requested from your address, try again in %s", retryAfter.Round(time.Second))
+	}
+	return nil
+}
+
+func (g *loginGuard) Unlock(ctx context.Context, admin *entities.User, username string) error {
+	if !isAdmin(g.config.AdminUsernames, admin) {
+		return permissionDenied("ADMIN_REQUIRED", "only admins can unlock accounts")
+	}
+	if _, err := g.userRepo.GetUserByUsername(ctx, username); err != nil {
+		return err
+	}
+
+	log.Printf("Admin %s unlocked username %q", admin.ID, username)
+	return g.attemptRepo.Reset(ctx, usernameKey(username))
+}
+
+func (g *loginGuard) attempts(ctx context.Context, key string) (*entities.LoginAttempts, error) {
+	attempts, err := g.attemptRepo.Get(ctx, key)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return &entities.LoginAttempts{Key: key}, nil
+	}
+	return attempts, err
+}
+
+// usernameBlockedUntil returns when the username may be tried again, and
+// whether it is locked out rather than backing off.
+func (g *loginGuard) usernameBlockedUntil(attempts *entities.LoginAttempts) (time.Time, bool) {
+	if attempts.Failures >= g.config.LockoutThreshold {
+		return attempts.LastFailureAt.Add(g.config.LockoutDuration), true
+	}
+	if attempts.Failures < g.config.FreeAttempts {
+		return time.Time{}, false
+	}
+
+	delay := g.config.BaseDelay
+	for i := g.config.FreeAttempts; i < attempts.Failures && delay < g.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > g.config.MaxDelay {
+		delay = g.config.MaxDelay
+	}
+	return attempts.LastFailureAt.Add(delay), false
+}
+
+func (g *loginGuard) addressBlockedUntil(attempts *entities.LoginAttempts) time.Time {
+	if attempts.Failures >= g.config.AddressLockoutThreshold {
+		return attempts.LastFailureAt.Add(g.config.LockoutDuration)
+	}
+	return time.Time{}
+}
+
+func (g *loginGuard) notifyLockout(ctx context.Context, username string, lockedUntil time.Time) {
+	if g.notifier == nil {
+		return
+	}
+	user, err := g.userRepo.GetUserByUsername(ctx, username)
+	if err != nil || user.IsBot || user.Email == "" {
+		return
+	}
+	if err := g.notifier.SendAccountLocked(ctx, user, lockedUntil); err != nil {
+		log.Printf("Error notifying user %s of lockout: %v", user.ID, err)
+	}
+}
+
+// Attempts are stored by hash, so that the store does not keep a list of
+// client addresses or of usernames that were tried.
+func usernameKey(username string) string {
+	return hashSecret("username:" + entities.UsernameKey(username))
+}
+
+func addressKey(ipAddress string) string {
+	return hashSecret("address:" + ipAddress)
+}
+
+func registrationKey(ipAddress string) string {
+	return hashSecret("registration:" + ipAddress)
+}
+
+func passwordResetKey(username string) string {
+	return hashSecret("password_reset:" + entities.UsernameKey(username))
+}
+
+func passwordResetAddressKey(ipAddress string) string {
+	return hashSecret("password_reset_address:" + ipAddress)
+}
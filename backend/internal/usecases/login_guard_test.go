@@ -0,0 +1,233 @@
+package usecases_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginGuard_CheckLogin(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	none := fmt.Errorf("login attempts %w", repositories.ErrNotFound)
+
+	tests := []struct {
+		name           string
+		username       *entities.LoginAttempts
+		address        *entities.LoginAttempts
+		wantReason     string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name: "no failures",
+		},
+		{
+			name:     "free attempts",
+			username: &entities.LoginAttempts{Failures: 2, LastFailureAt: now},
+		},
+		{
+			name:           "backing off",
+			username:       &entities.LoginAttempts{Failures: 5, LastFailureAt: now},
+			wantReason:     "LOGIN_THROTTLED",
+			wantRetryAfter: 4 * time.Second,
+		},
+		{
+			name:     "backoff over",
+			username: &entities.LoginAttempts{Failures: 5, LastFailureAt: now.Add(-5 * time.Second)},
+		},
+		{
+			name:           "backoff doubles with every failure",
+			username:       &entities.LoginAttempts{Failures: 9, LastFailureAt: now},
+			wantReason:     "LOGIN_THROTTLED",
+			wantRetryAfter: 64 * time.Second,
+		},
+		{
+			name:           "locked out",
+			username:       &entities.LoginAttempts{Failures: 10, LastFailureAt: now.Add(-5 * time.Minute)},
+			wantReason:     "ACCOUNT_LOCKED",
+			wantRetryAfter: 10 * time.Minute,
+		},
+		{
+			name:           "address locked out",
+			address:        &entities.LoginAttempts{Failures: 100, LastFailureAt: now},
+			wantReason:     "ADDRESS_LOCKED",
+			wantRetryAfter: 15 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAttemptRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+			guard := usecases.NewLoginGuard(mockAttemptRepo, mocks.NewMockUserRepository(ctrl), nil, usecases.DefaultLoginGuardConfig())
+
+			// The username is checked first, and the address only if the
+			// username may be tried.
+			if tt.username != nil {
+				mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(tt.username, nil)
+			} else {
+				mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(nil, none)
+			}
+			if tt.address != nil {
+				mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(tt.address, nil).MaxTimes(1)
+			} else {
+				mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(nil, none).MaxTimes(1)
+			}
+
+			err := guard.CheckLogin(ctx, "mariem", "10.0.0.1")
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			domainErr, ok := usecases.AsError(err)
+			require.True(t, ok)
+			assert.ErrorIs(t, err, usecases.ErrRateLimited)
+			assert.Equal(t, tt.wantReason, domainErr.Reason)
+			assert.InDelta(t, tt.wantRetryAfter.Seconds(), domainErr.RetryAfter.Seconds(), 1)
+		})
+	}
+}
+
+func TestLoginGuard_LoginFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockNotifier := ucMocks.NewMockNotifier(ctrl)
+	config := usecases.DefaultLoginGuardConfig()
+	guard := usecases.NewLoginGuard(mockAttemptRepo, mockUserRepo, mockNotifier, config)
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem", Email: "mariem@example.com"}
+
+	t.Run("counts the username and the address", func(t *testing.T) {
+		mockAttemptRepo.EXPECT().RecordFailure(ctx, gomock.Any(), gomock.Any(), config.Window).Return(&entities.LoginAttempts{Failures: 1}, nil).Times(2)
+
+		assert.NoError(t, guard.LoginFailed(ctx, "mariem", "10.0.0.1"))
+	})
+
+	t.Run("the owner hears about the lockout once", func(t *testing.T) {
+		mockAttemptRepo.EXPECT().RecordFailure(ctx, gomock.Any(), gomock.Any(), config.Window).Return(&entities.LoginAttempts{Failures: config.LockoutThreshold}, nil)
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockNotifier.EXPECT().
+			SendAccountLocked(ctx, user, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entities.User, lockedUntil time.Time) error {
+				assert.WithinDuration(t, time.Now().Add(config.LockoutDuration), lockedUntil, time.Second)
+				return nil
+			})
+
+		assert.NoError(t, guard.LoginFailed(ctx, "mariem", ""))
+
+		mockAttemptRepo.EXPECT().RecordFailure(ctx, gomock.Any(), gomock.Any(), config.Window).Return(&entities.LoginAttempts{Failures: config.LockoutThreshold + 1}, nil)
+		assert.NoError(t, guard.LoginFailed(ctx, "mariem", ""))
+	})
+}
+
+func TestLoginGuard_CheckRegistration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+	config := usecases.DefaultLoginGuardConfig()
+	guard := usecases.NewLoginGuard(mockAttemptRepo, mocks.NewMockUserRepository(ctrl), nil, config)
+
+	ctx := context.Background()
+	first := time.Now().Add(-10 * time.Minute)
+
+	mockAttemptRepo.EXPECT().
+		RecordFailure(ctx, gomock.Any(), gomock.Any(), config.RegistrationWindow).
+		Return(&entities.LoginAttempts{Failures: config.RegistrationsPerAddress, FirstFailureAt: first}, nil)
+	assert.NoError(t, guard.CheckRegistration(ctx, "10.0.0.1"))
+
+	mockAttemptRepo.EXPECT().
+		RecordFailure(ctx, gomock.Any(), gomock.Any(), config.RegistrationWindow).
+		Return(&entities.LoginAttempts{Failures: config.RegistrationsPerAddress + 1, FirstFailureAt: first}, nil)
+	err := guard.CheckRegistration(ctx, "10.0.0.1")
+	domainErr, ok := usecases.AsError(err)
+	require.True(t, ok)
+	assert.Equal(t, "TOO_MANY_REGISTRATIONS", domainErr.Reason)
+	assert.InDelta(t, (50 * time.Minute).Seconds(), domainErr.RetryAfter.Seconds(), 1)
+
+	assert.NoError(t, guard.CheckRegistration(ctx, ""), "clients without an address are not counted")
+}
+
+func TestLoginGuard_CheckPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+	config := usecases.DefaultLoginGuardConfig()
+	guard := usecases.NewLoginGuard(mockAttemptRepo, mocks.NewMockUserRepository(ctrl), nil, config)
+
+	ctx := context.Background()
+	first := time.Now().Add(-10 * time.Minute)
+	notFound := fmt.Errorf("login attempts %w", repositories.ErrNotFound)
+
+	t.Run("counts the username and the address", func(t *testing.T) {
+		mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(nil, notFound).Times(2)
+		mockAttemptRepo.EXPECT().
+			RecordFailure(ctx, gomock.Any(), gomock.Any(), config.PasswordResetWindow).
+			Return(&entities.LoginAttempts{Failures: 1, FirstFailureAt: first}, nil).
+			Times(2)
+
+		assert.NoError(t, guard.CheckPasswordReset(ctx, "mariem", "10.0.0.1"))
+	})
+
+	t.Run("too many requests for a username", func(t *testing.T) {
+		mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(nil, notFound).Times(2)
+		mockAttemptRepo.EXPECT().
+			RecordFailure(ctx, gomock.Any(), gomock.Any(), config.PasswordResetWindow).
+			Return(&entities.LoginAttempts{Failures: config.PasswordResetsPerUsername + 1, FirstFailureAt: first}, nil)
+
+		err := guard.CheckPasswordReset(ctx, "mariem", "10.0.0.1")
+		domainErr, ok := usecases.AsError(err)
+		require.True(t, ok)
+		assert.Equal(t, "TOO_MANY_PASSWORD_RESETS", domainErr.Reason)
+		assert.InDelta(t, (50 * time.Minute).Seconds(), domainErr.RetryAfter.Seconds(), 1)
+	})
+
+	t.Run("locked out addresses cannot ask", func(t *testing.T) {
+		mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(nil, notFound)
+		mockAttemptRepo.EXPECT().Get(ctx, gomock.Any()).Return(&entities.LoginAttempts{
+			Failures:      config.AddressLockoutThreshold,
+			LastFailureAt: time.Now(),
+		}, nil)
+
+		err := guard.CheckPasswordReset(ctx, "mariem", "10.0.0.1")
+		assert.ErrorIs(t, err, usecases.ErrRateLimited)
+	})
+}
+
+func TestLoginGuard_Unlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	config := usecases.DefaultLoginGuardConfig()
+	config.AdminUsernames = []string{"admin"}
+	guard := usecases.NewLoginGuard(mockAttemptRepo, mockUserRepo, nil, config)
+
+	ctx := context.Background()
+
+	mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(&entities.User{ID: "user123"}, nil)
+	mockAttemptRepo.EXPECT().Reset(ctx, gomock.Any()).Return(nil)
+	assert.NoError(t, guard.Unlock(ctx, &entities.User{ID: "admin1", Username: "admin"}, "mariem"))
+
+	err := guard.Unlock(ctx, &entities.User{ID: "user123", Username: "mariem"}, "mariem")
+	assert.ErrorIs(t, err, usecases.ErrPermissionDenied)
+}
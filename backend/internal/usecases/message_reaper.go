@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type MessageReaper interface {
+	Start(ctx context.Context)
+	Reap(ctx context.Context) (int, error)
+}
+
+type MessageReaperConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func DefaultMessageReaperConfig() MessageReaperConfig {
+	return MessageReaperConfig{
+		Interval:  time.Minute,
+		BatchSize: 200,
+	}
+}
+
+type messageReaper struct {
+	messageRepo repositories.MessageRepository
+	blobRepo    repositories.BlobRepository
+	config      MessageReaperConfig
+}
+
+func NewMessageReaper(messageRepo repositories.MessageRepository, blobRepo repositories.BlobRepository, config MessageReaperConfig) MessageReaper {
+	return &messageReaper{
+		messageRepo: messageRepo,
+		blobRepo:    blobRepo,
+		config:      config,
+	}
+}
+
+func (r *messageReaper) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.Reap(ctx); err != nil {
+					log.Printf("Error reaping expired messages: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Reap deletes expired messages in batches until none are left, along with
+// their attachments, thumbnails and poll votes. Those go first, so a failed
+// pass leaves the message to be reaped again rather than orphaning them.
+func (r *messageReaper) Reap(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		messages, err := r.messageRepo.ListExpired(ctx, time.Now(), r.config.BatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		var messageIDs, blobIDs []string
+		for _, message := range messages {
+			messageIDs = append(messageIDs, message.ID)
+			for _, attachment := range message.Attachments {
+				blobIDs = append(blobIDs, attachment.ID)
+				if attachment.ThumbnailID != "" {
+					blobIDs = append(blobIDs, attachment.ThumbnailID)
+				}
+			}
+			if message.Poll != nil {
+				if err := r.messageRepo.DeleteVotes(ctx, message.ID); err != nil {
+					return total, err
+				}
+			}
+		}
+		if len(blobIDs) > 0 {
+			if err := r.blobRepo.Delete(ctx, blobIDs); err != nil {
+				return total, err
+			}
+		}
+		if len(messageIDs) > 0 {
+			if err := r.messageRepo.DeleteMessages(ctx, messageIDs); err != nil {
+				return total, err
+			}
+		}
+		total += len(messageIDs)
+
+		if len(messages) < r.config.BatchSize {
+			if total > 0 {
+				log.Printf("Reaped %d expired messages", total)
+			}
+			return total, nil
+		}
+	}
+}
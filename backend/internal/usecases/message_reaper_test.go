@@ -0,0 +1,77 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageReaper_Reap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	mockBlobRepo := mocks.NewMockBlobRepository(ctrl)
+	reaper := usecases.NewMessageReaper(mockMsgRepo, mockBlobRepo, usecases.MessageReaperConfig{BatchSize: 2})
+
+	ctx := context.Background()
+
+	t.Run("deletes in batches until drained", func(t *testing.T) {
+		gomock.InOrder(
+			mockMsgRepo.EXPECT().ListExpired(ctx, gomock.Any(), 2).Return([]*entities.Message{{ID: "msg1"}, {ID: "msg2"}}, nil),
+			mockMsgRepo.EXPECT().DeleteMessages(ctx, []string{"msg1", "msg2"}).Return(nil),
+			mockMsgRepo.EXPECT().ListExpired(ctx, gomock.Any(), 2).Return([]*entities.Message{{ID: "msg3"}}, nil),
+			mockMsgRepo.EXPECT().DeleteMessages(ctx, []string{"msg3"}).Return(nil),
+		)
+
+		deleted, err := reaper.Reap(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, deleted)
+	})
+
+	t.Run("attachments, thumbnails and votes go with the message", func(t *testing.T) {
+		gomock.InOrder(
+			mockMsgRepo.EXPECT().ListExpired(ctx, gomock.Any(), 2).Return([]*entities.Message{
+				{ID: "msg1", Attachments: []*entities.Attachment{
+					{ID: "att1", ThumbnailID: "att1_thumb"},
+					{ID: "att2"},
+				}},
+				{ID: "poll1", Poll: &entities.Poll{Question: "Lunch?"}},
+			}, nil),
+			mockMsgRepo.EXPECT().DeleteVotes(ctx, "poll1").Return(nil),
+			mockBlobRepo.EXPECT().Delete(ctx, []string{"att1", "att1_thumb", "att2"}).Return(nil),
+			mockMsgRepo.EXPECT().DeleteMessages(ctx, []string{"msg1", "poll1"}).Return(nil),
+			mockMsgRepo.EXPECT().ListExpired(ctx, gomock.Any(), 2).Return(nil, nil),
+		)
+
+		deleted, err := reaper.Reap(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+	})
+
+	t.Run("messages stay when their blobs cannot be deleted", func(t *testing.T) {
+		mockMsgRepo.EXPECT().ListExpired(ctx, gomock.Any(), 2).Return([]*entities.Message{
+			{ID: "msg1", Attachments: []*entities.Attachment{{ID: "att1"}}},
+		}, nil)
+		mockBlobRepo.EXPECT().Delete(ctx, []string{"att1"}).Return(assert.AnError)
+
+		deleted, err := reaper.Reap(ctx)
+		require.Error(t, err)
+		assert.Equal(t, 0, deleted)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockMsgRepo.EXPECT().ListExpired(ctx, gomock.Any(), 2).Return(nil, assert.AnError)
+
+		deleted, err := reaper.Reap(ctx)
+		require.Error(t, err)
+		assert.Equal(t, 0, deleted)
+	})
+}
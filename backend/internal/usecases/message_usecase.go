@@ -4,53 +4,167 @@ import (
 	"chat-app/backend/internal/domain/entities"
 	"chat-app/backend/internal/domain/repositories"
 	"context"
-	"fmt"
+	"strings"
+	"time"
 )
 
 type MessageUseCase interface {
 	SendMessage(ctx context.Context, userID, username, content, roomID string) (*entities.Message, error)
+	SendMessageWithParams(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error)
 	GetMessageHistory(ctx context.Context, roomID string, limit int) ([]*entities.Message, error)
 	StreamMessages(ctx context.Context, roomID string) (<-chan *entities.Message, error)
 }
 
 type messageUseCase struct {
-	messageRepo repositories.MessageRepository
-	authUseCase AuthUseCase
+	messageRepo  repositories.MessageRepository
+	authUseCase  AuthUseCase
+	linkUnfurler LinkUnfurler
+	roomUseCase  RoomUseCase
+	commands     *CommandRegistry
 }
 
-func NewMessageUseCase(messageRepo repositories.MessageRepository, authUseCase AuthUseCase) MessageUseCase {
-	return &messageUseCase{
+type MessageUseCaseOption func(*messageUseCase)
+
+func WithLinkUnfurler(linkUnfurler LinkUnfurler) MessageUseCaseOption {
+	return func(uc *messageUseCase) {
+		uc.linkUnfurler = linkUnfurler
+	}
+}
+
+func WithRoomUseCase(roomUseCase RoomUseCase) MessageUseCaseOption {
+	return func(uc *messageUseCase) {
+		uc.roomUseCase = roomUseCase
+	}
+}
+
+// WithCommandRegistry makes messages starting with "/" run as slash
+// commands instead of being posted.
+func WithCommandRegistry(commands *CommandRegistry) MessageUseCaseOption {
+	return func(uc *messageUseCase) {
+		uc.commands = commands
+	}
+}
+
+func NewMessageUseCase(messageRepo repositories.MessageRepository, authUseCase AuthUseCase, opts ...MessageUseCaseOption) MessageUseCase {
+	uc := &messageUseCase{
 		messageRepo: messageRepo,
 		authUseCase: authUseCase,
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 func (uc *messageUseCase) SendMessage(ctx context.Context, userID, username, content, roomID string) (*entities.Message, error) {
-	message := &entities.Message{
+	return uc.SendMessageWithParams(ctx, entities.MessageCreateParams{
 		UserID:   userID,
 		Username: username,
 		Content:  content,
 		RoomID:   roomID,
+	})
+}
+
+func (uc *messageUseCase) SendMessageWithParams(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+	if params.TTL < 0 {
+		return nil, invalidArgument("ttl_seconds", "NEGATIVE_TTL", "message ttl cannot be negative")
+	}
+
+	if params.Poll == nil && strings.HasPrefix(params.Content, "/") {
+		if strings.HasPrefix(params.Content, "//") {
+			params.Content = params.Content[1:]
+		} else if uc.commands != nil {
+			if params.Unverified {
+				return nil, unauthenticated("TOKEN_REQUIRED", "slash commands need a session token")
+			}
+			return uc.commands.Execute(ctx, params)
+		}
+	}
+
+	message := &entities.Message{
+		UserID:   params.UserID,
+		Username: params.Username,
+		Content:  params.Content,
+		RoomID:   params.RoomID,
+		Poll:     params.Poll,
+		IsBot:    params.IsBot,
 	}
 
-	return uc.messageRepo.Create(ctx, message)
+	ttl := params.TTL
+	if uc.roomUseCase != nil {
+		room, err := uc.roomUseCase.GetRoom(ctx, params.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if params.Unverified && (len(room.ModeratorIDs) > 0 || len(room.MutedUntil) > 0) {
+			return nil, unauthenticated("TOKEN_REQUIRED", "this room only accepts messages sent with a session token")
+		}
+		if room.IsMuted(params.UserID, time.Now()) {
+			return nil, permissionDenied("MUTED", "you are muted in this room until %s", room.MutedUntil[params.UserID].Format(time.RFC3339))
+		}
+		if ttl == 0 {
+			ttl = room.DefaultMessageTTL()
+		}
+	}
+	if ttl > 0 {
+		message.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	message, err := uc.messageRepo.Create(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.linkUnfurler != nil {
+		uc.linkUnfurler.Unfurl(message)
+	}
+
+	return message, nil
 }
 
 func (uc *messageUseCase) GetMessageHistory(ctx context.Context, roomID string, limit int) ([]*entities.Message, error) {
 	if limit <= 0 {
 		limit = 50
 	}
-	return uc.messageRepo.GetByRoomID(ctx, roomID, limit)
+
+	messages, err := uc.messageRepo.GetByRoomID(ctx, roomID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutExpired(messages, time.Now()), nil
 }
 
+// StreamMessages drops expired messages the reaper has not deleted yet; their
+// removal is still forwarded as an expired event.
 func (uc *messageUseCase) StreamMessages(ctx context.Context, roomID string) (<-chan *entities.Message, error) {
-	return uc.messageRepo.StreamByRoomID(ctx, roomID)
+	source, err := uc.messageRepo.StreamByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	messageChan := make(chan *entities.Message)
+	go func() {
+		defer close(messageChan)
+		for message := range source {
+			if message.Event != entities.MessageExpired && message.IsExpired(time.Now()) {
+				continue
+			}
+			select {
+			case messageChan <- message:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return messageChan, nil
 }
 
 func (uc *messageUseCase) SendMessageWithAuth(ctx context.Context, token, content, roomID string) (*entities.Message, error) {
 	user, err := uc.authUseCase.ValidateToken(ctx, token)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized: %v", err)
+		return nil, err
 	}
 
 	return uc.SendMessage(ctx, user.ID, user.Username, content, roomID)
@@ -59,8 +173,18 @@ func (uc *messageUseCase) SendMessageWithAuth(ctx context.Context, token, conten
 func (uc *messageUseCase) GetMessageHistoryWithAuth(ctx context.Context, token, roomID string, limit int) ([]*entities.Message, error) {
 	_, err := uc.authUseCase.ValidateToken(ctx, token)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized: %v", err)
+		return nil, err
 	}
 
 	return uc.GetMessageHistory(ctx, roomID, limit)
 }
+
+func withoutExpired(messages []*entities.Message, now time.Time) []*entities.Message {
+	filtered := messages[:0]
+	for _, message := range messages {
+		if !message.IsExpired(now) {
+			filtered = append(filtered, message)
+		}
+	}
+	return filtered
+}
@@ -55,6 +55,174 @@ func TestMessageUseCase_SendMessage(t *testing.T) {
 		require.Error(t, err)
 		assert.Nil(t, message)
 	})
+
+	t.Run("hands stored message to the link unfurler", func(t *testing.T) {
+		mockUnfurler := ucMocks.NewMockLinkUnfurler(ctrl)
+		unfurlingUC := NewMessageUseCase(mockMsgRepo, mockAuthUC, WithLinkUnfurler(mockUnfurler))
+
+		stored := &entities.Message{ID: "msg456", Content: "see https://example.com"}
+		mockMsgRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			Return(stored, nil)
+		mockUnfurler.EXPECT().Unfurl(stored)
+
+		message, err := unfurlingUC.SendMessage(ctx, userID, username, stored.Content, roomID)
+		require.NoError(t, err)
+		assert.Equal(t, "msg456", message.ID)
+	})
+}
+
+func TestMessageUseCase_SendMessageWithParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgRepo := repoMocks.NewMockMessageRepository(ctrl)
+	mockAuthUC := ucMocks.NewMockAuthUseCase(ctrl)
+	mockRoomUC := ucMocks.NewMockRoomUseCase(ctrl)
+	msgUC := NewMessageUseCase(mockMsgRepo, mockAuthUC, WithRoomUseCase(mockRoomUC))
+
+	ctx := context.Background()
+	params := entities.MessageCreateParams{
+		UserID:   "user123",
+		Username: "testuser",
+		Content:  "this will self-destruct",
+		RoomID:   "room123",
+	}
+
+	t.Run("explicit ttl sets expiry", func(t *testing.T) {
+		withTTL := params
+		withTTL.TTL = time.Minute
+
+		mockRoomUC.EXPECT().
+			GetRoom(ctx, "room123").
+			Return(&entities.Room{ID: "room123", DefaultTTLSeconds: 3600}, nil)
+		mockMsgRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg *entities.Message) (*entities.Message, error) {
+				assert.WithinDuration(t, time.Now().Add(time.Minute), msg.ExpiresAt, 5*time.Second)
+				return msg, nil
+			})
+
+		_, err := msgUC.SendMessageWithParams(ctx, withTTL)
+		require.NoError(t, err)
+	})
+
+	t.Run("room default ttl applies when none is given", func(t *testing.T) {
+		mockRoomUC.EXPECT().
+			GetRoom(ctx, "room123").
+			Return(&entities.Room{ID: "room123", DefaultTTLSeconds: 3600}, nil)
+		mockMsgRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg *entities.Message) (*entities.Message, error) {
+				assert.WithinDuration(t, time.Now().Add(time.Hour), msg.ExpiresAt, 5*time.Second)
+				return msg, nil
+			})
+
+		_, err := msgUC.SendMessageWithParams(ctx, params)
+		require.NoError(t, err)
+	})
+
+	t.Run("rooms without a default keep messages", func(t *testing.T) {
+		mockRoomUC.EXPECT().
+			GetRoom(ctx, "room123").
+			Return(&entities.Room{ID: "room123"}, nil)
+		mockMsgRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg *entities.Message) (*entities.Message, error) {
+				assert.True(t, msg.ExpiresAt.IsZero())
+				return msg, nil
+			})
+
+		_, err := msgUC.SendMessageWithParams(ctx, params)
+		require.NoError(t, err)
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		negative := params
+		negative.TTL = -time.Second
+
+		message, err := msgUC.SendMessageWithParams(ctx, negative)
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("muted users cannot post", func(t *testing.T) {
+		mockRoomUC.EXPECT().
+			GetRoom(ctx, "room123").
+			Return(&entities.Room{
+				ID:         "room123",
+				MutedUntil: map[string]time.Time{"user123": time.Now().Add(time.Hour)},
+			}, nil)
+
+		message, err := msgUC.SendMessageWithParams(ctx, params)
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("unverified senders cannot post in moderated rooms", func(t *testing.T) {
+		unverified := params
+		unverified.Unverified = true
+		mockRoomUC.EXPECT().
+			GetRoom(ctx, "room123").
+			Return(&entities.Room{ID: "room123", ModeratorIDs: []string{"mod1"}}, nil)
+
+		message, err := msgUC.SendMessageWithParams(ctx, unverified)
+		assert.ErrorIs(t, err, ErrUnauthenticated)
+		assert.Nil(t, message)
+	})
+}
+
+func TestMessageUseCase_SlashCommands(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgRepo := repoMocks.NewMockMessageRepository(ctrl)
+	mockAuthUC := ucMocks.NewMockAuthUseCase(ctrl)
+	commands := NewCommandRegistry(nil)
+	msgUC := NewMessageUseCase(mockMsgRepo, mockAuthUC, WithCommandRegistry(commands))
+	commands.Register(NewMeCommand(msgUC))
+
+	ctx := context.Background()
+
+	t.Run("commands are not stored as messages", func(t *testing.T) {
+		mockMsgRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg *entities.Message) (*entities.Message, error) {
+				assert.Equal(t, "* testuser waves", msg.Content)
+				return msg, nil
+			})
+
+		message, err := msgUC.SendMessage(ctx, "user123", "testuser", "/me waves", "room123")
+		require.NoError(t, err)
+		assert.False(t, message.Ephemeral)
+	})
+
+	t.Run("unknown commands reply ephemerally", func(t *testing.T) {
+		message, err := msgUC.SendMessage(ctx, "user123", "testuser", "/nope", "room123")
+		require.NoError(t, err)
+		assert.True(t, message.Ephemeral)
+		assert.Contains(t, message.Content, "/help")
+	})
+
+	t.Run("unverified senders cannot run commands", func(t *testing.T) {
+		message, err := msgUC.SendMessageWithParams(ctx, entities.MessageCreateParams{
+			UserID: "user123", Username: "testuser", Content: "/me waves", RoomID: "room123", Unverified: true,
+		})
+		assert.ErrorIs(t, err, ErrUnauthenticated)
+		assert.Nil(t, message)
+	})
+
+	t.Run("double slash posts a literal slash", func(t *testing.T) {
+		mockMsgRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg *entities.Message) (*entities.Message, error) {
+				assert.Equal(t, "/shrug", msg.Content)
+				return msg, nil
+			})
+
+		_, err := msgUC.SendMessage(ctx, "user123", "testuser", "//shrug", "room123")
+		require.NoError(t, err)
+	})
 }
 
 func TestMessageUseCase_GetMessageHistory(t *testing.T) {
@@ -97,6 +265,24 @@ func TestMessageUseCase_GetMessageHistory(t *testing.T) {
 		assert.Len(t, messages, 1)
 	})
 
+	t.Run("expired messages are excluded", func(t *testing.T) {
+		expectedMessages := []*entities.Message{
+			{ID: "msg1", Content: "gone", RoomID: roomID, ExpiresAt: time.Now().Add(-time.Minute)},
+			{ID: "msg2", Content: "still here", RoomID: roomID, ExpiresAt: time.Now().Add(time.Minute)},
+			{ID: "msg3", Content: "permanent", RoomID: roomID},
+		}
+
+		mockMsgRepo.EXPECT().
+			GetByRoomID(ctx, roomID, 50).
+			Return(expectedMessages, nil)
+
+		messages, err := msgUC.GetMessageHistory(ctx, roomID, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		assert.Equal(t, "msg2", messages[0].ID)
+		assert.Equal(t, "msg3", messages[1].ID)
+	})
+
 	t.Run("get message history error", func(t *testing.T) {
 		mockMsgRepo.EXPECT().
 			GetByRoomID(ctx, roomID, 50).
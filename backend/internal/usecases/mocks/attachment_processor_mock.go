@@ -0,0 +1,59 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/attachment_processor.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAttachmentProcessor is a mock of AttachmentProcessor interface.
+type MockAttachmentProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentProcessorMockRecorder
+}
+
+// MockAttachmentProcessorMockRecorder is the mock recorder for MockAttachmentProcessor.
+type MockAttachmentProcessorMockRecorder struct {
+	mock *MockAttachmentProcessor
+}
+
+// NewMockAttachmentProcessor creates a new mock instance.
+func NewMockAttachmentProcessor(ctrl *gomock.Controller) *MockAttachmentProcessor {
+	mock := &MockAttachmentProcessor{ctrl: ctrl}
+	mock.recorder = &MockAttachmentProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentProcessor) EXPECT() *MockAttachmentProcessorMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockAttachmentProcessor) Enqueue(messageID, attachmentID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Enqueue", messageID, attachmentID)
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockAttachmentProcessorMockRecorder) Enqueue(messageID, attachmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockAttachmentProcessor)(nil).Enqueue), messageID, attachmentID)
+}
+
+// Start mocks base method.
+func (m *MockAttachmentProcessor) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockAttachmentProcessorMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockAttachmentProcessor)(nil).Start), ctx)
+}
@@ -35,19 +35,122 @@ func (m *MockAuthUseCase) EXPECT() *MockAuthUseCaseMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockAuthUseCase) ChangePassword(ctx context.Context, userID, currentToken, currentPassword, newPassword string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, currentToken, currentPassword, newPassword)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthUseCaseMockRecorder) ChangePassword(ctx, userID, currentToken, currentPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthUseCase)(nil).ChangePassword), ctx, userID, currentToken, currentPassword, newPassword)
+}
+
+// CompleteLogin mocks base method.
+func (m *MockAuthUseCase) CompleteLogin(ctx context.Context, challenge, code string) (*entities.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLogin", ctx, challenge, code)
+	ret0, _ := ret[0].(*entities.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteLogin indicates an expected call of CompleteLogin.
+func (mr *MockAuthUseCaseMockRecorder) CompleteLogin(ctx, challenge, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLogin", reflect.TypeOf((*MockAuthUseCase)(nil).CompleteLogin), ctx, challenge, code)
+}
+
+// ConfirmTwoFactor mocks base method.
+func (m *MockAuthUseCase) ConfirmTwoFactor(ctx context.Context, userID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTwoFactor", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTwoFactor indicates an expected call of ConfirmTwoFactor.
+func (mr *MockAuthUseCaseMockRecorder) ConfirmTwoFactor(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTwoFactor", reflect.TypeOf((*MockAuthUseCase)(nil).ConfirmTwoFactor), ctx, userID, code)
+}
+
+// DisableTwoFactor mocks base method.
+func (m *MockAuthUseCase) DisableTwoFactor(ctx context.Context, userID, password, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", ctx, userID, password, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *MockAuthUseCaseMockRecorder) DisableTwoFactor(ctx, userID, password, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*MockAuthUseCase)(nil).DisableTwoFactor), ctx, userID, password, code)
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *MockAuthUseCase) EnrollTwoFactor(ctx context.Context, userID string) (*entities.TwoFactorEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor", ctx, userID)
+	ret0, _ := ret[0].(*entities.TwoFactorEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *MockAuthUseCaseMockRecorder) EnrollTwoFactor(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*MockAuthUseCase)(nil).EnrollTwoFactor), ctx, userID)
+}
+
+// ListSessions mocks base method.
+func (m *MockAuthUseCase) ListSessions(ctx context.Context, userID string) ([]*entities.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userID)
+	ret0, _ := ret[0].([]*entities.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockAuthUseCaseMockRecorder) ListSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockAuthUseCase)(nil).ListSessions), ctx, userID)
+}
+
 // Login mocks base method.
-func (m *MockAuthUseCase) Login(ctx context.Context, username, password string) (*entities.AuthToken, error) {
+func (m *MockAuthUseCase) Login(ctx context.Context, username, password string, session entities.SessionMetadata) (*entities.AuthToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, username, password)
+	ret := m.ctrl.Call(m, "Login", ctx, username, password, session)
 	ret0, _ := ret[0].(*entities.AuthToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthUseCaseMockRecorder) Login(ctx, username, password interface{}) *gomock.Call {
+func (mr *MockAuthUseCaseMockRecorder) Login(ctx, username, password, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUseCase)(nil).Login), ctx, username, password, session)
+}
+
+// LoginExternal mocks base method.
+func (m *MockAuthUseCase) LoginExternal(ctx context.Context, identity entities.ExternalIdentity, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginExternal", ctx, identity, session)
+	ret0, _ := ret[0].(*entities.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginExternal indicates an expected call of LoginExternal.
+func (mr *MockAuthUseCaseMockRecorder) LoginExternal(ctx, identity, session interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUseCase)(nil).Login), ctx, username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginExternal", reflect.TypeOf((*MockAuthUseCase)(nil).LoginExternal), ctx, identity, session)
 }
 
 // Logout mocks base method.
@@ -64,19 +167,120 @@ func (mr *MockAuthUseCaseMockRecorder) Logout(ctx, token interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUseCase)(nil).Logout), ctx, token)
 }
 
+// RefreshToken mocks base method.
+func (m *MockAuthUseCase) RefreshToken(ctx context.Context, refreshToken string, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, refreshToken, session)
+	ret0, _ := ret[0].(*entities.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthUseCaseMockRecorder) RefreshToken(ctx, refreshToken, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthUseCase)(nil).RefreshToken), ctx, refreshToken, session)
+}
+
 // Register mocks base method.
-func (m *MockAuthUseCase) Register(ctx context.Context, username, password string) (*entities.AuthToken, error) {
+func (m *MockAuthUseCase) Register(ctx context.Context, username, password string, session entities.SessionMetadata) (*entities.AuthToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", ctx, username, password)
+	ret := m.ctrl.Call(m, "Register", ctx, username, password, session)
 	ret0, _ := ret[0].(*entities.AuthToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockAuthUseCaseMockRecorder) Register(ctx, username, password interface{}) *gomock.Call {
+func (mr *MockAuthUseCaseMockRecorder) Register(ctx, username, password, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthUseCase)(nil).Register), ctx, username, password, session)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockAuthUseCase) RequestPasswordReset(ctx context.Context, username string, session entities.SessionMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", ctx, username, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockAuthUseCaseMockRecorder) RequestPasswordReset(ctx, username, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockAuthUseCase)(nil).RequestPasswordReset), ctx, username, session)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthUseCase) ResetPassword(ctx context.Context, resetToken, newPassword string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, resetToken, newPassword)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthUseCaseMockRecorder) ResetPassword(ctx, resetToken, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthUseCase)(nil).ResetPassword), ctx, resetToken, newPassword)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockAuthUseCase) RevokeOtherSessions(ctx context.Context, userID, currentToken string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, userID, currentToken)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockAuthUseCaseMockRecorder) RevokeOtherSessions(ctx, userID, currentToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockAuthUseCase)(nil).RevokeOtherSessions), ctx, userID, currentToken)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthUseCase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthUseCaseMockRecorder) RevokeSession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthUseCase)(nil).RevokeSession), ctx, userID, sessionID)
+}
+
+// SetEmail mocks base method.
+func (m *MockAuthUseCase) SetEmail(ctx context.Context, userID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmail", ctx, userID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEmail indicates an expected call of SetEmail.
+func (mr *MockAuthUseCaseMockRecorder) SetEmail(ctx, userID, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmail", reflect.TypeOf((*MockAuthUseCase)(nil).SetEmail), ctx, userID, email)
+}
+
+// UnlockAccount mocks base method.
+func (m *MockAuthUseCase) UnlockAccount(ctx context.Context, admin *entities.User, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockAccount", ctx, admin, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockAccount indicates an expected call of UnlockAccount.
+func (mr *MockAuthUseCaseMockRecorder) UnlockAccount(ctx, admin, username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthUseCase)(nil).Register), ctx, username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockAccount", reflect.TypeOf((*MockAuthUseCase)(nil).UnlockAccount), ctx, admin, username)
 }
 
 // ValidateToken mocks base method.
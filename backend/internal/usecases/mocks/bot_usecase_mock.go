@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/bot_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBotUseCase is a mock of BotUseCase interface.
+type MockBotUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockBotUseCaseMockRecorder
+}
+
+// MockBotUseCaseMockRecorder is the mock recorder for MockBotUseCase.
+type MockBotUseCaseMockRecorder struct {
+	mock *MockBotUseCase
+}
+
+// NewMockBotUseCase creates a new mock instance.
+func NewMockBotUseCase(ctrl *gomock.Controller) *MockBotUseCase {
+	mock := &MockBotUseCase{ctrl: ctrl}
+	mock.recorder = &MockBotUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBotUseCase) EXPECT() *MockBotUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateBot mocks base method.
+func (m *MockBotUseCase) CreateBot(ctx context.Context, admin *entities.User, username string, scopes *entities.BotScopes) (*entities.User, *entities.IssuedAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBot", ctx, admin, username, scopes)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(*entities.IssuedAPIKey)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateBot indicates an expected call of CreateBot.
+func (mr *MockBotUseCaseMockRecorder) CreateBot(ctx, admin, username, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBot", reflect.TypeOf((*MockBotUseCase)(nil).CreateBot), ctx, admin, username, scopes)
+}
+
+// CreateBotKey mocks base method.
+func (m *MockBotUseCase) CreateBotKey(ctx context.Context, admin *entities.User, botID string) (*entities.IssuedAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBotKey", ctx, admin, botID)
+	ret0, _ := ret[0].(*entities.IssuedAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBotKey indicates an expected call of CreateBotKey.
+func (mr *MockBotUseCaseMockRecorder) CreateBotKey(ctx, admin, botID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBotKey", reflect.TypeOf((*MockBotUseCase)(nil).CreateBotKey), ctx, admin, botID)
+}
+
+// RevokeBotKey mocks base method.
+func (m *MockBotUseCase) RevokeBotKey(ctx context.Context, admin *entities.User, keyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeBotKey", ctx, admin, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeBotKey indicates an expected call of RevokeBotKey.
+func (mr *MockBotUseCaseMockRecorder) RevokeBotKey(ctx, admin, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeBotKey", reflect.TypeOf((*MockBotUseCase)(nil).RevokeBotKey), ctx, admin, keyID)
+}
+
+// UpdateBotScopes mocks base method.
+func (m *MockBotUseCase) UpdateBotScopes(ctx context.Context, admin *entities.User, botID string, scopes *entities.BotScopes) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBotScopes", ctx, admin, botID, scopes)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBotScopes indicates an expected call of UpdateBotScopes.
+func (mr *MockBotUseCaseMockRecorder) UpdateBotScopes(ctx, admin, botID, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBotScopes", reflect.TypeOf((*MockBotUseCase)(nil).UpdateBotScopes), ctx, admin, botID, scopes)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/incoming_webhook_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIncomingWebhookUseCase is a mock of IncomingWebhookUseCase interface.
+type MockIncomingWebhookUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncomingWebhookUseCaseMockRecorder
+}
+
+// MockIncomingWebhookUseCaseMockRecorder is the mock recorder for MockIncomingWebhookUseCase.
+type MockIncomingWebhookUseCaseMockRecorder struct {
+	mock *MockIncomingWebhookUseCase
+}
+
+// NewMockIncomingWebhookUseCase creates a new mock instance.
+func NewMockIncomingWebhookUseCase(ctrl *gomock.Controller) *MockIncomingWebhookUseCase {
+	mock := &MockIncomingWebhookUseCase{ctrl: ctrl}
+	mock.recorder = &MockIncomingWebhookUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncomingWebhookUseCase) EXPECT() *MockIncomingWebhookUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateIncomingWebhook mocks base method.
+func (m *MockIncomingWebhookUseCase) CreateIncomingWebhook(ctx context.Context, user *entities.User, roomID, displayName string) (*entities.IncomingWebhook, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncomingWebhook", ctx, user, roomID, displayName)
+	ret0, _ := ret[0].(*entities.IncomingWebhook)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateIncomingWebhook indicates an expected call of CreateIncomingWebhook.
+func (mr *MockIncomingWebhookUseCaseMockRecorder) CreateIncomingWebhook(ctx, user, roomID, displayName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncomingWebhook", reflect.TypeOf((*MockIncomingWebhookUseCase)(nil).CreateIncomingWebhook), ctx, user, roomID, displayName)
+}
+
+// DeleteIncomingWebhook mocks base method.
+func (m *MockIncomingWebhookUseCase) DeleteIncomingWebhook(ctx context.Context, user *entities.User, webhookID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncomingWebhook", ctx, user, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIncomingWebhook indicates an expected call of DeleteIncomingWebhook.
+func (mr *MockIncomingWebhookUseCaseMockRecorder) DeleteIncomingWebhook(ctx, user, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncomingWebhook", reflect.TypeOf((*MockIncomingWebhookUseCase)(nil).DeleteIncomingWebhook), ctx, user, webhookID)
+}
+
+// GetIncomingWebhook mocks base method.
+func (m *MockIncomingWebhookUseCase) GetIncomingWebhook(ctx context.Context, user *entities.User, webhookID string) (*entities.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingWebhook", ctx, user, webhookID)
+	ret0, _ := ret[0].(*entities.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingWebhook indicates an expected call of GetIncomingWebhook.
+func (mr *MockIncomingWebhookUseCaseMockRecorder) GetIncomingWebhook(ctx, user, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingWebhook", reflect.TypeOf((*MockIncomingWebhookUseCase)(nil).GetIncomingWebhook), ctx, user, webhookID)
+}
+
+// ListIncomingWebhooks mocks base method.
+func (m *MockIncomingWebhookUseCase) ListIncomingWebhooks(ctx context.Context, user *entities.User, roomID string) ([]*entities.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingWebhooks", ctx, user, roomID)
+	ret0, _ := ret[0].([]*entities.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingWebhooks indicates an expected call of ListIncomingWebhooks.
+func (mr *MockIncomingWebhookUseCaseMockRecorder) ListIncomingWebhooks(ctx, user, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingWebhooks", reflect.TypeOf((*MockIncomingWebhookUseCase)(nil).ListIncomingWebhooks), ctx, user, roomID)
+}
+
+// Post mocks base method.
+func (m *MockIncomingWebhookUseCase) Post(ctx context.Context, webhookID, token string, payload *entities.IncomingWebhookPayload) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, webhookID, token, payload)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockIncomingWebhookUseCaseMockRecorder) Post(ctx, webhookID, token, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockIncomingWebhookUseCase)(nil).Post), ctx, webhookID, token, payload)
+}
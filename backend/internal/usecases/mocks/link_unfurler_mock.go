@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/link_unfurler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLinkUnfurler is a mock of LinkUnfurler interface.
+type MockLinkUnfurler struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkUnfurlerMockRecorder
+}
+
+// MockLinkUnfurlerMockRecorder is the mock recorder for MockLinkUnfurler.
+type MockLinkUnfurlerMockRecorder struct {
+	mock *MockLinkUnfurler
+}
+
+// NewMockLinkUnfurler creates a new mock instance.
+func NewMockLinkUnfurler(ctrl *gomock.Controller) *MockLinkUnfurler {
+	mock := &MockLinkUnfurler{ctrl: ctrl}
+	mock.recorder = &MockLinkUnfurlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLinkUnfurler) EXPECT() *MockLinkUnfurlerMockRecorder {
+	return m.recorder
+}
+
+// Unfurl mocks base method.
+func (m *MockLinkUnfurler) Unfurl(message *entities.Message) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unfurl", message)
+}
+
+// Unfurl indicates an expected call of Unfurl.
+func (mr *MockLinkUnfurlerMockRecorder) Unfurl(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfurl", reflect.TypeOf((*MockLinkUnfurler)(nil).Unfurl), message)
+}
@@ -0,0 +1,120 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/login_guard.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoginGuard is a mock of LoginGuard interface.
+type MockLoginGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginGuardMockRecorder
+}
+
+// MockLoginGuardMockRecorder is the mock recorder for MockLoginGuard.
+type MockLoginGuardMockRecorder struct {
+	mock *MockLoginGuard
+}
+
+// NewMockLoginGuard creates a new mock instance.
+func NewMockLoginGuard(ctrl *gomock.Controller) *MockLoginGuard {
+	mock := &MockLoginGuard{ctrl: ctrl}
+	mock.recorder = &MockLoginGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginGuard) EXPECT() *MockLoginGuardMockRecorder {
+	return m.recorder
+}
+
+// CheckLogin mocks base method.
+func (m *MockLoginGuard) CheckLogin(ctx context.Context, username, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLogin", ctx, username, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckLogin indicates an expected call of CheckLogin.
+func (mr *MockLoginGuardMockRecorder) CheckLogin(ctx, username, ipAddress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLogin", reflect.TypeOf((*MockLoginGuard)(nil).CheckLogin), ctx, username, ipAddress)
+}
+
+// CheckPasswordReset mocks base method.
+func (m *MockLoginGuard) CheckPasswordReset(ctx context.Context, username, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPasswordReset", ctx, username, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckPasswordReset indicates an expected call of CheckPasswordReset.
+func (mr *MockLoginGuardMockRecorder) CheckPasswordReset(ctx, username, ipAddress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPasswordReset", reflect.TypeOf((*MockLoginGuard)(nil).CheckPasswordReset), ctx, username, ipAddress)
+}
+
+// CheckRegistration mocks base method.
+func (m *MockLoginGuard) CheckRegistration(ctx context.Context, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckRegistration", ctx, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckRegistration indicates an expected call of CheckRegistration.
+func (mr *MockLoginGuardMockRecorder) CheckRegistration(ctx, ipAddress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRegistration", reflect.TypeOf((*MockLoginGuard)(nil).CheckRegistration), ctx, ipAddress)
+}
+
+// LoginFailed mocks base method.
+func (m *MockLoginGuard) LoginFailed(ctx context.Context, username, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginFailed", ctx, username, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoginFailed indicates an expected call of LoginFailed.
+func (mr *MockLoginGuardMockRecorder) LoginFailed(ctx, username, ipAddress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginFailed", reflect.TypeOf((*MockLoginGuard)(nil).LoginFailed), ctx, username, ipAddress)
+}
+
+// LoginSucceeded mocks base method.
+func (m *MockLoginGuard) LoginSucceeded(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginSucceeded", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoginSucceeded indicates an expected call of LoginSucceeded.
+func (mr *MockLoginGuardMockRecorder) LoginSucceeded(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginSucceeded", reflect.TypeOf((*MockLoginGuard)(nil).LoginSucceeded), ctx, username)
+}
+
+// Unlock mocks base method.
+func (m *MockLoginGuard) Unlock(ctx context.Context, admin *entities.User, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, admin, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLoginGuardMockRecorder) Unlock(ctx, admin, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLoginGuard)(nil).Unlock), ctx, admin, username)
+}
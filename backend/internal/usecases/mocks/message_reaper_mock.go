@@ -0,0 +1,62 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/message_reaper.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMessageReaper is a mock of MessageReaper interface.
+type MockMessageReaper struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageReaperMockRecorder
+}
+
+// MockMessageReaperMockRecorder is the mock recorder for MockMessageReaper.
+type MockMessageReaperMockRecorder struct {
+	mock *MockMessageReaper
+}
+
+// NewMockMessageReaper creates a new mock instance.
+func NewMockMessageReaper(ctrl *gomock.Controller) *MockMessageReaper {
+	mock := &MockMessageReaper{ctrl: ctrl}
+	mock.recorder = &MockMessageReaperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageReaper) EXPECT() *MockMessageReaperMockRecorder {
+	return m.recorder
+}
+
+// Reap mocks base method.
+func (m *MockMessageReaper) Reap(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reap", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reap indicates an expected call of Reap.
+func (mr *MockMessageReaperMockRecorder) Reap(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reap", reflect.TypeOf((*MockMessageReaper)(nil).Reap), ctx)
+}
+
+// Start mocks base method.
+func (m *MockMessageReaper) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockMessageReaperMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockMessageReaper)(nil).Start), ctx)
+}
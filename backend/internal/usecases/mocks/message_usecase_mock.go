@@ -65,6 +65,21 @@ func (mr *MockMessageUseCaseMockRecorder) SendMessage(ctx, userID, username, con
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockMessageUseCase)(nil).SendMessage), ctx, userID, username, content, roomID)
 }
 
+// SendMessageWithParams mocks base method.
+func (m *MockMessageUseCase) SendMessageWithParams(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessageWithParams", ctx, params)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessageWithParams indicates an expected call of SendMessageWithParams.
+func (mr *MockMessageUseCaseMockRecorder) SendMessageWithParams(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessageWithParams", reflect.TypeOf((*MockMessageUseCase)(nil).SendMessageWithParams), ctx, params)
+}
+
 // StreamMessages mocks base method.
 func (m *MockMessageUseCase) StreamMessages(ctx context.Context, roomID string) (<-chan *entities.Message, error) {
 	m.ctrl.T.Helper()
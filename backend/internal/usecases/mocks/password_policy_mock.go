@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/password_policy.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBreachedPasswords is a mock of BreachedPasswords interface.
+type MockBreachedPasswords struct {
+	ctrl     *gomock.Controller
+	recorder *MockBreachedPasswordsMockRecorder
+}
+
+// MockBreachedPasswordsMockRecorder is the mock recorder for MockBreachedPasswords.
+type MockBreachedPasswordsMockRecorder struct {
+	mock *MockBreachedPasswords
+}
+
+// NewMockBreachedPasswords creates a new mock instance.
+func NewMockBreachedPasswords(ctrl *gomock.Controller) *MockBreachedPasswords {
+	mock := &MockBreachedPasswords{ctrl: ctrl}
+	mock.recorder = &MockBreachedPasswordsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBreachedPasswords) EXPECT() *MockBreachedPasswordsMockRecorder {
+	return m.recorder
+}
+
+// IsBreached mocks base method.
+func (m *MockBreachedPasswords) IsBreached(ctx context.Context, password string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBreached", ctx, password)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBreached indicates an expected call of IsBreached.
+func (mr *MockBreachedPasswordsMockRecorder) IsBreached(ctx, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBreached", reflect.TypeOf((*MockBreachedPasswords)(nil).IsBreached), ctx, password)
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/poll_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPollUseCase is a mock of PollUseCase interface.
+type MockPollUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPollUseCaseMockRecorder
+}
+
+// MockPollUseCaseMockRecorder is the mock recorder for MockPollUseCase.
+type MockPollUseCaseMockRecorder struct {
+	mock *MockPollUseCase
+}
+
+// NewMockPollUseCase creates a new mock instance.
+func NewMockPollUseCase(ctrl *gomock.Controller) *MockPollUseCase {
+	mock := &MockPollUseCase{ctrl: ctrl}
+	mock.recorder = &MockPollUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPollUseCase) EXPECT() *MockPollUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CloseDue mocks base method.
+func (m *MockPollUseCase) CloseDue(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseDue", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseDue indicates an expected call of CloseDue.
+func (mr *MockPollUseCaseMockRecorder) CloseDue(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseDue", reflect.TypeOf((*MockPollUseCase)(nil).CloseDue), ctx)
+}
+
+// ClosePoll mocks base method.
+func (m *MockPollUseCase) ClosePoll(ctx context.Context, user *entities.User, messageID string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePoll", ctx, user, messageID)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePoll indicates an expected call of ClosePoll.
+func (mr *MockPollUseCaseMockRecorder) ClosePoll(ctx, user, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePoll", reflect.TypeOf((*MockPollUseCase)(nil).ClosePoll), ctx, user, messageID)
+}
+
+// CreatePoll mocks base method.
+func (m *MockPollUseCase) CreatePoll(ctx context.Context, user *entities.User, roomID string, params entities.PollParams) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePoll", ctx, user, roomID, params)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePoll indicates an expected call of CreatePoll.
+func (mr *MockPollUseCaseMockRecorder) CreatePoll(ctx, user, roomID, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoll", reflect.TypeOf((*MockPollUseCase)(nil).CreatePoll), ctx, user, roomID, params)
+}
+
+// GetPoll mocks base method.
+func (m *MockPollUseCase) GetPoll(ctx context.Context, messageID string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoll", ctx, messageID)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoll indicates an expected call of GetPoll.
+func (mr *MockPollUseCaseMockRecorder) GetPoll(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoll", reflect.TypeOf((*MockPollUseCase)(nil).GetPoll), ctx, messageID)
+}
+
+// Start mocks base method.
+func (m *MockPollUseCase) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockPollUseCaseMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockPollUseCase)(nil).Start), ctx)
+}
+
+// Vote mocks base method.
+func (m *MockPollUseCase) Vote(ctx context.Context, user *entities.User, messageID string, optionIDs []string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vote", ctx, user, messageID, optionIDs)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Vote indicates an expected call of Vote.
+func (mr *MockPollUseCaseMockRecorder) Vote(ctx, user, messageID, optionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockPollUseCase)(nil).Vote), ctx, user, messageID, optionIDs)
+}
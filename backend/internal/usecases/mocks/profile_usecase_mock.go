@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/profile_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProfileUseCase is a mock of ProfileUseCase interface.
+type MockProfileUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileUseCaseMockRecorder
+}
+
+// MockProfileUseCaseMockRecorder is the mock recorder for MockProfileUseCase.
+type MockProfileUseCaseMockRecorder struct {
+	mock *MockProfileUseCase
+}
+
+// NewMockProfileUseCase creates a new mock instance.
+func NewMockProfileUseCase(ctrl *gomock.Controller) *MockProfileUseCase {
+	mock := &MockProfileUseCase{ctrl: ctrl}
+	mock.recorder = &MockProfileUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileUseCase) EXPECT() *MockProfileUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetProfile mocks base method.
+func (m *MockProfileUseCase) GetProfile(ctx context.Context, username string) (*entities.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, username)
+	ret0, _ := ret[0].(*entities.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockProfileUseCaseMockRecorder) GetProfile(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfileUseCase)(nil).GetProfile), ctx, username)
+}
+
+// GetProfiles mocks base method.
+func (m *MockProfileUseCase) GetProfiles(ctx context.Context, userIDs []string) (map[string]*entities.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfiles", ctx, userIDs)
+	ret0, _ := ret[0].(map[string]*entities.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfiles indicates an expected call of GetProfiles.
+func (mr *MockProfileUseCaseMockRecorder) GetProfiles(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfiles", reflect.TypeOf((*MockProfileUseCase)(nil).GetProfiles), ctx, userIDs)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockProfileUseCase) UpdateProfile(ctx context.Context, user *entities.User, update entities.ProfileUpdate) (*entities.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, user, update)
+	ret0, _ := ret[0].(*entities.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockProfileUseCaseMockRecorder) UpdateProfile(ctx, user, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockProfileUseCase)(nil).UpdateProfile), ctx, user, update)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/revocation_list.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevocationList is a mock of RevocationList interface.
+type MockRevocationList struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationListMockRecorder
+}
+
+// MockRevocationListMockRecorder is the mock recorder for MockRevocationList.
+type MockRevocationListMockRecorder struct {
+	mock *MockRevocationList
+}
+
+// NewMockRevocationList creates a new mock instance.
+func NewMockRevocationList(ctrl *gomock.Controller) *MockRevocationList {
+	mock := &MockRevocationList{ctrl: ctrl}
+	mock.recorder = &MockRevocationListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationList) EXPECT() *MockRevocationListMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockRevocationList) IsRevoked(sessionID string, now time.Time) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", sessionID, now)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockRevocationListMockRecorder) IsRevoked(sessionID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockRevocationList)(nil).IsRevoked), sessionID, now)
+}
+
+// Revoke mocks base method.
+func (m *MockRevocationList) Revoke(ctx context.Context, session *entities.RevokedSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRevocationListMockRecorder) Revoke(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRevocationList)(nil).Revoke), ctx, session)
+}
+
+// Start mocks base method.
+func (m *MockRevocationList) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockRevocationListMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRevocationList)(nil).Start), ctx)
+}
+
+// Sync mocks base method.
+func (m *MockRevocationList) Sync(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockRevocationListMockRecorder) Sync(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockRevocationList)(nil).Sync), ctx)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/room_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoomUseCase is a mock of RoomUseCase interface.
+type MockRoomUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomUseCaseMockRecorder
+}
+
+// MockRoomUseCaseMockRecorder is the mock recorder for MockRoomUseCase.
+type MockRoomUseCaseMockRecorder struct {
+	mock *MockRoomUseCase
+}
+
+// NewMockRoomUseCase creates a new mock instance.
+func NewMockRoomUseCase(ctrl *gomock.Controller) *MockRoomUseCase {
+	mock := &MockRoomUseCase{ctrl: ctrl}
+	mock.recorder = &MockRoomUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomUseCase) EXPECT() *MockRoomUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetRoom mocks base method.
+func (m *MockRoomUseCase) GetRoom(ctx context.Context, roomID string) (*entities.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoom", ctx, roomID)
+	ret0, _ := ret[0].(*entities.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoom indicates an expected call of GetRoom.
+func (mr *MockRoomUseCaseMockRecorder) GetRoom(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoom", reflect.TypeOf((*MockRoomUseCase)(nil).GetRoom), ctx, roomID)
+}
+
+// ListPinnedMessages mocks base method.
+func (m *MockRoomUseCase) ListPinnedMessages(ctx context.Context, roomID string) ([]*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPinnedMessages", ctx, roomID)
+	ret0, _ := ret[0].([]*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPinnedMessages indicates an expected call of ListPinnedMessages.
+func (mr *MockRoomUseCaseMockRecorder) ListPinnedMessages(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPinnedMessages", reflect.TypeOf((*MockRoomUseCase)(nil).ListPinnedMessages), ctx, roomID)
+}
+
+// MuteUser mocks base method.
+func (m *MockRoomUseCase) MuteUser(ctx context.Context, user *entities.User, roomID, targetUserID string, until time.Time) (*entities.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MuteUser", ctx, user, roomID, targetUserID, until)
+	ret0, _ := ret[0].(*entities.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MuteUser indicates an expected call of MuteUser.
+func (mr *MockRoomUseCaseMockRecorder) MuteUser(ctx, user, roomID, targetUserID, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MuteUser", reflect.TypeOf((*MockRoomUseCase)(nil).MuteUser), ctx, user, roomID, targetUserID, until)
+}
+
+// PinMessage mocks base method.
+func (m *MockRoomUseCase) PinMessage(ctx context.Context, user *entities.User, roomID, messageID string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinMessage", ctx, user, roomID, messageID)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinMessage indicates an expected call of PinMessage.
+func (mr *MockRoomUseCaseMockRecorder) PinMessage(ctx, user, roomID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinMessage", reflect.TypeOf((*MockRoomUseCase)(nil).PinMessage), ctx, user, roomID, messageID)
+}
+
+// UnpinMessage mocks base method.
+func (m *MockRoomUseCase) UnpinMessage(ctx context.Context, user *entities.User, roomID, messageID string) (*entities.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinMessage", ctx, user, roomID, messageID)
+	ret0, _ := ret[0].(*entities.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpinMessage indicates an expected call of UnpinMessage.
+func (mr *MockRoomUseCaseMockRecorder) UnpinMessage(ctx, user, roomID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinMessage", reflect.TypeOf((*MockRoomUseCase)(nil).UnpinMessage), ctx, user, roomID, messageID)
+}
+
+// UpdateRoomSettings mocks base method.
+func (m *MockRoomUseCase) UpdateRoomSettings(ctx context.Context, user *entities.User, settings entities.RoomSettings) (*entities.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRoomSettings", ctx, user, settings)
+	ret0, _ := ret[0].(*entities.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRoomSettings indicates an expected call of UpdateRoomSettings.
+func (mr *MockRoomUseCaseMockRecorder) UpdateRoomSettings(ctx, user, settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRoomSettings", reflect.TypeOf((*MockRoomUseCase)(nil).UpdateRoomSettings), ctx, user, settings)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/scheduled_message_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockScheduledMessageUseCase is a mock of ScheduledMessageUseCase interface.
+type MockScheduledMessageUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledMessageUseCaseMockRecorder
+}
+
+// MockScheduledMessageUseCaseMockRecorder is the mock recorder for MockScheduledMessageUseCase.
+type MockScheduledMessageUseCaseMockRecorder struct {
+	mock *MockScheduledMessageUseCase
+}
+
+// NewMockScheduledMessageUseCase creates a new mock instance.
+func NewMockScheduledMessageUseCase(ctrl *gomock.Controller) *MockScheduledMessageUseCase {
+	mock := &MockScheduledMessageUseCase{ctrl: ctrl}
+	mock.recorder = &MockScheduledMessageUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledMessageUseCase) EXPECT() *MockScheduledMessageUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CancelScheduledMessage mocks base method.
+func (m *MockScheduledMessageUseCase) CancelScheduledMessage(ctx context.Context, user *entities.User, id string) (*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledMessage", ctx, user, id)
+	ret0, _ := ret[0].(*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledMessage indicates an expected call of CancelScheduledMessage.
+func (mr *MockScheduledMessageUseCaseMockRecorder) CancelScheduledMessage(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledMessage", reflect.TypeOf((*MockScheduledMessageUseCase)(nil).CancelScheduledMessage), ctx, user, id)
+}
+
+// DeliverDue mocks base method.
+func (m *MockScheduledMessageUseCase) DeliverDue(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverDue", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverDue indicates an expected call of DeliverDue.
+func (mr *MockScheduledMessageUseCaseMockRecorder) DeliverDue(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverDue", reflect.TypeOf((*MockScheduledMessageUseCase)(nil).DeliverDue), ctx)
+}
+
+// GetScheduledMessage mocks base method.
+func (m *MockScheduledMessageUseCase) GetScheduledMessage(ctx context.Context, user *entities.User, id string) (*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledMessage", ctx, user, id)
+	ret0, _ := ret[0].(*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledMessage indicates an expected call of GetScheduledMessage.
+func (mr *MockScheduledMessageUseCaseMockRecorder) GetScheduledMessage(ctx, user, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledMessage", reflect.TypeOf((*MockScheduledMessageUseCase)(nil).GetScheduledMessage), ctx, user, id)
+}
+
+// ListScheduledMessages mocks base method.
+func (m *MockScheduledMessageUseCase) ListScheduledMessages(ctx context.Context, user *entities.User, roomID string) ([]*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledMessages", ctx, user, roomID)
+	ret0, _ := ret[0].([]*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledMessages indicates an expected call of ListScheduledMessages.
+func (mr *MockScheduledMessageUseCaseMockRecorder) ListScheduledMessages(ctx, user, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledMessages", reflect.TypeOf((*MockScheduledMessageUseCase)(nil).ListScheduledMessages), ctx, user, roomID)
+}
+
+// ScheduleMessage mocks base method.
+func (m *MockScheduledMessageUseCase) ScheduleMessage(ctx context.Context, user *entities.User, roomID, content string, sendAt time.Time, ttl time.Duration) (*entities.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleMessage", ctx, user, roomID, content, sendAt, ttl)
+	ret0, _ := ret[0].(*entities.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleMessage indicates an expected call of ScheduleMessage.
+func (mr *MockScheduledMessageUseCaseMockRecorder) ScheduleMessage(ctx, user, roomID, content, sendAt, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleMessage", reflect.TypeOf((*MockScheduledMessageUseCase)(nil).ScheduleMessage), ctx, user, roomID, content, sendAt, ttl)
+}
+
+// Start mocks base method.
+func (m *MockScheduledMessageUseCase) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockScheduledMessageUseCaseMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockScheduledMessageUseCase)(nil).Start), ctx)
+}
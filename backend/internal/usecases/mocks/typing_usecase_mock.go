@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/typing_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTypingUseCase is a mock of TypingUseCase interface.
+type MockTypingUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockTypingUseCaseMockRecorder
+}
+
+// MockTypingUseCaseMockRecorder is the mock recorder for MockTypingUseCase.
+type MockTypingUseCaseMockRecorder struct {
+	mock *MockTypingUseCase
+}
+
+// NewMockTypingUseCase creates a new mock instance.
+func NewMockTypingUseCase(ctrl *gomock.Controller) *MockTypingUseCase {
+	mock := &MockTypingUseCase{ctrl: ctrl}
+	mock.recorder = &MockTypingUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTypingUseCase) EXPECT() *MockTypingUseCaseMockRecorder {
+	return m.recorder
+}
+
+// SetTyping mocks base method.
+func (m *MockTypingUseCase) SetTyping(ctx context.Context, user *entities.User, roomID string, typing bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTyping", ctx, user, roomID, typing)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTyping indicates an expected call of SetTyping.
+func (mr *MockTypingUseCaseMockRecorder) SetTyping(ctx, user, roomID, typing interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTyping", reflect.TypeOf((*MockTypingUseCase)(nil).SetTyping), ctx, user, roomID, typing)
+}
+
+// Subscribe mocks base method.
+func (m *MockTypingUseCase) Subscribe(ctx context.Context, roomID string) <-chan *entities.TypingEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, roomID)
+	ret0, _ := ret[0].(<-chan *entities.TypingEvent)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockTypingUseCaseMockRecorder) Subscribe(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockTypingUseCase)(nil).Subscribe), ctx, roomID)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecases/webhook_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "chat-app/backend/internal/domain/entities"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookUseCase is a mock of WebhookUseCase interface.
+type MockWebhookUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookUseCaseMockRecorder
+}
+
+// MockWebhookUseCaseMockRecorder is the mock recorder for MockWebhookUseCase.
+type MockWebhookUseCaseMockRecorder struct {
+	mock *MockWebhookUseCase
+}
+
+// NewMockWebhookUseCase creates a new mock instance.
+func NewMockWebhookUseCase(ctrl *gomock.Controller) *MockWebhookUseCase {
+	mock := &MockWebhookUseCase{ctrl: ctrl}
+	mock.recorder = &MockWebhookUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookUseCase) EXPECT() *MockWebhookUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookUseCase) CreateWebhook(ctx context.Context, user *entities.User, roomID, rawURL, secret string, events []string) (*entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, user, roomID, rawURL, secret, events)
+	ret0, _ := ret[0].(*entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) CreateWebhook(ctx, user, roomID, rawURL, secret, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).CreateWebhook), ctx, user, roomID, rawURL, secret, events)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookUseCase) DeleteWebhook(ctx context.Context, user *entities.User, webhookID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, user, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) DeleteWebhook(ctx, user, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).DeleteWebhook), ctx, user, webhookID)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookUseCase) GetWebhook(ctx context.Context, user *entities.User, webhookID string) (*entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, user, webhookID)
+	ret0, _ := ret[0].(*entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) GetWebhook(ctx, user, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).GetWebhook), ctx, user, webhookID)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookUseCase) ListDeliveries(ctx context.Context, user *entities.User, webhookID string, limit int) ([]*entities.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, user, webhookID, limit)
+	ret0, _ := ret[0].([]*entities.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookUseCaseMockRecorder) ListDeliveries(ctx, user, webhookID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookUseCase)(nil).ListDeliveries), ctx, user, webhookID, limit)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookUseCase) ListWebhooks(ctx context.Context, user *entities.User, roomID string) ([]*entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx, user, roomID)
+	ret0, _ := ret[0].([]*entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookUseCaseMockRecorder) ListWebhooks(ctx, user, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookUseCase)(nil).ListWebhooks), ctx, user, roomID)
+}
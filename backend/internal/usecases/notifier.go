@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+// Notifier delivers account messages to users outside the chat.
+type Notifier interface {
+	// SendPasswordReset sends token to user.Email. The token is valid until
+	// expiresAt and is exchanged with ResetPassword.
+	SendPasswordReset(ctx context.Context, user *entities.User, token string, expiresAt time.Time) error
+	// SendAccountLocked tells user that logins to their account are refused
+	// until lockedUntil after too many wrong passwords.
+	SendAccountLocked(ctx context.Context, user *entities.User, lockedUntil time.Time) error
+}
@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BreachedPasswords tells whether a password appeared in a known data
+// breach.
+type BreachedPasswords interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// PasswordPolicy is what new passwords must look like and how they are
+// hashed.
+type PasswordPolicy struct {
+	MinLength int
+	// MinEntropyBits is how many bits of entropy a password needs by
+	// estimatePasswordEntropy. Zero turns the estimate off.
+	MinEntropyBits float64
+	// RejectUsername refuses passwords that contain the username.
+	RejectUsername bool
+	// BcryptCost is the cost new hashes are made with. Hashes of a lower
+	// cost are upgraded when their user logs in.
+	BcryptCost int
+}
+
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:      8,
+		MinEntropyBits: 40,
+		RejectUsername: true,
+		BcryptCost:     bcrypt.DefaultCost,
+	}
+}
+
+// maxPasswordBytes is as much of a password as bcrypt uses.
+const maxPasswordBytes = 72
+
+func (p PasswordPolicy) check(field, username, password string) error {
+	if length := len([]rune(password)); length < p.MinLength || length == 0 {
+		return invalidArgument(field, "PASSWORD_TOO_SHORT", "password must be at least %d characters", max(p.MinLength, 1))
+	}
+	if len(password) > maxPasswordBytes {
+		return invalidArgument(field, "PASSWORD_TOO_LONG", "password must be at most %d bytes", maxPasswordBytes)
+	}
+	if p.RejectUsername && username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return invalidArgument(field, "PASSWORD_CONTAINS_USERNAME", "password must not contain the username")
+	}
+	if p.MinEntropyBits > 0 && estimatePasswordEntropy(password) < p.MinEntropyBits {
+		return invalidArgument(field, "PASSWORD_TOO_WEAK", "password is too easy to guess, use a longer one or more kinds of characters")
+	}
+	return nil
+}
+
+func (p PasswordPolicy) cost() int {
+	if p.BcryptCost < bcrypt.MinCost {
+		return bcrypt.DefaultCost
+	}
+	return p.BcryptCost
+}
+
+// estimatePasswordEntropy guesses how many bits of entropy password has
+// from the kinds of characters it uses. A character that repeats the one
+// before it or continues a sequence such as "abc" or "321" counts for one
+// bit only.
+func estimatePasswordEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII && unicode.IsPrint(r):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	pool := 0
+	for _, kind := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if kind.used {
+			pool += kind.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+
+	bitsPerCharacter := math.Log2(float64(pool))
+	var bits float64
+	previous, step := rune(-1), rune(0)
+	for _, r := range password {
+		diff := r - previous
+		if previous >= 0 && (diff == 0 || (diff == 1 || diff == -1) && (step == 0 || diff == step)) {
+			bits++
+			step = diff
+		} else {
+			bits += bitsPerCharacter
+			step = 0
+		}
+		previous = r
+	}
+	return bits
+}
+
+// checkPassword applies the policy and the breached password list to a
+// new password of the user with the given username, which may be empty.
+func (uc *authUseCase) checkPassword(ctx context.Context, field, username, password string) error {
+	if err := uc.config.PasswordPolicy.check(field, username, password); err != nil {
+		return err
+	}
+	if uc.breached == nil {
+		return nil
+	}
+
+	breached, err := uc.breached.IsBreached(ctx, password)
+	if err != nil {
+		return fmt.Errorf("failed to check breached passwords: %v", err)
+	}
+	if breached {
+		return invalidArgument(field, "PASSWORD_BREACHED", "password appeared in a data breach, choose another one")
+	}
+	return nil
+}
+
+func (uc *authUseCase) hashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), uc.config.PasswordPolicy.cost())
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %v", err)
+	}
+	return string(hashedPassword), nil
+}
+
+// dummyPasswordHash returns a hash of a random password, made with the
+// policy's cost, that logins are checked against when there is no account
+// hash so that they take as long as a wrong password.
+func (uc *authUseCase) dummyPasswordHash() []byte {
+	uc.dummyHashOnce.Do(func() {
+		password := make([]byte, 32)
+		rand.Read(password)
+		uc.dummyHash, _ = bcrypt.GenerateFromPassword(password, uc.config.PasswordPolicy.cost())
+	})
+	return uc.dummyHash
+}
+
+// needsRehash reports whether hash was made with a lower cost than the
+// policy asks for.
+func (uc *authUseCase) needsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost < uc.config.PasswordPolicy.cost()
+}
@@ -0,0 +1,101 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		password   string
+		breached   bool
+		wantReason string
+	}{
+		{name: "valid", password: "correct-horse-battery"},
+		{name: "too short", password: "xK9#q", wantReason: "PASSWORD_TOO_SHORT"},
+		{name: "too long", password: strings.Repeat("correct-horse-", 6), wantReason: "PASSWORD_TOO_LONG"},
+		{name: "contains the username", password: "the-Mariem-password", wantReason: "PASSWORD_CONTAINS_USERNAME"},
+		{name: "repeated characters", password: "aaaaaaaaaaaa", wantReason: "PASSWORD_TOO_WEAK"},
+		{name: "sequence", password: "123456789", wantReason: "PASSWORD_TOO_WEAK"},
+		{name: "one kind of character", password: "password", wantReason: "PASSWORD_TOO_WEAK"},
+		{name: "breached", password: "Summer2024!", breached: true, wantReason: "PASSWORD_BREACHED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mocks.NewMockUserRepository(ctrl)
+			mockBreached := ucMocks.NewMockBreachedPasswords(ctrl)
+			authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithBreachedPasswords(mockBreached))
+
+			mockBreached.EXPECT().IsBreached(ctx, tt.password).Return(tt.breached, nil).MaxTimes(1)
+			if tt.wantReason == "" {
+				mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(nil)
+				mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+			}
+
+			_, err := authUC.Register(ctx, "mariem", tt.password, entities.SessionMetadata{})
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			domainErr, ok := usecases.AsError(err)
+			require.True(t, ok)
+			assert.Equal(t, usecases.KindInvalidArgument, domainErr.Kind)
+			assert.Equal(t, "password", domainErr.Field)
+			assert.Equal(t, tt.wantReason, domainErr.Reason)
+		})
+	}
+}
+
+func TestAuthUseCase_LoginUpgradesPasswordHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	config := usecases.DefaultAuthConfig()
+	config.PasswordPolicy.BcryptCost = bcrypt.MinCost + 1
+	authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithAuthConfig(config))
+
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	user := &entities.User{ID: "user123", Username: "mariem", PasswordHash: string(hashedPassword), CreatedAt: time.Now()}
+
+	mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+	mockUserRepo.EXPECT().
+		UpdatePassword(ctx, "user123", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID, passwordHash string) error {
+			cost, err := bcrypt.Cost([]byte(passwordHash))
+			require.NoError(t, err)
+			assert.Equal(t, bcrypt.MinCost+1, cost)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte("secret")))
+			user.PasswordHash = passwordHash
+			return nil
+		})
+	mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil).Times(2)
+
+	_, err := authUC.Login(ctx, "mariem", "secret", entities.SessionMetadata{})
+	require.NoError(t, err)
+
+	// The upgraded hash is left alone.
+	mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+	_, err = authUC.Login(ctx, "mariem", "secret", entities.SessionMetadata{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type PollUseCase interface {
+	CreatePoll(ctx context.Context, user *entities.User, roomID string, params entities.PollParams) (*entities.Message, error)
+	Vote(ctx context.Context, user *entities.User, messageID string, optionIDs []string) (*entities.Message, error)
+	ClosePoll(ctx context.Context, user *entities.User, messageID string) (*entities.Message, error)
+	// GetPoll returns the message that holds the poll.
+	GetPoll(ctx context.Context, messageID string) (*entities.Message, error)
+	Start(ctx context.Context)
+	CloseDue(ctx context.Context) (int, error)
+}
+
+type PollConfig struct {
+	MinOptions      int
+	MaxOptions      int
+	MaxOptionLength int
+	PollInterval    time.Duration
+	BatchSize       int
+	// VoterKeySecret keys the hashes anonymous votes are stored under, so
+	// they cannot be matched to users without it. Anonymous polls are
+	// refused when it is empty, and changing it lets everyone vote again
+	// in the anonymous polls that are still open.
+	VoterKeySecret []byte
+}
+
+// errAnonymousPolls refuses anonymous polls on servers without a voter key
+// secret.
+var errAnonymousPolls = failedPrecondition("ANONYMOUS_POLLS_DISABLED", "anonymous polls are not enabled on this server")
+
+func DefaultPollConfig() PollConfig {
+	return PollConfig{
+		MinOptions:      2,
+		MaxOptions:      10,
+		MaxOptionLength: 200,
+		PollInterval:    10 * time.Second,
+		BatchSize:       50,
+	}
+}
+
+type pollUseCase struct {
+	messageRepo    repositories.MessageRepository
+	messageUseCase MessageUseCase
+	config         PollConfig
+}
+
+func NewPollUseCase(messageRepo repositories.MessageRepository, messageUseCase MessageUseCase, config PollConfig) PollUseCase {
+	return &pollUseCase{
+		messageRepo:    messageRepo,
+		messageUseCase: messageUseCase,
+		config:         config,
+	}
+}
+
+// CreatePoll posts the poll as a regular message whose content is the
+// question, so clients without poll support still show something sensible.
+func (uc *pollUseCase) CreatePoll(ctx context.Context, user *entities.User, roomID string, params entities.PollParams) (*entities.Message, error) {
+	if roomID == "" {
+		return nil, invalidArgument("room_id", "ROOM_ID_REQUIRED", "room id is required")
+	}
+
+	question := strings.TrimSpace(params.Question)
+	if question == "" {
+		return nil, invalidArgument("question", "POLL_QUESTION_REQUIRED", "poll question is required")
+	}
+	if len(params.Options) < uc.config.MinOptions || len(params.Options) > uc.config.MaxOptions {
+		return nil, invalidArgument("options", "POLL_OPTION_COUNT", "a poll needs between %d and %d options", uc.config.MinOptions, uc.config.MaxOptions)
+	}
+	if !params.ClosesAt.IsZero() && !params.ClosesAt.After(time.Now()) {
+		return nil, invalidArgument("closes_at", "POLL_CLOSES_IN_PAST", "poll closing time must be in the future")
+	}
+	if params.Anonymous && len(uc.config.VoterKeySecret) == 0 {
+		return nil, errAnonymousPolls
+	}
+
+	poll := &entities.Poll{
+		Question:       question,
+		MultipleChoice: params.MultipleChoice,
+		Anonymous:      params.Anonymous,
+		ClosesAt:       params.ClosesAt,
+	}
+	seen := make(map[string]bool)
+	for i, text := range params.Options {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, invalidArgument("options", "POLL_OPTION_EMPTY", "poll options cannot be empty")
+		}
+		if len(text) > uc.config.MaxOptionLength {
+			return nil, invalidArgument("options", "POLL_OPTION_TOO_LONG", "poll options cannot be longer than %d characters", uc.config.MaxOptionLength)
+		}
+		if seen[strings.ToLower(text)] {
+			return nil, invalidArgument("options", "POLL_OPTION_DUPLICATE", "duplicate poll option %q", text)
+		}
+		seen[strings.ToLower(text)] = true
+		poll.Options = append(poll.Options, &entities.PollOption{ID: strconv.Itoa(i + 1), Text: text})
+	}
+
+	return uc.messageUseCase.SendMessageWithParams(ctx, entities.MessageCreateParams{
+		UserID:   user.ID,
+		Username: user.Username,
+		Content:  question,
+		RoomID:   roomID,
+		Poll:     poll,
+	})
+}
+
+func (uc *pollUseCase) Vote(ctx context.Context, user *entities.User, messageID string, optionIDs []string) (*entities.Message, error) {
+	message, err := uc.messageRepo.GetByID(ctx, messageID)
+	if err != nil || message.Poll == nil {
+		return nil, notFound("POLL_NOT_FOUND", "poll not found")
+	}
+
+	now := time.Now()
+	if !message.Poll.IsOpen(now) {
+		return nil, failedPrecondition("POLL_CLOSED", "poll is closed")
+	}
+	if message.Poll.Anonymous && len(uc.config.VoterKeySecret) == 0 {
+		return nil, errAnonymousPolls
+	}
+	if len(optionIDs) == 0 {
+		return nil, invalidArgument("option_ids", "POLL_NO_OPTION", "select at least one option")
+	}
+	if !message.Poll.MultipleChoice && len(optionIDs) > 1 {
+		return nil, invalidArgument("option_ids", "POLL_SINGLE_CHOICE", "this poll only allows a single choice")
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range optionIDs {
+		if message.Poll.Option(id) == nil {
+			return nil, invalidArgument("option_ids", "POLL_OPTION_NOT_FOUND", "poll option %s not found", id)
+		}
+		if seen[id] {
+			return nil, invalidArgument("option_ids", "POLL_OPTION_DUPLICATE", "poll option %s selected twice", id)
+		}
+		seen[id] = true
+	}
+
+	return uc.messageRepo.RecordVote(ctx, messageID, uc.voterKey(message, user.ID), user.ID, optionIDs, now)
+}
+
+func (uc *pollUseCase) ClosePoll(ctx context.Context, user *entities.User, messageID string) (*entities.Message, error) {
+	message, err := uc.messageRepo.GetByID(ctx, messageID)
+	if err != nil || message.Poll == nil {
+		return nil, notFound("POLL_NOT_FOUND", "poll not found")
+	}
+	if message.UserID != user.ID {
+		return nil, permissionDenied("AUTHOR_REQUIRED", "only the author can close a poll")
+	}
+
+	return uc.messageRepo.ClosePoll(ctx, messageID)
+}
+
+func (uc *pollUseCase) GetPoll(ctx context.Context, messageID string) (*entities.Message, error) {
+	message, err := uc.messageRepo.GetByID(ctx, messageID)
+	if err != nil || message.Poll == nil {
+		return nil, notFound("POLL_NOT_FOUND", "poll not found")
+	}
+	return message, nil
+}
+
+func (uc *pollUseCase) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(uc.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := uc.CloseDue(ctx); err != nil {
+					log.Printf("Error closing due polls: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// CloseDue closes polls whose closing time has passed so that subscribers
+// receive a poll_closed event with the final results.
+func (uc *pollUseCase) CloseDue(ctx context.Context) (int, error) {
+	due, err := uc.messageRepo.ListDuePolls(ctx, time.Now(), uc.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, message := range due {
+		if _, err := uc.messageRepo.ClosePoll(ctx, message.ID); err != nil {
+			log.Printf("Error closing poll %s: %v", message.ID, err)
+			continue
+		}
+		closed++
+	}
+
+	return closed, nil
+}
+
+// voterKey identifies a vote without storing who cast it when the poll is
+// anonymous. The key is an HMAC rather than a plain hash, which anyone could
+// compute for every member of the room to find who voted.
+func (uc *pollUseCase) voterKey(message *entities.Message, userID string) string {
+	if !message.Poll.Anonymous {
+		return userID
+	}
+	mac := hmac.New(sha256.New, uc.config.VoterKeySecret)
+	mac.Write([]byte(message.ID + ":" + userID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
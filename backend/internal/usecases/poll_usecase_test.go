@@ -0,0 +1,229 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollUseCase_CreatePoll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	pollUC := usecases.NewPollUseCase(mockMsgRepo, mockMsgUC, usecases.DefaultPollConfig())
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+
+	t.Run("successful poll", func(t *testing.T) {
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params entities.MessageCreateParams) (*entities.Message, error) {
+				assert.Equal(t, "Lunch?", params.Content)
+				require.NotNil(t, params.Poll)
+				require.Len(t, params.Poll.Options, 2)
+				assert.Equal(t, "1", params.Poll.Options[0].ID)
+				assert.Equal(t, "Pizza", params.Poll.Options[0].Text)
+				return &entities.Message{ID: "msg123", Content: params.Content, Poll: params.Poll}, nil
+			})
+
+		message, err := pollUC.CreatePoll(ctx, user, "room123", entities.PollParams{
+			Question: "Lunch?",
+			Options:  []string{"Pizza", " Sushi "},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Sushi", message.Poll.Options[1].Text)
+	})
+
+	t.Run("too few options", func(t *testing.T) {
+		message, err := pollUC.CreatePoll(ctx, user, "room123", entities.PollParams{
+			Question: "Lunch?",
+			Options:  []string{"Pizza"},
+		})
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("duplicate options", func(t *testing.T) {
+		message, err := pollUC.CreatePoll(ctx, user, "room123", entities.PollParams{
+			Question: "Lunch?",
+			Options:  []string{"Pizza", "pizza"},
+		})
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("closing time in the past", func(t *testing.T) {
+		message, err := pollUC.CreatePoll(ctx, user, "room123", entities.PollParams{
+			Question: "Lunch?",
+			Options:  []string{"Pizza", "Sushi"},
+			ClosesAt: time.Now().Add(-time.Minute),
+		})
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("anonymous polls need a voter key secret", func(t *testing.T) {
+		message, err := pollUC.CreatePoll(ctx, user, "room123", entities.PollParams{
+			Question:  "Lunch?",
+			Options:   []string{"Pizza", "Sushi"},
+			Anonymous: true,
+		})
+		assert.ErrorIs(t, err, usecases.ErrFailedPrecondition)
+		assert.Nil(t, message)
+	})
+}
+
+func TestPollUseCase_Vote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	config := usecases.DefaultPollConfig()
+	config.VoterKeySecret = []byte("0123456789abcdef0123456789abcdef")
+	pollUC := usecases.NewPollUseCase(mockMsgRepo, mockMsgUC, config)
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+
+	newPoll := func(multiple, anonymous bool) *entities.Message {
+		return &entities.Message{
+			ID: "msg123",
+			Poll: &entities.Poll{
+				Question:       "Lunch?",
+				MultipleChoice: multiple,
+				Anonymous:      anonymous,
+				Options: []*entities.PollOption{
+					{ID: "1", Text: "Pizza"},
+					{ID: "2", Text: "Sushi"},
+				},
+			},
+		}
+	}
+
+	t.Run("records a vote", func(t *testing.T) {
+		poll := newPoll(false, false)
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(poll, nil)
+		mockMsgRepo.EXPECT().
+			RecordVote(ctx, "msg123", "user123", "user123", []string{"2"}, gomock.Any()).
+			Return(poll, nil)
+
+		message, err := pollUC.Vote(ctx, user, "msg123", []string{"2"})
+		require.NoError(t, err)
+		assert.Equal(t, "msg123", message.ID)
+	})
+
+	t.Run("anonymous polls do not key votes by user id", func(t *testing.T) {
+		poll := newPoll(false, true)
+		var keys []string
+		recordVote := func(ctx context.Context, messageID, voterKey, userID string, optionIDs []string, at time.Time) (*entities.Message, error) {
+			keys = append(keys, voterKey)
+			return poll, nil
+		}
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(poll, nil).Times(3)
+		mockMsgRepo.EXPECT().
+			RecordVote(ctx, "msg123", gomock.Not("user123"), "user123", []string{"1"}, gomock.Any()).
+			DoAndReturn(recordVote).Times(3)
+
+		_, err := pollUC.Vote(ctx, user, "msg123", []string{"1"})
+		require.NoError(t, err)
+		_, err = pollUC.Vote(ctx, user, "msg123", []string{"1"})
+		require.NoError(t, err)
+
+		// Without the secret the key cannot be worked out from the user id.
+		otherConfig := config
+		otherConfig.VoterKeySecret = []byte("fedcba9876543210fedcba9876543210")
+		_, err = usecases.NewPollUseCase(mockMsgRepo, mockMsgUC, otherConfig).Vote(ctx, user, "msg123", []string{"1"})
+		require.NoError(t, err)
+
+		require.Len(t, keys, 3)
+		assert.Equal(t, keys[0], keys[1])
+		assert.NotEqual(t, keys[0], keys[2])
+	})
+
+	t.Run("anonymous polls need a voter key secret", func(t *testing.T) {
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(newPoll(false, true), nil)
+
+		_, err := usecases.NewPollUseCase(mockMsgRepo, mockMsgUC, usecases.DefaultPollConfig()).Vote(ctx, user, "msg123", []string{"1"})
+		assert.ErrorIs(t, err, usecases.ErrFailedPrecondition)
+	})
+
+	t.Run("single choice rejects several options", func(t *testing.T) {
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(newPoll(false, false), nil)
+
+		message, err := pollUC.Vote(ctx, user, "msg123", []string{"1", "2"})
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("unknown option", func(t *testing.T) {
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(newPoll(true, false), nil)
+
+		message, err := pollUC.Vote(ctx, user, "msg123", []string{"1", "7"})
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("closed poll", func(t *testing.T) {
+		poll := newPoll(false, false)
+		poll.Poll.ClosesAt = time.Now().Add(-time.Minute)
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(poll, nil)
+
+		message, err := pollUC.Vote(ctx, user, "msg123", []string{"1"})
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+}
+
+func TestPollUseCase_ClosePoll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	pollUC := usecases.NewPollUseCase(mockMsgRepo, mockMsgUC, usecases.DefaultPollConfig())
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+	poll := &entities.Message{ID: "msg123", UserID: "user123", Poll: &entities.Poll{Question: "Lunch?"}}
+
+	t.Run("author closes", func(t *testing.T) {
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(poll, nil)
+		mockMsgRepo.EXPECT().ClosePoll(ctx, "msg123").Return(poll, nil)
+
+		_, err := pollUC.ClosePoll(ctx, user, "msg123")
+		require.NoError(t, err)
+	})
+
+	t.Run("others cannot close", func(t *testing.T) {
+		mockMsgRepo.EXPECT().GetByID(ctx, "msg123").Return(poll, nil)
+
+		message, err := pollUC.ClosePoll(ctx, &entities.User{ID: "someone-else"}, "msg123")
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("closes due polls", func(t *testing.T) {
+		mockMsgRepo.EXPECT().
+			ListDuePolls(ctx, gomock.Any(), usecases.DefaultPollConfig().BatchSize).
+			Return([]*entities.Message{{ID: "a"}, {ID: "b"}}, nil)
+		mockMsgRepo.EXPECT().ClosePoll(ctx, "a").Return(&entities.Message{ID: "a"}, nil)
+		mockMsgRepo.EXPECT().ClosePoll(ctx, "b").Return(nil, assert.AnError)
+
+		closed, err := pollUC.CloseDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, closed)
+	})
+}
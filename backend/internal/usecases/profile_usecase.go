@@ -0,0 +1,269 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type ProfileUseCase interface {
+	// GetProfile returns the profile of username, empty if they never set
+	// one.
+	GetProfile(ctx context.Context, username string) (*entities.Profile, error)
+	// GetProfiles returns the profiles of the users that have one, by user
+	// ID. Profiles are cached for a while, and the ones that are not are
+	// read together.
+	GetProfiles(ctx context.Context, userIDs []string) (map[string]*entities.Profile, error)
+	UpdateProfile(ctx context.Context, user *entities.User, update entities.ProfileUpdate) (*entities.Profile, error)
+}
+
+type ProfileConfig struct {
+	MaxDisplayNameLength int
+	MaxBioLength         int
+	MaxStatusLength      int
+	MaxAvatarURLLength   int
+	// MaxBatchSize is how many profiles GetProfiles reads from the store
+	// at once.
+	MaxBatchSize int
+	// CacheTTL is how long GetProfiles keeps a profile, so that a change
+	// can take this long to show on other server instances.
+	CacheTTL time.Duration
+	// MaxCachedProfiles bounds the cache; it is emptied when full.
+	MaxCachedProfiles int
+}
+
+func DefaultProfileConfig() ProfileConfig {
+	return ProfileConfig{
+		MaxDisplayNameLength: 64,
+		MaxBioLength:         500,
+		MaxStatusLength:      140,
+		MaxAvatarURLLength:   2048,
+		MaxBatchSize:         100,
+		CacheTTL:             30 * time.Second,
+		MaxCachedProfiles:    10000,
+	}
+}
+
+// cachedProfile is a profile read at fetchedAt; profile is nil for a user
+// who has none.
+type cachedProfile struct {
+	profile   *entities.Profile
+	fetchedAt time.Time
+}
+
+type profileUseCase struct {
+	profileRepo repositories.ProfileRepository
+	userRepo    repositories.UserRepository
+	config      ProfileConfig
+
+	mu    sync.Mutex
+	cache map[string]cachedProfile
+}
+
+func NewProfileUseCase(profileRepo repositories.ProfileRepository, userRepo repositories.UserRepository, config ProfileConfig) ProfileUseCase {
+	return &profileUseCase{
+		profileRepo: profileRepo,
+		userRepo:    userRepo,
+		config:      config,
+		cache:       make(map[string]cachedProfile),
+	}
+}
+
+func (uc *profileUseCase) GetProfile(ctx context.Context, username string) (*entities.Profile, error) {
+	user, err := uc.userRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.profileRepo.Get(ctx, user.ID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		profile, err = &entities.Profile{UserID: user.ID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Username = user.Username
+	profile.ClearExpiredStatus(time.Now())
+	return profile, nil
+}
+
+func (uc *profileUseCase) GetProfiles(ctx context.Context, userIDs []string) (map[string]*entities.Profile, error) {
+	now := time.Now()
+	profiles := make(map[string]*entities.Profile, len(userIDs))
+	var missing []string
+	seen := make(map[string]bool, len(userIDs))
+
+	uc.mu.Lock()
+	for _, userID := range userIDs {
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		if cached, ok := uc.cache[userID]; ok && now.Sub(cached.fetchedAt) < uc.config.CacheTTL {
+			if cached.profile != nil {
+				profiles[userID] = cached.profile
+			}
+			continue
+		}
+		missing = append(missing, userID)
+	}
+	uc.mu.Unlock()
+
+	for len(missing) > 0 {
+		batch := missing[:min(len(missing), max(uc.config.MaxBatchSize, 1))]
+		missing = missing[len(batch):]
+		fetched, err := uc.profileRepo.GetMany(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		uc.mu.Lock()
+		if len(uc.cache)+len(batch) > uc.config.MaxCachedProfiles {
+			uc.cache = make(map[string]cachedProfile)
+		}
+		for _, userID := range batch {
+			uc.cache[userID] = cachedProfile{fetchedAt: now}
+		}
+		for _, profile := range fetched {
+			uc.cache[profile.UserID] = cachedProfile{profile: profile, fetchedAt: now}
+			profiles[profile.UserID] = profile
+		}
+		uc.mu.Unlock()
+	}
+
+	// Cached profiles are shared, so expired statuses are cleared on
+	// copies.
+	for userID, profile := range profiles {
+		if !profile.StatusExpiresAt.IsZero() && !now.Before(profile.StatusExpiresAt) {
+			current := *profile
+			current.ClearExpiredStatus(now)
+			profiles[userID] = &current
+		}
+	}
+	return profiles, nil
+}
+
+func (uc *profileUseCase) UpdateProfile(ctx context.Context, user *entities.User, update entities.ProfileUpdate) (*entities.Profile, error) {
+	if err := uc.validateUpdate(&update); err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.profileRepo.Get(ctx, user.ID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		profile, err = &entities.Profile{UserID: user.ID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	profile.Username = user.Username
+	profile.ClearExpiredStatus(now)
+	if update.DisplayName != nil {
+		profile.DisplayName = *update.DisplayName
+	}
+	if update.AvatarURL != nil {
+		profile.AvatarURL = *update.AvatarURL
+	}
+	if update.Bio != nil {
+		profile.Bio = *update.Bio
+	}
+	if update.StatusText != nil {
+		profile.StatusText = *update.StatusText
+		profile.StatusExpiresAt = time.Time{}
+		if profile.StatusText != "" && update.StatusTTL > 0 {
+			profile.StatusExpiresAt = now.Add(update.StatusTTL)
+		}
+	}
+	if update.Timezone != nil {
+		profile.Timezone = *update.Timezone
+	}
+	profile.UpdatedAt = now
+
+	if err := uc.profileRepo.Save(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	uc.cache[user.ID] = cachedProfile{profile: profile, fetchedAt: now}
+	uc.mu.Unlock()
+	return profile, nil
+}
+
+// validateUpdate checks the fields of update and trims the text ones.
+func (uc *profileUseCase) validateUpdate(update *entities.ProfileUpdate) error {
+	if update.DisplayName != nil {
+		displayName, err := profileText("display_name", *update.DisplayName, uc.config.MaxDisplayNameLength, false)
+		if err != nil {
+			return err
+		}
+		update.DisplayName = &displayName
+	}
+	if update.Bio != nil {
+		bio, err := profileText("bio", *update.Bio, uc.config.MaxBioLength, true)
+		if err != nil {
+			return err
+		}
+		update.Bio = &bio
+	}
+	if update.StatusText != nil {
+		statusText, err := profileText("status_text", *update.StatusText, uc.config.MaxStatusLength, false)
+		if err != nil {
+			return err
+		}
+		update.StatusText = &statusText
+	}
+	if update.StatusTTL < 0 {
+		return invalidArgument("status_ttl_seconds", "INVALID_STATUS_TTL", "status lifetime cannot be negative")
+	}
+
+	if update.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*update.AvatarURL)
+		if avatarURL != "" {
+			if len(avatarURL) > uc.config.MaxAvatarURLLength {
+				return invalidArgument("avatar_url", "AVATAR_URL_TOO_LONG", "avatar URL cannot be longer than %d characters", uc.config.MaxAvatarURLLength)
+			}
+			parsed, err := url.Parse(avatarURL)
+			if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+				return invalidArgument("avatar_url", "INVALID_AVATAR_URL", "avatar must be an http or https URL")
+			}
+		}
+		update.AvatarURL = &avatarURL
+	}
+
+	if update.Timezone != nil {
+		timezone := strings.TrimSpace(*update.Timezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+				return invalidArgument("timezone", "INVALID_TIMEZONE", "unknown time zone %q", timezone)
+			}
+		}
+		update.Timezone = &timezone
+	}
+	return nil
+}
+
+// profileText trims value and checks that it is at most maxLength
+// characters without control characters, except for line breaks if
+// multiline.
+func profileText(field, value string, maxLength int, multiline bool) (string, error) {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) > maxLength {
+		return "", invalidArgument(field, strings.ToUpper(field)+"_TOO_LONG", "%s cannot be longer than %d characters", strings.ReplaceAll(field, "_", " "), maxLength)
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) && !(multiline && r == '\n') {
+			return "", invalidArgument(field, "INVALID_CHARACTERS", "%s cannot contain control characters", strings.ReplaceAll(field, "_", " "))
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,150 @@
+package usecases_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileUseCase_GetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepo := mocks.NewMockProfileRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	profileUC := usecases.NewProfileUseCase(mockProfileRepo, mockUserRepo, usecases.DefaultProfileConfig())
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+
+	t.Run("user without a profile", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockProfileRepo.EXPECT().Get(ctx, "user123").Return(nil, fmt.Errorf("profile %w", repositories.ErrNotFound))
+
+		profile, err := profileUC.GetProfile(ctx, "mariem")
+		require.NoError(t, err)
+		assert.Equal(t, &entities.Profile{UserID: "user123", Username: "mariem"}, profile)
+	})
+
+	t.Run("expired status is cleared", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByUsername(ctx, "mariem").Return(user, nil)
+		mockProfileRepo.EXPECT().Get(ctx, "user123").Return(&entities.Profile{
+			UserID:          "user123",
+			DisplayName:     "Mariem B.",
+			StatusText:      "On holiday",
+			StatusExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		profile, err := profileUC.GetProfile(ctx, "mariem")
+		require.NoError(t, err)
+		assert.Equal(t, "Mariem B.", profile.DisplayName)
+		assert.Empty(t, profile.StatusText)
+		assert.True(t, profile.StatusExpiresAt.IsZero())
+	})
+}
+
+func TestProfileUseCase_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+	text := func(s string) *string { return &s }
+
+	tests := []struct {
+		name       string
+		update     entities.ProfileUpdate
+		wantField  string
+		wantReason string
+	}{
+		{name: "display name too long", update: entities.ProfileUpdate{DisplayName: text(strings.Repeat("a", 65))}, wantField: "display_name", wantReason: "DISPLAY_NAME_TOO_LONG"},
+		{name: "control characters", update: entities.ProfileUpdate{DisplayName: text("Mariem\u0007")}, wantField: "display_name", wantReason: "INVALID_CHARACTERS"},
+		{name: "bio too long", update: entities.ProfileUpdate{Bio: text(strings.Repeat("a", 501))}, wantField: "bio", wantReason: "BIO_TOO_LONG"},
+		{name: "avatar is not a URL", update: entities.ProfileUpdate{AvatarURL: text("javascript:alert(1)")}, wantField: "avatar_url", wantReason: "INVALID_AVATAR_URL"},
+		{name: "unknown time zone", update: entities.ProfileUpdate{Timezone: text("Mars/Olympus")}, wantField: "timezone", wantReason: "INVALID_TIMEZONE"},
+		{name: "negative status lifetime", update: entities.ProfileUpdate{StatusText: text("Away"), StatusTTL: -time.Second}, wantField: "status_ttl_seconds", wantReason: "INVALID_STATUS_TTL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			profileUC := usecases.NewProfileUseCase(mocks.NewMockProfileRepository(ctrl), mocks.NewMockUserRepository(ctrl), usecases.DefaultProfileConfig())
+
+			_, err := profileUC.UpdateProfile(ctx, user, tt.update)
+			domainErr, ok := usecases.AsError(err)
+			require.True(t, ok)
+			assert.Equal(t, usecases.KindInvalidArgument, domainErr.Kind)
+			assert.Equal(t, tt.wantField, domainErr.Field)
+			assert.Equal(t, tt.wantReason, domainErr.Reason)
+		})
+	}
+
+	t.Run("changes only the given fields", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockProfileRepo := mocks.NewMockProfileRepository(ctrl)
+		profileUC := usecases.NewProfileUseCase(mockProfileRepo, mocks.NewMockUserRepository(ctrl), usecases.DefaultProfileConfig())
+
+		mockProfileRepo.EXPECT().Get(ctx, "user123").Return(&entities.Profile{UserID: "user123", DisplayName: "Mariem", Timezone: "Africa/Tunis"}, nil)
+		mockProfileRepo.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		profile, err := profileUC.UpdateProfile(ctx, user, entities.ProfileUpdate{
+			AvatarURL:  text(" https://example.com/mariem.png "),
+			StatusText: text("In a meeting"),
+			StatusTTL:  time.Hour,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Mariem", profile.DisplayName)
+		assert.Equal(t, "Africa/Tunis", profile.Timezone)
+		assert.Equal(t, "mariem", profile.Username)
+		assert.Equal(t, "https://example.com/mariem.png", profile.AvatarURL)
+		assert.Equal(t, "In a meeting", profile.StatusText)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), profile.StatusExpiresAt, time.Second)
+
+		// The update is seen by lookups without reading the store again.
+		profiles, err := profileUC.GetProfiles(ctx, []string{"user123"})
+		require.NoError(t, err)
+		assert.Equal(t, profile, profiles["user123"])
+	})
+}
+
+func TestProfileUseCase_GetProfiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepo := mocks.NewMockProfileRepository(ctrl)
+	config := usecases.DefaultProfileConfig()
+	config.MaxBatchSize = 2
+	profileUC := usecases.NewProfileUseCase(mockProfileRepo, mocks.NewMockUserRepository(ctrl), config)
+
+	ctx := context.Background()
+	mariem := &entities.Profile{UserID: "user1", DisplayName: "Mariem B."}
+	away := &entities.Profile{UserID: "user3", DisplayName: "Sam", StatusText: "Away", StatusExpiresAt: time.Now().Add(-time.Second)}
+
+	// Duplicates are read once, in batches of MaxBatchSize.
+	mockProfileRepo.EXPECT().GetMany(ctx, []string{"user1", "user2"}).Return([]*entities.Profile{mariem}, nil)
+	mockProfileRepo.EXPECT().GetMany(ctx, []string{"user3"}).Return([]*entities.Profile{away}, nil)
+
+	profiles, err := profileUC.GetProfiles(ctx, []string{"user1", "user2", "user1", "user3"})
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+	assert.Equal(t, "Mariem B.", profiles["user1"].DisplayName)
+	assert.Empty(t, profiles["user3"].StatusText)
+	assert.Equal(t, "Away", away.StatusText, "the cached profile is not changed")
+
+	// Users with and without a profile are both cached.
+	profiles, err = profileUC.GetProfiles(ctx, []string{"user2", "user1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*entities.Profile{"user1": mariem}, profiles)
+}
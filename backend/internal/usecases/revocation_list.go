@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+// RevocationList keeps the revoked sessions in memory so that signed access
+// tokens are checked without a read. Revocations made by other instances
+// are picked up on the next sync.
+type RevocationList interface {
+	Start(ctx context.Context)
+	Sync(ctx context.Context) error
+	Revoke(ctx context.Context, session *entities.RevokedSession) error
+	IsRevoked(sessionID string, now time.Time) bool
+}
+
+type RevocationListConfig struct {
+	SyncInterval time.Duration
+	// Lookback is how far back the first sync reads. Revocations older than
+	// the access token lifetime no longer matter.
+	Lookback time.Duration
+}
+
+func DefaultRevocationListConfig() RevocationListConfig {
+	return RevocationListConfig{
+		SyncInterval: 10 * time.Second,
+		Lookback:     DefaultAuthConfig().AccessTokenTTL,
+	}
+}
+
+type revocationList struct {
+	revokedRepo repositories.RevokedSessionRepository
+	config      RevocationListConfig
+
+	mu       sync.RWMutex
+	revoked  map[string]time.Time
+	syncedAt time.Time
+}
+
+func NewRevocationList(revokedRepo repositories.RevokedSessionRepository, config RevocationListConfig) RevocationList {
+	return &revocationList{
+		revokedRepo: revokedRepo,
+		config:      config,
+		revoked:     make(map[string]time.Time),
+	}
+}
+
+func (l *revocationList) Start(ctx context.Context) {
+	if err := l.Sync(ctx); err != nil {
+		log.Printf("Error loading revoked sessions: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(l.config.SyncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Sync(ctx); err != nil {
+					log.Printf("Error syncing revoked sessions: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Sync reads the revocations made since the previous sync and forgets the
+// expired ones. Reads overlap by one interval so that a revocation whose
+// write was still in flight is not missed.
+func (l *revocationList) Sync(ctx context.Context) error {
+	now := time.Now()
+
+	l.mu.RLock()
+	since := l.syncedAt.Add(-l.config.SyncInterval)
+	if l.syncedAt.IsZero() {
+		since = now.Add(-l.config.Lookback)
+	}
+	l.mu.RUnlock()
+
+	sessions, err := l.revokedRepo.ListRevokedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, session := range sessions {
+		l.add(session)
+	}
+	for id, expiresAt := range l.revoked {
+		if now.After(expiresAt) {
+			delete(l.revoked, id)
+		}
+	}
+	l.syncedAt = now
+	return nil
+}
+
+func (l *revocationList) Revoke(ctx context.Context, session *entities.RevokedSession) error {
+	if err := l.revokedRepo.Create(ctx, session); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.add(session)
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *revocationList) IsRevoked(sessionID string, now time.Time) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	expiresAt, ok := l.revoked[sessionID]
+	return ok && !now.After(expiresAt)
+}
+
+func (l *revocationList) add(session *entities.RevokedSession) {
+	if session.ExpiresAt.After(l.revoked[session.SessionID]) {
+		l.revoked[session.SessionID] = session.ExpiresAt
+	}
+}
@@ -0,0 +1,66 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocationList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRevokedRepo := mocks.NewMockRevokedSessionRepository(ctrl)
+	config := usecases.RevocationListConfig{SyncInterval: time.Second, Lookback: time.Hour}
+	list := usecases.NewRevocationList(mockRevokedRepo, config)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("first sync looks back", func(t *testing.T) {
+		mockRevokedRepo.EXPECT().
+			ListRevokedSince(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, since time.Time) ([]*entities.RevokedSession, error) {
+				assert.WithinDuration(t, now.Add(-time.Hour), since, 5*time.Second)
+				return []*entities.RevokedSession{
+					{SessionID: "s1", ExpiresAt: now.Add(time.Minute)},
+					{SessionID: "s2", ExpiresAt: now.Add(-time.Minute)},
+				}, nil
+			})
+
+		require.NoError(t, list.Sync(ctx))
+		assert.True(t, list.IsRevoked("s1", now))
+		assert.False(t, list.IsRevoked("s2", now))
+		assert.False(t, list.IsRevoked("s3", now))
+	})
+
+	t.Run("later syncs overlap the previous one", func(t *testing.T) {
+		mockRevokedRepo.EXPECT().
+			ListRevokedSince(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, since time.Time) ([]*entities.RevokedSession, error) {
+				assert.WithinDuration(t, now.Add(-time.Second), since, 5*time.Second)
+				return []*entities.RevokedSession{{SessionID: "s3", ExpiresAt: now.Add(time.Minute)}}, nil
+			})
+
+		require.NoError(t, list.Sync(ctx))
+		assert.True(t, list.IsRevoked("s1", now))
+		assert.True(t, list.IsRevoked("s3", now))
+	})
+
+	t.Run("local revocations apply at once", func(t *testing.T) {
+		session := &entities.RevokedSession{SessionID: "s4", ExpiresAt: now.Add(time.Minute)}
+		mockRevokedRepo.EXPECT().Create(ctx, session).Return(nil)
+
+		require.NoError(t, list.Revoke(ctx, session))
+		assert.True(t, list.IsRevoked("s4", now))
+		assert.False(t, list.IsRevoked("s4", now.Add(2*time.Minute)))
+	})
+}
@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type RoomUseCase interface {
+	GetRoom(ctx context.Context, roomID string) (*entities.Room, error)
+	UpdateRoomSettings(ctx context.Context, user *entities.User, settings entities.RoomSettings) (*entities.Room, error)
+	PinMessage(ctx context.Context, user *entities.User, roomID, messageID string) (*entities.Message, error)
+	UnpinMessage(ctx context.Context, user *entities.User, roomID, messageID string) (*entities.Message, error)
+	ListPinnedMessages(ctx context.Context, roomID string) ([]*entities.Message, error)
+	MuteUser(ctx context.Context, user *entities.User, roomID, targetUserID string, until time.Time) (*entities.Room, error)
+}
+
+type RoomConfig struct {
+	DefaultPinLimit int
+	MaxTopicLength  int
+	// AdminUsernames may set up rooms that have no moderator yet.
+	AdminUsernames []string
+}
+
+func DefaultRoomConfig() RoomConfig {
+	return RoomConfig{DefaultPinLimit: 50, MaxTopicLength: 250}
+}
+
+type roomUseCase struct {
+	roomRepo    repositories.RoomRepository
+	messageRepo repositories.MessageRepository
+	config      RoomConfig
+}
+
+func NewRoomUseCase(roomRepo repositories.RoomRepository, messageRepo repositories.MessageRepository, config RoomConfig) RoomUseCase {
+	return &roomUseCase{
+		roomRepo:    roomRepo,
+		messageRepo: messageRepo,
+		config:      config,
+	}
+}
+
+func (uc *roomUseCase) GetRoom(ctx context.Context, roomID string) (*entities.Room, error) {
+	if roomID == "" {
+		return nil, invalidArgument("room_id", "ROOM_ID_REQUIRED", "room id is required")
+	}
+
+	room, err := uc.roomRepo.GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.PinLimit <= 0 {
+		room.PinLimit = uc.config.DefaultPinLimit
+	}
+
+	return room, nil
+}
+
+// UpdateRoomSettings lets moderators change a room. A room nobody moderates
+// yet can only be set up by an admin, who becomes its first moderator.
+func (uc *roomUseCase) UpdateRoomSettings(ctx context.Context, user *entities.User, settings entities.RoomSettings) (*entities.Room, error) {
+	if settings.RoomID == "" {
+		return nil, invalidArgument("room_id", "ROOM_ID_REQUIRED", "room id is required")
+	}
+	if settings.PinLimit < 0 {
+		return nil, invalidArgument("pin_limit", "NEGATIVE_PIN_LIMIT", "pin limit cannot be negative")
+	}
+	if settings.DefaultTTL != nil && *settings.DefaultTTL < 0 {
+		return nil, invalidArgument("default_message_ttl_seconds", "NEGATIVE_TTL", "default message ttl cannot be negative")
+	}
+	var topic string
+	if settings.Topic != nil {
+		topic = strings.TrimSpace(*settings.Topic)
+		if len(topic) > uc.config.MaxTopicLength {
+			return nil, invalidArgument("topic", "TOPIC_TOO_LONG", "room topic cannot be longer than %d characters", uc.config.MaxTopicLength)
+		}
+	}
+
+	room, err := uc.roomRepo.UpdateRoom(ctx, settings.RoomID, func(room *entities.Room) error {
+		if len(room.ModeratorIDs) == 0 {
+			if !isAdmin(uc.config.AdminUsernames, user) {
+				return permissionDenied("ADMIN_REQUIRED", "only admins can set up a room nobody moderates")
+			}
+			room.ModeratorIDs = []string{user.ID}
+		} else if !room.IsModerator(user.ID) {
+			return permissionDenied("MODERATOR_REQUIRED", "only room moderators can change room settings")
+		}
+
+		if settings.PinLimit > 0 {
+			room.PinLimit = settings.PinLimit
+		}
+		if settings.DefaultTTL != nil {
+			room.DefaultTTLSeconds = int(settings.DefaultTTL.Seconds())
+		}
+		if settings.Topic != nil {
+			room.Topic = topic
+		}
+		if len(settings.ModeratorIDs) > 0 {
+			room.ModeratorIDs = settings.ModeratorIDs
+			if !room.IsModerator(user.ID) {
+				return invalidArgument("moderator_ids", "MODERATOR_SELF_REMOVAL", "moderators cannot remove themselves")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if room.PinLimit <= 0 {
+		room.PinLimit = uc.config.DefaultPinLimit
+	}
+
+	return room, nil
+}
+
+func (uc *roomUseCase) PinMessage(ctx context.Context, user *entities.User, roomID, messageID string) (*entities.Message, error) {
+	room, err := uc.requireModerator(ctx, user, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkMessageInRoom(ctx, roomID, messageID); err != nil {
+		return nil, err
+	}
+
+	return uc.messageRepo.Pin(ctx, messageID, user.ID, room.PinLimit)
+}
+
+func (uc *roomUseCase) UnpinMessage(ctx context.Context, user *entities.User, roomID, messageID string) (*entities.Message, error) {
+	if _, err := uc.requireModerator(ctx, user, roomID); err != nil {
+		return nil, err
+	}
+	if err := uc.checkMessageInRoom(ctx, roomID, messageID); err != nil {
+		return nil, err
+	}
+
+	return uc.messageRepo.Unpin(ctx, messageID)
+}
+
+func (uc *roomUseCase) ListPinnedMessages(ctx context.Context, roomID string) ([]*entities.Message, error) {
+	messages, err := uc.messageRepo.GetPinnedByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutExpired(messages, time.Now()), nil
+}
+
+// MuteUser stops a user from posting in the room until the given time. A
+// zero time lifts the mute.
+func (uc *roomUseCase) MuteUser(ctx context.Context, user *entities.User, roomID, targetUserID string, until time.Time) (*entities.Room, error) {
+	if roomID == "" {
+		return nil, invalidArgument("room_id", "ROOM_ID_REQUIRED", "room id is required")
+	}
+	now := time.Now()
+	if !until.IsZero() && !until.After(now) {
+		return nil, invalidArgument("until", "MUTE_ENDS_IN_PAST", "mute must end in the future")
+	}
+
+	room, err := uc.roomRepo.UpdateRoom(ctx, roomID, func(room *entities.Room) error {
+		if !room.IsModerator(user.ID) {
+			return permissionDenied("MODERATOR_REQUIRED", "only room moderators can mute users")
+		}
+		if room.IsModerator(targetUserID) {
+			return failedPrecondition("MODERATOR_NOT_MUTABLE", "moderators cannot be muted")
+		}
+
+		for id, expiry := range room.MutedUntil {
+			if !now.Before(expiry) {
+				delete(room.MutedUntil, id)
+			}
+		}
+		if until.IsZero() {
+			delete(room.MutedUntil, targetUserID)
+			return nil
+		}
+		if room.MutedUntil == nil {
+			room.MutedUntil = make(map[string]time.Time)
+		}
+		room.MutedUntil[targetUserID] = until
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if room.PinLimit <= 0 {
+		room.PinLimit = uc.config.DefaultPinLimit
+	}
+
+	return room, nil
+}
+
+func (uc *roomUseCase) requireModerator(ctx context.Context, user *entities.User, roomID string) (*entities.Room, error) {
+	room, err := uc.GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if !room.IsModerator(user.ID) {
+		return nil, permissionDenied("MODERATOR_REQUIRED", "only room moderators can pin messages")
+	}
+	return room, nil
+}
+
+func (uc *roomUseCase) checkMessageInRoom(ctx context.Context, roomID, messageID string) error {
+	message, err := uc.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return notFound("MESSAGE_NOT_FOUND", "message not found")
+	}
+	if message.RoomID != roomID {
+		return notFound("MESSAGE_NOT_FOUND", "message not found in room %s", roomID)
+	}
+	return nil
+}
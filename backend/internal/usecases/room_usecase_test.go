@@ -0,0 +1,202 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateRoom stands in for a transactional UpdateRoom that reads stored.
+func updateRoom(stored *entities.Room) func(context.Context, string, func(*entities.Room) error) (*entities.Room, error) {
+	return func(ctx context.Context, roomID string, update func(*entities.Room) error) (*entities.Room, error) {
+		if err := update(stored); err != nil {
+			return nil, err
+		}
+		return stored, nil
+	}
+}
+
+func TestRoomUseCase_UpdateRoomSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoomRepo := mocks.NewMockRoomRepository(ctrl)
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	config := usecases.DefaultRoomConfig()
+	config.AdminUsernames = []string{"admin"}
+	roomUC := usecases.NewRoomUseCase(mockRoomRepo, mockMsgRepo, config)
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+
+	t.Run("an admin sets up an unmoderated room", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{ID: "general"}))
+
+		admin := &entities.User{ID: "admin1", Username: "admin"}
+		room, err := roomUC.UpdateRoomSettings(ctx, admin, entities.RoomSettings{RoomID: "general", PinLimit: 5})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"admin1"}, room.ModeratorIDs)
+		assert.Equal(t, 5, room.PinLimit)
+	})
+
+	t.Run("other users cannot claim an unmoderated room", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{ID: "general"}))
+
+		room, err := roomUC.UpdateRoomSettings(ctx, user, entities.RoomSettings{RoomID: "general", PinLimit: 5})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, usecases.ErrPermissionDenied)
+		assert.Nil(t, room)
+	})
+
+	t.Run("moderators change settings", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{ID: "general", ModeratorIDs: []string{"user123"}}))
+
+		topic := "release planning"
+		room, err := roomUC.UpdateRoomSettings(ctx, user, entities.RoomSettings{RoomID: "general", Topic: &topic})
+		require.NoError(t, err)
+		assert.Equal(t, "release planning", room.Topic)
+		assert.Equal(t, usecases.DefaultRoomConfig().DefaultPinLimit, room.PinLimit)
+	})
+
+	t.Run("non moderators are rejected", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{ID: "general", ModeratorIDs: []string{"other"}}))
+
+		room, err := roomUC.UpdateRoomSettings(ctx, user, entities.RoomSettings{RoomID: "general", PinLimit: 5})
+		require.Error(t, err)
+		assert.Nil(t, room)
+	})
+}
+
+func TestRoomUseCase_PinMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoomRepo := mocks.NewMockRoomRepository(ctrl)
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	roomUC := usecases.NewRoomUseCase(mockRoomRepo, mockMsgRepo, usecases.RoomConfig{DefaultPinLimit: 3})
+
+	ctx := context.Background()
+	moderator := &entities.User{ID: "mod123", Username: "moderator"}
+	member := &entities.User{ID: "user123", Username: "member"}
+	room := &entities.Room{ID: "general", ModeratorIDs: []string{"mod123"}}
+
+	t.Run("moderator pins with the default room limit", func(t *testing.T) {
+		mockRoomRepo.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+		mockMsgRepo.EXPECT().
+			GetByID(ctx, "msg123").
+			Return(&entities.Message{ID: "msg123", RoomID: "general"}, nil)
+		mockMsgRepo.EXPECT().
+			Pin(ctx, "msg123", "mod123", 3).
+			Return(&entities.Message{ID: "msg123", RoomID: "general", Pinned: true, PinnedBy: "mod123"}, nil)
+
+		message, err := roomUC.PinMessage(ctx, moderator, "general", "msg123")
+		require.NoError(t, err)
+		assert.True(t, message.Pinned)
+	})
+
+	t.Run("members cannot pin", func(t *testing.T) {
+		mockRoomRepo.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+
+		message, err := roomUC.PinMessage(ctx, member, "general", "msg123")
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("message from another room", func(t *testing.T) {
+		mockRoomRepo.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+		mockMsgRepo.EXPECT().
+			GetByID(ctx, "msg999").
+			Return(&entities.Message{ID: "msg999", RoomID: "random"}, nil)
+
+		message, err := roomUC.PinMessage(ctx, moderator, "general", "msg999")
+		require.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("moderator unpins", func(t *testing.T) {
+		mockRoomRepo.EXPECT().GetRoom(ctx, "general").Return(room, nil)
+		mockMsgRepo.EXPECT().
+			GetByID(ctx, "msg123").
+			Return(&entities.Message{ID: "msg123", RoomID: "general", Pinned: true}, nil)
+		mockMsgRepo.EXPECT().
+			Unpin(ctx, "msg123").
+			Return(&entities.Message{ID: "msg123", RoomID: "general"}, nil)
+
+		message, err := roomUC.UnpinMessage(ctx, moderator, "general", "msg123")
+		require.NoError(t, err)
+		assert.False(t, message.Pinned)
+	})
+}
+
+func TestRoomUseCase_MuteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoomRepo := mocks.NewMockRoomRepository(ctrl)
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	roomUC := usecases.NewRoomUseCase(mockRoomRepo, mockMsgRepo, usecases.DefaultRoomConfig())
+
+	ctx := context.Background()
+	moderator := &entities.User{ID: "mod1", Username: "mariem"}
+	until := time.Now().Add(time.Hour)
+
+	t.Run("moderator mutes a user", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{ID: "general", ModeratorIDs: []string{"mod1"}}))
+
+		room, err := roomUC.MuteUser(ctx, moderator, "general", "user2", until)
+		require.NoError(t, err)
+		assert.True(t, room.IsMuted("user2", time.Now()))
+	})
+
+	t.Run("zero time unmutes", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{
+				ID:           "general",
+				ModeratorIDs: []string{"mod1"},
+				MutedUntil:   map[string]time.Time{"user2": until},
+			}))
+
+		room, err := roomUC.MuteUser(ctx, moderator, "general", "user2", time.Time{})
+		require.NoError(t, err)
+		assert.False(t, room.IsMuted("user2", time.Now()))
+	})
+
+	t.Run("non moderators cannot mute", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{ID: "general", ModeratorIDs: []string{"mod1"}}))
+
+		room, err := roomUC.MuteUser(ctx, &entities.User{ID: "user3"}, "general", "user2", until)
+		require.Error(t, err)
+		assert.Nil(t, room)
+	})
+
+	t.Run("moderators cannot be muted", func(t *testing.T) {
+		mockRoomRepo.EXPECT().
+			UpdateRoom(ctx, "general", gomock.Any()).
+			DoAndReturn(updateRoom(&entities.Room{ID: "general", ModeratorIDs: []string{"mod1", "mod2"}}))
+
+		room, err := roomUC.MuteUser(ctx, moderator, "general", "mod2", until)
+		require.Error(t, err)
+		assert.Nil(t, room)
+	})
+}
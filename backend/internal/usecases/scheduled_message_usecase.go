@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type ScheduledMessageUseCase interface {
+	ScheduleMessage(ctx context.Context, user *entities.User, roomID, content string, sendAt time.Time, ttl time.Duration) (*entities.ScheduledMessage, error)
+	ListScheduledMessages(ctx context.Context, user *entities.User, roomID string) ([]*entities.ScheduledMessage, error)
+	CancelScheduledMessage(ctx context.Context, user *entities.User, id string) (*entities.ScheduledMessage, error)
+	// GetScheduledMessage returns a message user scheduled.
+	GetScheduledMessage(ctx context.Context, user *entities.User, id string) (*entities.ScheduledMessage, error)
+	Start(ctx context.Context)
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type SchedulerConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	Lease          time.Duration
+	MaxAttempts    int
+	MaxHorizon     time.Duration
+	MaxPendingJobs int
+}
+
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		PollInterval:   5 * time.Second,
+		BatchSize:      50,
+		Lease:          time.Minute,
+		MaxAttempts:    3,
+		MaxHorizon:     365 * 24 * time.Hour,
+		MaxPendingJobs: 100,
+	}
+}
+
+type scheduledMessageUseCase struct {
+	scheduledRepo  repositories.ScheduledMessageRepository
+	messageUseCase MessageUseCase
+	config         SchedulerConfig
+}
+
+func NewScheduledMessageUseCase(scheduledRepo repositories.ScheduledMessageRepository, messageUseCase MessageUseCase, config SchedulerConfig) ScheduledMessageUseCase {
+	return &scheduledMessageUseCase{
+		scheduledRepo:  scheduledRepo,
+		messageUseCase: messageUseCase,
+		config:         config,
+	}
+}
+
+func (uc *scheduledMessageUseCase) ScheduleMessage(ctx context.Context, user *entities.User, roomID, content string, sendAt time.Time, ttl time.Duration) (*entities.ScheduledMessage, error) {
+	if roomID == "" {
+		return nil, invalidArgument("room_id", "ROOM_ID_REQUIRED", "room id is required")
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, invalidArgument("content", "CONTENT_REQUIRED", "message content is required")
+	}
+	if ttl < 0 {
+		return nil, invalidArgument("ttl_seconds", "NEGATIVE_TTL", "message ttl cannot be negative")
+	}
+
+	now := time.Now()
+	if !sendAt.After(now) {
+		return nil, invalidArgument("send_at", "SEND_AT_IN_PAST", "send time must be in the future")
+	}
+	if sendAt.After(now.Add(uc.config.MaxHorizon)) {
+		return nil, invalidArgument("send_at", "SEND_AT_TOO_FAR", "send time is too far in the future")
+	}
+
+	pending, err := uc.scheduledRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) >= uc.config.MaxPendingJobs {
+		return nil, failedPrecondition("TOO_MANY_SCHEDULED_MESSAGES", "too many scheduled messages, cancel some first")
+	}
+
+	scheduled := &entities.ScheduledMessage{
+		ID:         generateID(),
+		UserID:     user.ID,
+		Username:   user.Username,
+		RoomID:     roomID,
+		Content:    content,
+		TTLSeconds: int(ttl.Seconds()),
+		SendAt:     sendAt,
+		Status:     entities.ScheduledPending,
+		CreatedAt:  now,
+	}
+
+	if err := uc.scheduledRepo.Create(ctx, scheduled); err != nil {
+		return nil, err
+	}
+
+	return scheduled, nil
+}
+
+func (uc *scheduledMessageUseCase) ListScheduledMessages(ctx context.Context, user *entities.User, roomID string) ([]*entities.ScheduledMessage, error) {
+	scheduled, err := uc.scheduledRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if roomID == "" {
+		return scheduled, nil
+	}
+
+	var inRoom []*entities.ScheduledMessage
+	for _, s := range scheduled {
+		if s.RoomID == roomID {
+			inRoom = append(inRoom, s)
+		}
+	}
+	return inRoom, nil
+}
+
+func (uc *scheduledMessageUseCase) CancelScheduledMessage(ctx context.Context, user *entities.User, id string) (*entities.ScheduledMessage, error) {
+	if _, err := uc.GetScheduledMessage(ctx, user, id); err != nil {
+		return nil, err
+	}
+
+	return uc.scheduledRepo.Cancel(ctx, id)
+}
+
+func (uc *scheduledMessageUseCase) GetScheduledMessage(ctx context.Context, user *entities.User, id string) (*entities.ScheduledMessage, error) {
+	scheduled, err := uc.scheduledRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound("SCHEDULED_MESSAGE_NOT_FOUND", "scheduled message not found")
+	}
+	if scheduled.UserID != user.ID {
+		return nil, notFound("SCHEDULED_MESSAGE_NOT_FOUND", "scheduled message not found")
+	}
+	return scheduled, nil
+}
+
+func (uc *scheduledMessageUseCase) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(uc.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := uc.DeliverDue(ctx); err != nil {
+				log.Printf("Error delivering scheduled messages: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// DeliverDue posts every due job through the regular send path. Jobs are
+// claimed with a lease so that several server instances, or a restart in
+// the middle of a delivery, never post the same job twice concurrently.
+func (uc *scheduledMessageUseCase) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := uc.scheduledRepo.ListDue(ctx, now, uc.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, job := range due {
+		claimed, err := uc.scheduledRepo.Claim(ctx, job.ID, now, now.Add(uc.config.Lease))
+		if err != nil {
+			log.Printf("Error claiming scheduled message %s: %v", job.ID, err)
+			continue
+		}
+		if claimed == nil {
+			continue
+		}
+
+		if uc.deliver(ctx, claimed) {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+func (uc *scheduledMessageUseCase) deliver(ctx context.Context, job *entities.ScheduledMessage) bool {
+	message, err := uc.messageUseCase.SendMessageWithParams(ctx, entities.MessageCreateParams{
+		UserID:   job.UserID,
+		Username: job.Username,
+		Content:  job.Content,
+		RoomID:   job.RoomID,
+		TTL:      time.Duration(job.TTLSeconds) * time.Second,
+	})
+	if err != nil {
+		log.Printf("Error sending scheduled message %s: %v", job.ID, err)
+		job.Error = err.Error()
+		job.Status = entities.ScheduledPending
+		if job.Attempts >= uc.config.MaxAttempts {
+			job.Status = entities.ScheduledFailed
+		}
+	} else {
+		job.Status = entities.ScheduledSent
+		job.MessageID = message.ID
+		job.Error = ""
+	}
+	job.LeaseUntil = time.Time{}
+
+	if err := uc.scheduledRepo.Update(ctx, job); err != nil {
+		log.Printf("Error updating scheduled message %s: %v", job.ID, err)
+	}
+
+	return job.Status == entities.ScheduledSent
+}
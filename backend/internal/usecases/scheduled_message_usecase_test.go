@@ -0,0 +1,174 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+	ucMocks "chat-app/backend/internal/usecases/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledMessageUseCase_ScheduleMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	scheduledUC := usecases.NewScheduledMessageUseCase(mockScheduledRepo, mockMsgUC, usecases.DefaultSchedulerConfig())
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+	sendAt := time.Now().Add(time.Hour)
+
+	t.Run("successful schedule", func(t *testing.T) {
+		mockScheduledRepo.EXPECT().ListByUser(ctx, "user123").Return(nil, nil)
+		mockScheduledRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, scheduled *entities.ScheduledMessage) error {
+				assert.NotEmpty(t, scheduled.ID)
+				assert.Equal(t, "room123", scheduled.RoomID)
+				assert.Equal(t, entities.ScheduledPending, scheduled.Status)
+				assert.True(t, scheduled.SendAt.Equal(sendAt))
+				return nil
+			})
+
+		scheduled, err := scheduledUC.ScheduleMessage(ctx, user, "room123", "good morning", sendAt, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "mariem", scheduled.Username)
+	})
+
+	t.Run("send time in the past", func(t *testing.T) {
+		scheduled, err := scheduledUC.ScheduleMessage(ctx, user, "room123", "too late", time.Now().Add(-time.Minute), 0)
+		require.Error(t, err)
+		assert.Nil(t, scheduled)
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		scheduled, err := scheduledUC.ScheduleMessage(ctx, user, "room123", "  ", sendAt, 0)
+		require.Error(t, err)
+		assert.Nil(t, scheduled)
+	})
+}
+
+func TestScheduledMessageUseCase_CancelScheduledMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	scheduledUC := usecases.NewScheduledMessageUseCase(mockScheduledRepo, mockMsgUC, usecases.DefaultSchedulerConfig())
+
+	ctx := context.Background()
+	user := &entities.User{ID: "user123", Username: "mariem"}
+
+	t.Run("owner cancels", func(t *testing.T) {
+		mockScheduledRepo.EXPECT().
+			GetByID(ctx, "job1").
+			Return(&entities.ScheduledMessage{ID: "job1", UserID: "user123", Status: entities.ScheduledPending}, nil)
+		mockScheduledRepo.EXPECT().
+			Cancel(ctx, "job1").
+			Return(&entities.ScheduledMessage{ID: "job1", UserID: "user123", Status: entities.ScheduledCancelled}, nil)
+
+		scheduled, err := scheduledUC.CancelScheduledMessage(ctx, user, "job1")
+		require.NoError(t, err)
+		assert.Equal(t, entities.ScheduledCancelled, scheduled.Status)
+	})
+
+	t.Run("other users cannot cancel", func(t *testing.T) {
+		mockScheduledRepo.EXPECT().
+			GetByID(ctx, "job2").
+			Return(&entities.ScheduledMessage{ID: "job2", UserID: "someone-else", Status: entities.ScheduledPending}, nil)
+
+		scheduled, err := scheduledUC.CancelScheduledMessage(ctx, user, "job2")
+		require.Error(t, err)
+		assert.Nil(t, scheduled)
+	})
+}
+
+func TestScheduledMessageUseCase_DeliverDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+	mockMsgUC := ucMocks.NewMockMessageUseCase(ctrl)
+	config := usecases.DefaultSchedulerConfig()
+	config.MaxAttempts = 2
+	scheduledUC := usecases.NewScheduledMessageUseCase(mockScheduledRepo, mockMsgUC, config)
+
+	ctx := context.Background()
+	job := &entities.ScheduledMessage{
+		ID:         "job1",
+		UserID:     "user123",
+		Username:   "mariem",
+		RoomID:     "room123",
+		Content:    "standup time",
+		TTLSeconds: 60,
+		Status:     entities.ScheduledPending,
+	}
+
+	t.Run("delivers through the send path", func(t *testing.T) {
+		claimed := *job
+		claimed.Status = entities.ScheduledSending
+		claimed.Attempts = 1
+
+		mockScheduledRepo.EXPECT().ListDue(ctx, gomock.Any(), config.BatchSize).Return([]*entities.ScheduledMessage{job}, nil)
+		mockScheduledRepo.EXPECT().Claim(ctx, "job1", gomock.Any(), gomock.Any()).Return(&claimed, nil)
+		mockMsgUC.EXPECT().
+			SendMessageWithParams(ctx, entities.MessageCreateParams{
+				UserID:   "user123",
+				Username: "mariem",
+				Content:  "standup time",
+				RoomID:   "room123",
+				TTL:      time.Minute,
+			}).
+			Return(&entities.Message{ID: "msg123"}, nil)
+		mockScheduledRepo.EXPECT().
+			Update(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, scheduled *entities.ScheduledMessage) error {
+				assert.Equal(t, entities.ScheduledSent, scheduled.Status)
+				assert.Equal(t, "msg123", scheduled.MessageID)
+				return nil
+			})
+
+		delivered, err := scheduledUC.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+	})
+
+	t.Run("skips jobs claimed elsewhere", func(t *testing.T) {
+		mockScheduledRepo.EXPECT().ListDue(ctx, gomock.Any(), config.BatchSize).Return([]*entities.ScheduledMessage{job}, nil)
+		mockScheduledRepo.EXPECT().Claim(ctx, "job1", gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		delivered, err := scheduledUC.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("marks job failed after max attempts", func(t *testing.T) {
+		claimed := *job
+		claimed.Status = entities.ScheduledSending
+		claimed.Attempts = 2
+
+		mockScheduledRepo.EXPECT().ListDue(ctx, gomock.Any(), config.BatchSize).Return([]*entities.ScheduledMessage{job}, nil)
+		mockScheduledRepo.EXPECT().Claim(ctx, "job1", gomock.Any(), gomock.Any()).Return(&claimed, nil)
+		mockMsgUC.EXPECT().SendMessageWithParams(ctx, gomock.Any()).Return(nil, assert.AnError)
+		mockScheduledRepo.EXPECT().
+			Update(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, scheduled *entities.ScheduledMessage) error {
+				assert.Equal(t, entities.ScheduledFailed, scheduled.Status)
+				assert.NotEmpty(t, scheduled.Error)
+				return nil
+			})
+
+		delivered, err := scheduledUC.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+}
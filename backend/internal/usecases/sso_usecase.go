@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"log"
+	"strings"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+// IdentityProvider is an OpenID Connect provider that users sign in with
+// using the authorization code flow with PKCE.
+type IdentityProvider interface {
+	Issuer() string
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange redeems an authorization code and returns the claims of the
+	// verified ID token.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (map[string]interface{}, error)
+}
+
+type SSOUseCase interface {
+	// BeginLogin starts a sign-in. The client sends the user to AuthURL and
+	// keeps the request for CompleteLogin.
+	BeginLogin(deviceLabel string) (*entities.SSORequest, error)
+	// CompleteLogin finishes the sign-in of request with the state and code
+	// the provider redirected back with.
+	CompleteLogin(ctx context.Context, request *entities.SSORequest, state, code string, session entities.SessionMetadata) (*entities.AuthToken, error)
+}
+
+type SSOConfig struct {
+	// UsernameClaims are the ID token claims a new user's username is taken
+	// from, in order of preference.
+	UsernameClaims []string
+	// StripEmailDomain turns a username claim like "mariem@example.com"
+	// into "mariem".
+	StripEmailDomain bool
+}
+
+func DefaultSSOConfig() SSOConfig {
+	return SSOConfig{
+		UsernameClaims:   []string{"preferred_username", "email"},
+		StripEmailDomain: true,
+	}
+}
+
+type ssoUseCase struct {
+	provider    IdentityProvider
+	authUseCase AuthUseCase
+	config      SSOConfig
+}
+
+func NewSSOUseCase(provider IdentityProvider, authUseCase AuthUseCase, config SSOConfig) SSOUseCase {
+	return &ssoUseCase{provider: provider, authUseCase: authUseCase, config: config}
+}
+
+func (uc *ssoUseCase) BeginLogin(deviceLabel string) (*entities.SSORequest, error) {
+	request := &entities.SSORequest{
+		State:        randomURLSafe(),
+		Nonce:        randomURLSafe(),
+		CodeVerifier: randomURLSafe(),
+		DeviceLabel:  deviceLabel,
+	}
+	request.AuthURL = uc.provider.AuthCodeURL(request.State, request.Nonce, codeChallenge(request.CodeVerifier))
+	return request, nil
+}
+
+func (uc *ssoUseCase) CompleteLogin(ctx context.Context, request *entities.SSORequest, state, code string, session entities.SessionMetadata) (*entities.AuthToken, error) {
+	if request == nil || state == "" || subtle.ConstantTimeCompare([]byte(request.State), []byte(state)) != 1 {
+		return nil, unauthenticated("SSO_STATE_MISMATCH", "sign-in was not started here or has expired")
+	}
+	if code == "" {
+		return nil, invalidArgument("code", "CODE_REQUIRED", "authorization code is required")
+	}
+
+	claims, err := uc.provider.Exchange(ctx, code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		log.Printf("Error signing in with %s: %v", uc.provider.Issuer(), err)
+		return nil, unauthenticated("SSO_FAILED", "identity provider sign-in failed")
+	}
+
+	subject, _ := claims["sub"].(string)
+	identity := entities.ExternalIdentity{
+		Issuer:   uc.provider.Issuer(),
+		Subject:  subject,
+		Username: uc.username(claims),
+	}
+	if verified, _ := claims["email_verified"].(bool); verified {
+		identity.Email, _ = claims["email"].(string)
+	}
+
+	session.DeviceLabel = request.DeviceLabel
+	return uc.authUseCase.LoginExternal(ctx, identity, session)
+}
+
+func (uc *ssoUseCase) username(claims map[string]interface{}) string {
+	for _, name := range uc.config.UsernameClaims {
+		value, _ := claims[name].(string)
+		value = strings.TrimSpace(value)
+		if uc.config.StripEmailDomain {
+			if at := strings.LastIndex(value, "@"); at >= 0 {
+				value = value[:at]
+			}
+		}
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// randomURLSafe returns 32 random bytes, encoded so that they can be used
+// as a PKCE code verifier.
+func randomURLSafe() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
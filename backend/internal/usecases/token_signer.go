@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SigningKey is an HMAC key for signed access tokens. ID is written to the
+// token's kid header so that the key can be found again after rotation.
+type SigningKey struct {
+	ID     string
+	Secret []byte
+}
+
+// TokenClaims are the claims of a signed access token.
+type TokenClaims struct {
+	Subject   string `json:"sub"`
+	Username  string `json:"name"`
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// minSigningKeyLength is the length of the HS256 digest; shorter keys are
+// easier to guess than the signature.
+const minSigningKeyLength = 32
+
+var errInvalidSignedToken = errors.New("invalid signed token")
+
+// TokenSigner issues and verifies HS256 JSON Web Tokens. It signs with one
+// key and verifies with that key and any retired ones, so a key is rotated
+// by making a new key active and keeping the old one until the tokens it
+// signed have expired.
+type TokenSigner struct {
+	active SigningKey
+	keys   map[string][]byte
+}
+
+func NewTokenSigner(active SigningKey, retired ...SigningKey) (*TokenSigner, error) {
+	s := &TokenSigner{active: active, keys: make(map[string][]byte)}
+	for _, key := range append([]SigningKey{active}, retired...) {
+		if key.ID == "" {
+			return nil, fmt.Errorf("signing key has no id")
+		}
+		if len(key.Secret) < minSigningKeyLength {
+			return nil, fmt.Errorf("signing key %s must be at least %d bytes", key.ID, minSigningKeyLength)
+		}
+		if _, ok := s.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate signing key %s", key.ID)
+		}
+		s.keys[key.ID] = key.Secret
+	}
+	return s, nil
+}
+
+// ParseSigningKeys reads keys written as "id:base64secret" separated by
+// commas. The first key is the active one.
+func ParseSigningKeys(value string) ([]SigningKey, error) {
+	var keys []SigningKey
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("signing key %q is not id:secret", entry)
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %v", id, err)
+		}
+		keys = append(keys, SigningKey{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+func (s *TokenSigner) Sign(claims TokenClaims) (string, error) {
+	header, err := json.Marshal(tokenHeader{Algorithm: "HS256", Type: "JWT", KeyID: s.active.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	return signingInput + "." + encodeSegment(sign(s.active.Secret, signingInput)), nil
+}
+
+// Verify checks the token's signature and expiry and returns its claims.
+func (s *TokenSigner) Verify(token string, now time.Time) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errInvalidSignedToken
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Algorithm != "HS256" {
+		return nil, errInvalidSignedToken
+	}
+	secret, ok := s.keys[header.KeyID]
+	if !ok {
+		return nil, errInvalidSignedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(secret, parts[0]+"."+parts[1])) {
+		return nil, errInvalidSignedToken
+	}
+
+	var claims TokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errInvalidSignedToken
+	}
+	if claims.Subject == "" || !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, errInvalidSignedToken
+	}
+	return &claims, nil
+}
+
+// isSignedToken tells signed tokens apart from opaque ones, which are hex.
+func isSignedToken(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func sign(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
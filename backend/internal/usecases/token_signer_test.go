@@ -0,0 +1,107 @@
+package usecases_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSigner(t *testing.T) {
+	oldKey := usecases.SigningKey{ID: "2024-01", Secret: []byte(strings.Repeat("a", 32))}
+	newKey := usecases.SigningKey{ID: "2024-02", Secret: []byte(strings.Repeat("b", 32))}
+	now := time.Now()
+	claims := usecases.TokenClaims{
+		Subject:   "user123",
+		Username:  "mariem",
+		SessionID: "s1",
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Minute).Unix(),
+	}
+
+	oldSigner, err := usecases.NewTokenSigner(oldKey)
+	require.NoError(t, err)
+	newSigner, err := usecases.NewTokenSigner(newKey, oldKey)
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		token, err := newSigner.Sign(claims)
+		require.NoError(t, err)
+
+		verified, err := newSigner.Verify(token, now)
+		require.NoError(t, err)
+		assert.Equal(t, claims, *verified)
+	})
+
+	t.Run("retired key still verifies", func(t *testing.T) {
+		token, err := oldSigner.Sign(claims)
+		require.NoError(t, err)
+
+		_, err = newSigner.Verify(token, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		token, err := newSigner.Sign(claims)
+		require.NoError(t, err)
+
+		_, err = oldSigner.Verify(token, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := newSigner.Sign(claims)
+		require.NoError(t, err)
+
+		_, err = newSigner.Verify(token, now.Add(2*time.Minute))
+		assert.Error(t, err)
+	})
+
+	t.Run("changed claims", func(t *testing.T) {
+		token, err := newSigner.Sign(claims)
+		require.NoError(t, err)
+
+		forged := claims
+		forged.Subject = "admin"
+		other, err := newSigner.Sign(forged)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		parts[1] = strings.Split(other, ".")[1]
+		_, err = newSigner.Verify(strings.Join(parts, "."), now)
+		assert.Error(t, err)
+	})
+
+	t.Run("alg none", func(t *testing.T) {
+		token, err := newSigner.Sign(claims)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"2024-02"}`))
+		_, err = newSigner.Verify(parts[0]+"."+parts[1]+".", now)
+		assert.Error(t, err)
+	})
+
+	t.Run("short keys are rejected", func(t *testing.T) {
+		_, err := usecases.NewTokenSigner(usecases.SigningKey{ID: "k", Secret: []byte("short")})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseSigningKeys(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32)))
+
+	keys, err := usecases.ParseSigningKeys("new:" + secret + ", old:" + secret)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "new", keys[0].ID)
+	assert.Equal(t, "old", keys[1].ID)
+
+	_, err = usecases.ParseSigningKeys("missing-secret")
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP(t *testing.T) {
+	// The SHA1 key of RFC 6238's test vectors.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	for at, want := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		code, err := totpCode(secret, totpCounter(time.Unix(at, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, want, code, "at %d", at)
+	}
+
+	t.Run("verify allows one step of drift", func(t *testing.T) {
+		now := time.Unix(1111111109, 0)
+		counter, ok := verifyTOTP(secret, "081804", now.Add(totpPeriod))
+		assert.True(t, ok)
+		assert.Equal(t, totpCounter(now), counter)
+
+		_, ok = verifyTOTP(secret, "081804", now.Add(3*totpPeriod))
+		assert.False(t, ok)
+	})
+
+	t.Run("uri", func(t *testing.T) {
+		uri := totpURI("chat-app", "mariem", "ABC")
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/chat-app:mariem?"))
+		assert.Contains(t, uri, "secret=ABC")
+		assert.Contains(t, uri, "issuer=chat-app")
+	})
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+// TypingUseCase fans typing indicators out to the subscribers of a room.
+// Indicators are kept in memory, so they only reach clients connected to
+// the same server instance.
+type TypingUseCase interface {
+	SetTyping(ctx context.Context, user *entities.User, roomID string, typing bool) error
+	Subscribe(ctx context.Context, roomID string) <-chan *entities.TypingEvent
+}
+
+type TypingConfig struct {
+	TTL time.Duration
+	// Buffer is the number of events queued per subscriber. Typing is best
+	// effort: events for a subscriber that is this far behind are dropped.
+	Buffer int
+}
+
+func DefaultTypingConfig() TypingConfig {
+	return TypingConfig{
+		TTL:    6 * time.Second,
+		Buffer: 16,
+	}
+}
+
+type typingUseCase struct {
+	config TypingConfig
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan *entities.TypingEvent]struct{}
+}
+
+func NewTypingUseCase(config TypingConfig) TypingUseCase {
+	return &typingUseCase{
+		config:      config,
+		subscribers: make(map[string]map[chan *entities.TypingEvent]struct{}),
+	}
+}
+
+func (uc *typingUseCase) SetTyping(ctx context.Context, user *entities.User, roomID string, typing bool) error {
+	if roomID == "" {
+		return invalidArgument("room_id", "ROOM_ID_REQUIRED", "room id is required")
+	}
+
+	event := &entities.TypingEvent{
+		RoomID:   roomID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Typing:   typing,
+	}
+	if typing {
+		event.ExpiresAt = time.Now().Add(uc.config.TTL)
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for subscriber := range uc.subscribers[roomID] {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel of the room's typing events that is closed
+// once ctx is done.
+func (uc *typingUseCase) Subscribe(ctx context.Context, roomID string) <-chan *entities.TypingEvent {
+	subscriber := make(chan *entities.TypingEvent, uc.config.Buffer)
+
+	uc.mu.Lock()
+	if uc.subscribers[roomID] == nil {
+		uc.subscribers[roomID] = make(map[chan *entities.TypingEvent]struct{})
+	}
+	uc.subscribers[roomID][subscriber] = struct{}{}
+	uc.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		delete(uc.subscribers[roomID], subscriber)
+		if len(uc.subscribers[roomID]) == 0 {
+			delete(uc.subscribers, roomID)
+		}
+		close(subscriber)
+	}()
+
+	return subscriber
+}
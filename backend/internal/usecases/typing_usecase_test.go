@@ -0,0 +1,32 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypingUseCase(t *testing.T) {
+	typingUC := usecases.NewTypingUseCase(usecases.DefaultTypingConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	events := typingUC.Subscribe(ctx, "general")
+	other := typingUC.Subscribe(ctx, "random")
+
+	require.NoError(t, typingUC.SetTyping(context.Background(), &entities.User{ID: "user1", Username: "mariem"}, "general", true))
+
+	event := <-events
+	assert.Equal(t, "mariem", event.Username)
+	assert.True(t, event.Typing)
+	assert.WithinDuration(t, time.Now().Add(usecases.DefaultTypingConfig().TTL), event.ExpiresAt, time.Second)
+	assert.Len(t, other, 0)
+
+	cancel()
+	_, open := <-events
+	assert.False(t, open)
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"chat-app/backend/internal/domain/entities"
+)
+
+// UsernamePolicy is what new usernames must look like. Usernames are
+// unique regardless of case and of characters that look alike; see
+// entities.UsernameKey.
+type UsernamePolicy struct {
+	MinLength int
+	MaxLength int
+	// AllowUnicode allows letters and digits of any script. Otherwise only
+	// ASCII letters and digits are allowed. "_", "." and "-" are allowed
+	// after the first character either way.
+	AllowUnicode bool
+	// Reserved usernames cannot be registered, nor anything that looks
+	// like them.
+	Reserved []string
+}
+
+func DefaultUsernamePolicy() UsernamePolicy {
+	return UsernamePolicy{
+		MinLength: 3,
+		MaxLength: 32,
+		Reserved: []string{
+			"admin", "administrator", "root", "system", "support", "moderator",
+			"help", "security", "api", "bot", "chat", "null", "undefined",
+		},
+	}
+}
+
+// scripts groups the scripts that are checked for mixing; Japanese is
+// written with Han, Hiragana and Katakana together.
+var scripts = map[*unicode.RangeTable]string{
+	unicode.Latin:    "Latin",
+	unicode.Cyrillic: "Cyrillic",
+	unicode.Greek:    "Greek",
+	unicode.Armenian: "Armenian",
+	unicode.Arabic:   "Arabic",
+	unicode.Hebrew:   "Hebrew",
+	unicode.Han:      "Han",
+	unicode.Hiragana: "Han",
+	unicode.Katakana: "Han",
+	unicode.Hangul:   "Hangul",
+}
+
+// normalize returns username in NFKC form, or an error if the policy does
+// not allow it.
+func (p UsernamePolicy) normalize(field, username string) (string, error) {
+	username = norm.NFKC.String(strings.TrimSpace(username))
+
+	length := utf8.RuneCountInString(username)
+	if length < p.MinLength || length == 0 {
+		return "", invalidArgument(field, "USERNAME_TOO_SHORT", "username must be at least %d characters", max(p.MinLength, 1))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return "", invalidArgument(field, "USERNAME_TOO_LONG", "username must be at most %d characters", p.MaxLength)
+	}
+
+	used := make(map[string]bool)
+	for i, r := range username {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+		case p.AllowUnicode && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) && i > 0):
+		case (r == '_' || r == '.' || r == '-') && i > 0:
+		default:
+			return "", invalidArgument(field, "USERNAME_INVALID_CHARACTERS", "username must start with a letter or digit and contain only letters, digits, \"_\", \".\" and \"-\"")
+		}
+		for table, script := range scripts {
+			if unicode.Is(table, r) {
+				used[script] = true
+			}
+		}
+	}
+	if len(used) > 1 {
+		return "", invalidArgument(field, "USERNAME_MIXED_SCRIPTS", "username must not mix alphabets")
+	}
+
+	key := entities.UsernameKey(username)
+	for _, reserved := range p.Reserved {
+		if key == entities.UsernameKey(reserved) {
+			return "", invalidArgument(field, "USERNAME_RESERVED", "username %q is reserved", username)
+		}
+	}
+	return username, nil
+}
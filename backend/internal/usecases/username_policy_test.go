@@ -0,0 +1,83 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsernameKey(t *testing.T) {
+	key := entities.UsernameKey("alice")
+
+	assert.Equal(t, key, entities.UsernameKey("ALICE"))
+	assert.Equal(t, key, entities.UsernameKey("ａｌｉｃｅ"), "fullwidth")
+	assert.Equal(t, key, entities.UsernameKey("аlice"), "Cyrillic а")
+	assert.Equal(t, key, entities.UsernameKey("a1ice"))
+	assert.Equal(t, entities.UsernameKey("marie"), entities.UsernameKey("rnarie"))
+	assert.NotEqual(t, key, entities.UsernameKey("alicia"))
+}
+
+func TestUsernamePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		username     string
+		allowUnicode bool
+		want         string
+		wantReason   string
+	}{
+		{name: "valid", username: "mariem_b.2", want: "mariem_b.2"},
+		{name: "trimmed and normalized", username: " ｍａｒｉｅｍ ", want: "mariem"},
+		{name: "too short", username: "ab", wantReason: "USERNAME_TOO_SHORT"},
+		{name: "too long", username: "abcdefghijklmnopqrstuvwxyz1234567", wantReason: "USERNAME_TOO_LONG"},
+		{name: "spaces", username: "mariem b", wantReason: "USERNAME_INVALID_CHARACTERS"},
+		{name: "leading punctuation", username: ".mariem", wantReason: "USERNAME_INVALID_CHARACTERS"},
+		{name: "unicode not allowed", username: "мариам", wantReason: "USERNAME_INVALID_CHARACTERS"},
+		{name: "unicode allowed", username: "мариам", allowUnicode: true, want: "мариам"},
+		{name: "mixed scripts", username: "mаriem", allowUnicode: true, wantReason: "USERNAME_MIXED_SCRIPTS"},
+		{name: "reserved", username: "Admin", wantReason: "USERNAME_RESERVED"},
+		{name: "looks reserved", username: "һеӏр", allowUnicode: true, wantReason: "USERNAME_RESERVED"},
+		{name: "looks reserved with digits", username: "r00t", wantReason: "USERNAME_RESERVED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			config := usecases.DefaultAuthConfig()
+			config.UsernamePolicy.AllowUnicode = tt.allowUnicode
+			mockUserRepo := mocks.NewMockUserRepository(ctrl)
+			authUC := usecases.NewAuthUseCase(mockUserRepo, usecases.WithAuthConfig(config))
+
+			if tt.wantReason == "" {
+				mockUserRepo.EXPECT().
+					CreateUser(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entities.User) error {
+						assert.Equal(t, tt.want, user.Username)
+						return nil
+					})
+				mockUserRepo.EXPECT().StoreToken(ctx, gomock.Any()).Return(nil)
+			}
+
+			_, err := authUC.Register(ctx, tt.username, "correct-horse-battery", entities.SessionMetadata{})
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			domainErr, ok := usecases.AsError(err)
+			require.True(t, ok)
+			assert.Equal(t, usecases.KindInvalidArgument, domainErr.Kind)
+			assert.Equal(t, "username", domainErr.Field)
+			assert.Equal(t, tt.wantReason, domainErr.Reason)
+		})
+	}
+}
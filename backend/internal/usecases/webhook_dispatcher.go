@@ -0,0 +1,360 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type WebhookDispatcher interface {
+	Start(ctx context.Context)
+	Refresh(ctx context.Context) error
+	Enqueue(ctx context.Context, message *entities.Message) (int, error)
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type WebhookDispatcherConfig struct {
+	PollInterval    time.Duration
+	RefreshInterval time.Duration
+	BatchSize       int
+	Lease           time.Duration
+	MaxAttempts     int
+	InitialBackoff  time.Duration
+	MaxBackoff      time.Duration
+	Timeout         time.Duration
+	MaxRedirects    int
+	// AllowPrivateNetworks lets deliveries reach loopback and private
+	// addresses and any port. Only tests should set it.
+	AllowPrivateNetworks bool
+	// ReplayWindow bounds how far back created events are enqueued when a
+	// room listener starts and Firestore replays the room's history.
+	ReplayWindow time.Duration
+}
+
+func DefaultWebhookDispatcherConfig() WebhookDispatcherConfig {
+	return WebhookDispatcherConfig{
+		PollInterval:    2 * time.Second,
+		RefreshInterval: 30 * time.Second,
+		BatchSize:       50,
+		Lease:           time.Minute,
+		MaxAttempts:     8,
+		InitialBackoff:  5 * time.Second,
+		MaxBackoff:      time.Hour,
+		Timeout:         10 * time.Second,
+		MaxRedirects:    3,
+		ReplayWindow:    10 * time.Minute,
+	}
+}
+
+type webhookPayload struct {
+	Event     string            `json:"event"`
+	WebhookID string            `json:"webhook_id"`
+	RoomID    string            `json:"room_id"`
+	Message   *entities.Message `json:"message"`
+}
+
+type webhookDispatcher struct {
+	webhookRepo  repositories.WebhookRepository
+	deliveryRepo repositories.WebhookDeliveryRepository
+	messageRepo  repositories.MessageRepository
+	config       WebhookDispatcherConfig
+	client       *http.Client
+	startedAt    time.Time
+
+	mu        sync.Mutex
+	webhooks  map[string][]*entities.Webhook
+	listeners map[string]*roomListener
+}
+
+type roomListener struct {
+	cancel context.CancelFunc
+}
+
+func NewWebhookDispatcher(webhookRepo repositories.WebhookRepository, deliveryRepo repositories.WebhookDeliveryRepository, messageRepo repositories.MessageRepository, config WebhookDispatcherConfig) WebhookDispatcher {
+	return &webhookDispatcher{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		messageRepo:  messageRepo,
+		config:       config,
+		client: newPublicHTTPClient(config.Timeout, config.MaxRedirects, func() bool {
+			return config.AllowPrivateNetworks
+		}),
+		startedAt: time.Now(),
+		webhooks:  make(map[string][]*entities.Webhook),
+		listeners: make(map[string]*roomListener),
+	}
+}
+
+func (d *webhookDispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.config.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := d.Refresh(ctx); err != nil {
+				log.Printf("Error refreshing webhooks: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(d.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := d.DeliverDue(ctx); err != nil {
+					log.Printf("Error delivering webhooks: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Refresh reloads the webhook subscriptions and keeps one room listener
+// running for every room that has at least one webhook.
+func (d *webhookDispatcher) Refresh(ctx context.Context) error {
+	webhooks, err := d.webhookRepo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	byRoom := make(map[string][]*entities.Webhook)
+	for _, webhook := range webhooks {
+		byRoom[webhook.RoomID] = append(byRoom[webhook.RoomID], webhook)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.webhooks = byRoom
+	for roomID, listener := range d.listeners {
+		if _, ok := byRoom[roomID]; !ok {
+			listener.cancel()
+			delete(d.listeners, roomID)
+		}
+	}
+	for roomID := range byRoom {
+		if _, ok := d.listeners[roomID]; ok {
+			continue
+		}
+		listenCtx, cancel := context.WithCancel(ctx)
+		listener := &roomListener{cancel: cancel}
+		d.listeners[roomID] = listener
+		go d.listen(listenCtx, roomID, listener)
+	}
+
+	return nil
+}
+
+// listen runs until the room stream ends. It then unregisters itself so the
+// next refresh starts a fresh listener.
+func (d *webhookDispatcher) listen(ctx context.Context, roomID string, listener *roomListener) {
+	defer func() {
+		d.mu.Lock()
+		if d.listeners[roomID] == listener {
+			delete(d.listeners, roomID)
+		}
+		d.mu.Unlock()
+		listener.cancel()
+	}()
+
+	messages, err := d.messageRepo.StreamByRoomID(ctx, roomID)
+	if err != nil {
+		log.Printf("Error streaming room %s for webhooks: %v", roomID, err)
+		return
+	}
+
+	for message := range messages {
+		if _, err := d.Enqueue(ctx, message); err != nil {
+			log.Printf("Error enqueuing webhooks for message %s: %v", message.ID, err)
+		}
+	}
+}
+
+// Enqueue records a delivery for every webhook of the room that subscribes
+// to the message's event. Delivery IDs are derived from the payload, so the
+// same event seen by several server instances is only delivered once.
+func (d *webhookDispatcher) Enqueue(ctx context.Context, message *entities.Message) (int, error) {
+	d.mu.Lock()
+	webhooks := d.webhooks[message.RoomID]
+	d.mu.Unlock()
+
+	event := WebhookEventName(message.Event)
+	now := time.Now()
+	enqueued := 0
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event) {
+			continue
+		}
+		if message.Event == entities.MessageCreated &&
+			(message.Timestamp.Before(webhook.CreatedAt) || message.Timestamp.Before(d.startedAt.Add(-d.config.ReplayWindow))) {
+			continue
+		}
+
+		body, err := json.Marshal(webhookPayload{
+			Event:     event,
+			WebhookID: webhook.ID,
+			RoomID:    message.RoomID,
+			Message:   message,
+		})
+		if err != nil {
+			return enqueued, err
+		}
+		sum := sha256.Sum256(body)
+
+		created, err := d.deliveryRepo.Create(ctx, &entities.WebhookDelivery{
+			ID:            hex.EncodeToString(sum[:16]),
+			WebhookID:     webhook.ID,
+			RoomID:        message.RoomID,
+			MessageID:     message.ID,
+			Event:         event,
+			Payload:       string(body),
+			Status:        entities.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return enqueued, err
+		}
+		if created {
+			enqueued++
+		}
+	}
+
+	return enqueued, nil
+}
+
+func (d *webhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := d.deliveryRepo.ListDue(ctx, now, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range due {
+		claimed, err := d.deliveryRepo.Claim(ctx, delivery.ID, now, now.Add(d.config.Lease))
+		if err != nil {
+			log.Printf("Error claiming webhook delivery %s: %v", delivery.ID, err)
+			continue
+		}
+		if claimed == nil {
+			continue
+		}
+
+		if d.deliver(ctx, claimed) {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+func (d *webhookDispatcher) deliver(ctx context.Context, delivery *entities.WebhookDelivery) bool {
+	webhook, err := d.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	if err != nil {
+		delivery.Error = "webhook no longer exists"
+		delivery.Status = entities.DeliveryDead
+	} else {
+		delivery.ResponseCode, err = d.post(ctx, webhook, delivery)
+		now := time.Now()
+		switch {
+		case err == nil:
+			delivery.Status = entities.DeliveryDelivered
+			delivery.DeliveredAt = now
+			delivery.Error = ""
+		case delivery.Attempts >= d.config.MaxAttempts:
+			log.Printf("Webhook delivery %s dead-lettered after %d attempts: %v", delivery.ID, delivery.Attempts, err)
+			delivery.Status = entities.DeliveryDead
+			delivery.Error = err.Error()
+		default:
+			delivery.Status = entities.DeliveryPending
+			delivery.Error = err.Error()
+			delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		}
+	}
+	delivery.LeaseUntil = time.Time{}
+
+	if err := d.deliveryRepo.Update(ctx, delivery); err != nil {
+		log.Printf("Error updating webhook delivery %s: %v", delivery.ID, err)
+	}
+
+	return delivery.Status == entities.DeliveryDelivered
+}
+
+func (d *webhookDispatcher) post(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ChatAppWebhook/1.0")
+	req.Header.Set("X-Webhook-Id", webhook.ID)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *webhookDispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.InitialBackoff
+	for i := 1; i < attempts && delay < d.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.config.MaxBackoff {
+		delay = d.config.MaxBackoff
+	}
+	return delay
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature value: an HMAC-SHA256
+// of "<timestamp>.<body>" keyed with the webhook secret. Receivers should
+// recompute it and reject stale timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
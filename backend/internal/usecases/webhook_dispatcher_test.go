@@ -0,0 +1,241 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories/mocks"
+	"chat-app/backend/internal/usecases"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDispatcher_Enqueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockDeliveryRepo := mocks.NewMockWebhookDeliveryRepository(ctrl)
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	dispatcher := usecases.NewWebhookDispatcher(mockWebhookRepo, mockDeliveryRepo, mockMsgRepo, usecases.DefaultWebhookDispatcherConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	createdAt := time.Now().Add(-time.Minute)
+	mockWebhookRepo.EXPECT().ListAll(ctx).Return([]*entities.Webhook{
+		{ID: "all", RoomID: "room123", CreatedAt: createdAt},
+		{ID: "deletes", RoomID: "room123", Events: []string{"message.deleted"}, CreatedAt: createdAt},
+	}, nil)
+	mockMsgRepo.EXPECT().StreamByRoomID(gomock.Any(), "room123").Return(make(chan *entities.Message), nil).AnyTimes()
+	require.NoError(t, dispatcher.Refresh(ctx))
+
+	t.Run("only subscribed webhooks get a delivery", func(t *testing.T) {
+		message := &entities.Message{ID: "msg1", RoomID: "room123", Content: "hi", Timestamp: time.Now(), Event: entities.MessageCreated}
+		mockDeliveryRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery *entities.WebhookDelivery) (bool, error) {
+				assert.Equal(t, "all", delivery.WebhookID)
+				assert.Equal(t, "message.created", delivery.Event)
+				assert.Equal(t, entities.DeliveryPending, delivery.Status)
+
+				var payload map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(delivery.Payload), &payload))
+				assert.Equal(t, "message.created", payload["event"])
+				return true, nil
+			})
+
+		enqueued, err := dispatcher.Enqueue(ctx, message)
+		require.NoError(t, err)
+		assert.Equal(t, 1, enqueued)
+	})
+
+	t.Run("messages older than the webhook are skipped", func(t *testing.T) {
+		message := &entities.Message{ID: "old", RoomID: "room123", Timestamp: createdAt.Add(-time.Hour), Event: entities.MessageCreated}
+
+		enqueued, err := dispatcher.Enqueue(ctx, message)
+		require.NoError(t, err)
+		assert.Equal(t, 0, enqueued)
+	})
+
+	t.Run("duplicate events are not counted", func(t *testing.T) {
+		message := &entities.Message{ID: "msg1", RoomID: "room123", Timestamp: time.Now(), Event: entities.MessageDeleted}
+		mockDeliveryRepo.EXPECT().Create(ctx, gomock.Any()).Return(true, nil)
+		mockDeliveryRepo.EXPECT().Create(ctx, gomock.Any()).Return(false, nil)
+
+		enqueued, err := dispatcher.Enqueue(ctx, message)
+		require.NoError(t, err)
+		assert.Equal(t, 1, enqueued)
+	})
+}
+
+func TestWebhookDispatcher_DeliverDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const secret = "0123456789abcdef"
+	status := http.StatusOK
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockDeliveryRepo := mocks.NewMockWebhookDeliveryRepository(ctrl)
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	config := usecases.DefaultWebhookDispatcherConfig()
+	config.MaxAttempts = 3
+	config.AllowPrivateNetworks = true
+	dispatcher := usecases.NewWebhookDispatcher(mockWebhookRepo, mockDeliveryRepo, mockMsgRepo, config)
+
+	ctx := context.Background()
+	webhook := &entities.Webhook{ID: "hook1", RoomID: "room123", URL: server.URL, Secret: secret}
+	delivery := &entities.WebhookDelivery{
+		ID:        "delivery1",
+		WebhookID: "hook1",
+		Event:     "message.created",
+		Payload:   `{"event":"message.created"}`,
+		Status:    entities.DeliveryPending,
+	}
+
+	expectClaim := func(attempts int) {
+		claimed := *delivery
+		claimed.Status = entities.DeliverySending
+		claimed.Attempts = attempts
+		mockDeliveryRepo.EXPECT().ListDue(ctx, gomock.Any(), config.BatchSize).Return([]*entities.WebhookDelivery{delivery}, nil)
+		mockDeliveryRepo.EXPECT().Claim(ctx, "delivery1", gomock.Any(), gomock.Any()).Return(&claimed, nil)
+		mockWebhookRepo.EXPECT().GetByID(ctx, "hook1").Return(webhook, nil)
+	}
+
+	t.Run("posts a signed payload", func(t *testing.T) {
+		expectClaim(1)
+		mockDeliveryRepo.EXPECT().
+			Update(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, d *entities.WebhookDelivery) error {
+				assert.Equal(t, entities.DeliveryDelivered, d.Status)
+				assert.Equal(t, http.StatusOK, d.ResponseCode)
+				return nil
+			})
+
+		delivered, err := dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+
+		require.NotNil(t, received)
+		assert.Equal(t, `{"event":"message.created"}`, string(receivedBody))
+		assert.Equal(t, "delivery1", received.Header.Get("X-Webhook-Delivery"))
+		timestamp, err := strconv.ParseInt(received.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, usecases.SignWebhookPayload(secret, timestamp, receivedBody), received.Header.Get("X-Webhook-Signature"))
+	})
+
+	t.Run("failed attempts back off exponentially", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		expectClaim(2)
+		mockDeliveryRepo.EXPECT().
+			Update(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, d *entities.WebhookDelivery) error {
+				assert.Equal(t, entities.DeliveryPending, d.Status)
+				assert.Equal(t, http.StatusInternalServerError, d.ResponseCode)
+				assert.WithinDuration(t, time.Now().Add(2*config.InitialBackoff), d.NextAttemptAt, time.Second)
+				return nil
+			})
+
+		delivered, err := dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("last failed attempt becomes a dead letter", func(t *testing.T) {
+		status = http.StatusBadGateway
+		expectClaim(3)
+		mockDeliveryRepo.EXPECT().
+			Update(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, d *entities.WebhookDelivery) error {
+				assert.Equal(t, entities.DeliveryDead, d.Status)
+				assert.NotEmpty(t, d.Error)
+				return nil
+			})
+
+		delivered, err := dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("private addresses are refused by default", func(t *testing.T) {
+		received = nil
+		guarded := usecases.NewWebhookDispatcher(mockWebhookRepo, mockDeliveryRepo, mockMsgRepo, usecases.DefaultWebhookDispatcherConfig())
+		claimed := *delivery
+		claimed.Status = entities.DeliverySending
+		claimed.Attempts = 1
+		mockDeliveryRepo.EXPECT().ListDue(ctx, gomock.Any(), gomock.Any()).Return([]*entities.WebhookDelivery{delivery}, nil)
+		mockDeliveryRepo.EXPECT().Claim(ctx, "delivery1", gomock.Any(), gomock.Any()).Return(&claimed, nil)
+		mockWebhookRepo.EXPECT().GetByID(ctx, "hook1").Return(webhook, nil)
+		mockDeliveryRepo.EXPECT().
+			Update(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, d *entities.WebhookDelivery) error {
+				assert.Equal(t, entities.DeliveryPending, d.Status)
+				assert.Contains(t, d.Error, "not allowed")
+				return nil
+			})
+
+		delivered, err := guarded.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Nil(t, received)
+	})
+}
+
+func TestWebhookUseCase_CreateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockDeliveryRepo := mocks.NewMockWebhookDeliveryRepository(ctrl)
+	mockRoomRepo := mocks.NewMockRoomRepository(ctrl)
+	mockMsgRepo := mocks.NewMockMessageRepository(ctrl)
+	roomUC := usecases.NewRoomUseCase(mockRoomRepo, mockMsgRepo, usecases.DefaultRoomConfig())
+	webhookUC := usecases.NewWebhookUseCase(mockWebhookRepo, mockDeliveryRepo, roomUC)
+
+	ctx := context.Background()
+	moderator := &entities.User{ID: "mod1"}
+	room := &entities.Room{ID: "room123", ModeratorIDs: []string{"mod1"}}
+
+	t.Run("moderator creates a webhook with a generated secret", func(t *testing.T) {
+		mockRoomRepo.EXPECT().GetRoom(ctx, "room123").Return(room, nil)
+		mockWebhookRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		webhook, err := webhookUC.CreateWebhook(ctx, moderator, "room123", "https://hooks.example.com/chat", "", []string{"message.created"})
+		require.NoError(t, err)
+		assert.Len(t, webhook.Secret, 64)
+	})
+
+	t.Run("unknown events are rejected", func(t *testing.T) {
+		mockRoomRepo.EXPECT().GetRoom(ctx, "room123").Return(room, nil)
+
+		webhook, err := webhookUC.CreateWebhook(ctx, moderator, "room123", "https://hooks.example.com/chat", "", []string{"message.edited"})
+		require.Error(t, err)
+		assert.Nil(t, webhook)
+	})
+
+	t.Run("only moderators", func(t *testing.T) {
+		mockRoomRepo.EXPECT().GetRoom(ctx, "room123").Return(room, nil)
+
+		webhook, err := webhookUC.CreateWebhook(ctx, &entities.User{ID: "user2"}, "room123", "https://hooks.example.com/chat", "", nil)
+		require.Error(t, err)
+		assert.Nil(t, webhook)
+	})
+}
@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"chat-app/backend/internal/domain/entities"
+	"chat-app/backend/internal/domain/repositories"
+)
+
+type WebhookUseCase interface {
+	CreateWebhook(ctx context.Context, user *entities.User, roomID, rawURL, secret string, events []string) (*entities.Webhook, error)
+	ListWebhooks(ctx context.Context, user *entities.User, roomID string) ([]*entities.Webhook, error)
+	DeleteWebhook(ctx context.Context, user *entities.User, webhookID string) error
+	ListDeliveries(ctx context.Context, user *entities.User, webhookID string, limit int) ([]*entities.WebhookDelivery, error)
+	// GetWebhook returns a webhook of a room that user moderates.
+	GetWebhook(ctx context.Context, user *entities.User, webhookID string) (*entities.Webhook, error)
+}
+
+var webhookEvents = []entities.MessageEvent{
+	entities.MessageCreated,
+	entities.MessageUpdated,
+	entities.MessageDeleted,
+	entities.MessageExpired,
+	entities.MessagePinned,
+	entities.MessageUnpinned,
+	entities.PollUpdated,
+	entities.PollClosed,
+}
+
+// WebhookEventName is the event type sent to webhooks, e.g. "message.created".
+func WebhookEventName(event entities.MessageEvent) string {
+	return "message." + string(event)
+}
+
+type webhookUseCase struct {
+	webhookRepo  repositories.WebhookRepository
+	deliveryRepo repositories.WebhookDeliveryRepository
+	roomUseCase  RoomUseCase
+}
+
+func NewWebhookUseCase(webhookRepo repositories.WebhookRepository, deliveryRepo repositories.WebhookDeliveryRepository, roomUseCase RoomUseCase) WebhookUseCase {
+	return &webhookUseCase{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		roomUseCase:  roomUseCase,
+	}
+}
+
+// CreateWebhook returns the webhook with its secret. The secret is generated
+// when none is given and is not returned again afterwards.
+func (uc *webhookUseCase) CreateWebhook(ctx context.Context, user *entities.User, roomID, rawURL, secret string, events []string) (*entities.Webhook, error) {
+	if err := uc.requireModerator(ctx, user, roomID); err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, invalidArgument("url", "INVALID_WEBHOOK_URL", "webhook url must be an absolute http or https url")
+	}
+
+	for _, event := range events {
+		if !isWebhookEvent(event) {
+			return nil, invalidArgument("events", "UNKNOWN_WEBHOOK_EVENT", "unknown webhook event %q", event)
+		}
+	}
+
+	if secret == "" {
+		secret = generateToken()
+	} else if len(secret) < 16 {
+		return nil, invalidArgument("secret", "WEBHOOK_SECRET_TOO_SHORT", "webhook secret must be at least 16 characters")
+	}
+
+	webhook := &entities.Webhook{
+		ID:        generateID(),
+		RoomID:    roomID,
+		URL:       parsed.String(),
+		Secret:    secret,
+		Events:    events,
+		CreatedBy: user.ID,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (uc *webhookUseCase) ListWebhooks(ctx context.Context, user *entities.User, roomID string) ([]*entities.Webhook, error) {
+	if err := uc.requireModerator(ctx, user, roomID); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := uc.webhookRepo.ListByRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (uc *webhookUseCase) DeleteWebhook(ctx context.Context, user *entities.User, webhookID string) error {
+	webhook, err := uc.GetWebhook(ctx, user, webhookID)
+	if err != nil {
+		return err
+	}
+	return uc.webhookRepo.Delete(ctx, webhook.ID)
+}
+
+func (uc *webhookUseCase) ListDeliveries(ctx context.Context, user *entities.User, webhookID string, limit int) ([]*entities.WebhookDelivery, error) {
+	webhook, err := uc.GetWebhook(ctx, user, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return uc.deliveryRepo.ListByWebhook(ctx, webhook.ID, limit)
+}
+
+func (uc *webhookUseCase) GetWebhook(ctx context.Context, user *entities.User, webhookID string) (*entities.Webhook, error) {
+	webhook, err := uc.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, notFound("WEBHOOK_NOT_FOUND", "webhook not found")
+	}
+	if err := uc.requireModerator(ctx, user, webhook.RoomID); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (uc *webhookUseCase) requireModerator(ctx context.Context, user *entities.User, roomID string) error {
+	room, err := uc.roomUseCase.GetRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if !room.IsModerator(user.ID) {
+		return permissionDenied("MODERATOR_REQUIRED", "only room moderators can manage webhooks")
+	}
+	return nil
+}
+
+func isWebhookEvent(name string) bool {
+	for _, event := range webhookEvents {
+		if WebhookEventName(event) == name {
+			return true
+		}
+	}
+	return false
+}